@@ -15,6 +15,15 @@ func init() {
 	config.InitEncryptionVars()
 	config.InitBucketVars()
 	config.InitEmailVars()
+	config.InitGeocoderVars()
+	config.InitPublicVars()
+	config.InitInvoiceVars()
+	config.InitLoyaltyVars()
+	config.InitReceivableVars()
+	config.InitExpenseVars()
+	config.InitAttendanceVars()
+	config.InitCashShiftVars()
+	config.InitSaleVars()
 
 	middleware.InitLogger()
 	middleware.InitValidator()
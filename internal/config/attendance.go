@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+var (
+	// Jarak maksimal (meter) posisi karyawan dari titik outlet saat clock-in/out
+	ATTENDANCE_GEOFENCE_METERS float64
+	// Keterlambatan sampai batas ini (menit) belum dihitung terlambat
+	ATTENDANCE_LATE_GRACE_MINUTES int
+	// Kelebihan jam kerja di bawah batas ini (menit) tidak dihitung lembur
+	ATTENDANCE_OVERTIME_MIN_MINUTES int
+	// Lembur paling lama (menit) yang dicatat otomatis; lebih dari itu dicek supervisor
+	ATTENDANCE_OVERTIME_MAX_MINUTES int
+	// Clock-in paling cepat sekian menit sebelum jadwal shift dimulai
+	ATTENDANCE_EARLY_CLOCK_IN_MINUTES int
+	// Masa berlaku QR absensi (menit) sejak ditampilkan di outlet
+	ATTENDANCE_QR_TTL_MINUTES int
+)
+
+func InitAttendanceVars() {
+	ATTENDANCE_GEOFENCE_METERS, _ = strconv.ParseFloat(os.Getenv("ATTENDANCE_GEOFENCE_METERS"+Prefix), 64)
+	if ATTENDANCE_GEOFENCE_METERS <= 0 {
+		ATTENDANCE_GEOFENCE_METERS = 100
+	}
+	ATTENDANCE_LATE_GRACE_MINUTES, _ = strconv.Atoi(os.Getenv("ATTENDANCE_LATE_GRACE_MINUTES" + Prefix))
+	if ATTENDANCE_LATE_GRACE_MINUTES < 0 {
+		ATTENDANCE_LATE_GRACE_MINUTES = 0
+	}
+	ATTENDANCE_OVERTIME_MIN_MINUTES, _ = strconv.Atoi(os.Getenv("ATTENDANCE_OVERTIME_MIN_MINUTES" + Prefix))
+	if ATTENDANCE_OVERTIME_MIN_MINUTES < 1 {
+		ATTENDANCE_OVERTIME_MIN_MINUTES = 30
+	}
+	ATTENDANCE_OVERTIME_MAX_MINUTES, _ = strconv.Atoi(os.Getenv("ATTENDANCE_OVERTIME_MAX_MINUTES" + Prefix))
+	if ATTENDANCE_OVERTIME_MAX_MINUTES < 1 {
+		ATTENDANCE_OVERTIME_MAX_MINUTES = 240
+	}
+	ATTENDANCE_EARLY_CLOCK_IN_MINUTES, _ = strconv.Atoi(os.Getenv("ATTENDANCE_EARLY_CLOCK_IN_MINUTES" + Prefix))
+	if ATTENDANCE_EARLY_CLOCK_IN_MINUTES < 1 {
+		ATTENDANCE_EARLY_CLOCK_IN_MINUTES = 60
+	}
+	ATTENDANCE_QR_TTL_MINUTES, _ = strconv.Atoi(os.Getenv("ATTENDANCE_QR_TTL_MINUTES" + Prefix))
+	if ATTENDANCE_QR_TTL_MINUTES < 1 {
+		ATTENDANCE_QR_TTL_MINUTES = 5
+	}
+}
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+var (
+	// Selisih kas (rupiah, plus/minus) di atas nilai ini ditandai untuk diperiksa manajer
+	CASH_SHIFT_DISCREPANCY_TOLERANCE int64
+)
+
+func InitCashShiftVars() {
+	CASH_SHIFT_DISCREPANCY_TOLERANCE, _ = strconv.ParseInt(os.Getenv("CASH_SHIFT_DISCREPANCY_TOLERANCE"+Prefix), 10, 64)
+	if CASH_SHIFT_DISCREPANCY_TOLERANCE < 0 {
+		CASH_SHIFT_DISCREPANCY_TOLERANCE = 0
+	}
+}
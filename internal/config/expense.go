@@ -0,0 +1,18 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+var (
+	// Pengeluaran di atas nominal ini (rupiah) harus disetujui supervisor
+	EXPENSE_APPROVAL_THRESHOLD int64
+)
+
+func InitExpenseVars() {
+	EXPENSE_APPROVAL_THRESHOLD, _ = strconv.ParseInt(os.Getenv("EXPENSE_APPROVAL_THRESHOLD"+Prefix), 10, 64)
+	if EXPENSE_APPROVAL_THRESHOLD < 0 {
+		EXPENSE_APPROVAL_THRESHOLD = 0
+	}
+}
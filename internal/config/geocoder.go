@@ -0,0 +1,18 @@
+package config
+
+import (
+	"os"
+)
+
+var (
+	// Geocoding alamat outlet: "nominatim", "stub" atau kosong (tidak aktif)
+	GEOCODER_PROVIDER   string
+	GEOCODER_URL        string
+	GEOCODER_USER_AGENT string
+)
+
+func InitGeocoderVars() {
+	GEOCODER_PROVIDER = os.Getenv("GEOCODER_PROVIDER" + Prefix)
+	GEOCODER_URL = os.Getenv("GEOCODER_URL" + Prefix)
+	GEOCODER_USER_AGENT = os.Getenv("GEOCODER_USER_AGENT" + Prefix)
+}
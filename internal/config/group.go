@@ -0,0 +1,8 @@
+package config
+
+// Nilai kolom users.group yang dipakai untuk otorisasi endpoint
+const (
+	GROUP_USER           int = 1
+	GROUP_ADMIN          int = 2
+	GROUP_REGION_MANAGER int = 3
+)
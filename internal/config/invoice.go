@@ -0,0 +1,18 @@
+package config
+
+import (
+	"os"
+)
+
+var (
+	// Identitas penjual yang dicetak di invoice (nama PT, alamat, NPWP)
+	INVOICE_COMPANY_NAME    string
+	INVOICE_COMPANY_ADDRESS string
+	INVOICE_COMPANY_TAX_ID  string
+)
+
+func InitInvoiceVars() {
+	INVOICE_COMPANY_NAME = os.Getenv("INVOICE_COMPANY_NAME" + Prefix)
+	INVOICE_COMPANY_ADDRESS = os.Getenv("INVOICE_COMPANY_ADDRESS" + Prefix)
+	INVOICE_COMPANY_TAX_ID = os.Getenv("INVOICE_COMPANY_TAX_ID" + Prefix)
+}
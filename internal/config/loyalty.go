@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+var (
+	// Nilai satu poin dalam rupiah saat ditukar di kasir
+	LOYALTY_POINT_VALUE int64
+	// Poin hangus setelah sekian bulan sejak didapat
+	LOYALTY_POINT_EXPIRY_MONTHS int
+	// Periode belanja bergulir (bulan) untuk menentukan tier
+	LOYALTY_TIER_WINDOW_MONTHS int
+)
+
+func InitLoyaltyVars() {
+	LOYALTY_POINT_VALUE, _ = strconv.ParseInt(os.Getenv("LOYALTY_POINT_VALUE"+Prefix), 10, 64)
+	if LOYALTY_POINT_VALUE < 1 {
+		LOYALTY_POINT_VALUE = 1
+	}
+	LOYALTY_POINT_EXPIRY_MONTHS, _ = strconv.Atoi(os.Getenv("LOYALTY_POINT_EXPIRY_MONTHS" + Prefix))
+	if LOYALTY_POINT_EXPIRY_MONTHS < 1 {
+		LOYALTY_POINT_EXPIRY_MONTHS = 12
+	}
+	LOYALTY_TIER_WINDOW_MONTHS, _ = strconv.Atoi(os.Getenv("LOYALTY_TIER_WINDOW_MONTHS" + Prefix))
+	if LOYALTY_TIER_WINDOW_MONTHS < 1 {
+		LOYALTY_TIER_WINDOW_MONTHS = 12
+	}
+}
@@ -0,0 +1,15 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+var (
+	// Alamat frontend publik, dipakai untuk link di QR code outlet
+	PUBLIC_BASE_URL string
+)
+
+func InitPublicVars() {
+	PUBLIC_BASE_URL = strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"+Prefix), "/")
+}
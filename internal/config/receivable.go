@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+var (
+	// Tempo pembayaran (hari) untuk pelanggan yang tidak punya tempo sendiri
+	RECEIVABLE_DEFAULT_TERM_DAYS int
+	// Jarak minimal (hari) antar email pengingat ke pelanggan yang sama
+	RECEIVABLE_REMINDER_INTERVAL_DAYS int
+)
+
+func InitReceivableVars() {
+	RECEIVABLE_DEFAULT_TERM_DAYS, _ = strconv.Atoi(os.Getenv("RECEIVABLE_DEFAULT_TERM_DAYS" + Prefix))
+	if RECEIVABLE_DEFAULT_TERM_DAYS < 1 {
+		RECEIVABLE_DEFAULT_TERM_DAYS = 30
+	}
+	RECEIVABLE_REMINDER_INTERVAL_DAYS, _ = strconv.Atoi(os.Getenv("RECEIVABLE_REMINDER_INTERVAL_DAYS" + Prefix))
+	if RECEIVABLE_REMINDER_INTERVAL_DAYS < 1 {
+		RECEIVABLE_REMINDER_INTERVAL_DAYS = 7
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+var (
+	// soldAt dari POS boleh lebih maju sekian menit dari jam server (selisih jam perangkat)
+	SALE_CLOCK_SKEW_MINUTES int
+	// Transaksi offline paling lama sekian jam sebelum diunggah
+	SALE_OFFLINE_MAX_HOURS int
+)
+
+func InitSaleVars() {
+	var err error
+	SALE_CLOCK_SKEW_MINUTES, err = strconv.Atoi(os.Getenv("SALE_CLOCK_SKEW_MINUTES" + Prefix))
+	if err != nil || SALE_CLOCK_SKEW_MINUTES < 0 {
+		SALE_CLOCK_SKEW_MINUTES = 5
+	}
+	SALE_OFFLINE_MAX_HOURS, _ = strconv.Atoi(os.Getenv("SALE_OFFLINE_MAX_HOURS" + Prefix))
+	if SALE_OFFLINE_MAX_HOURS < 1 {
+		SALE_OFFLINE_MAX_HOURS = 72
+	}
+}
@@ -1,11 +1,14 @@
 package controller
 
 import (
+    "errors"
     "net/http"
     "time"
 
     "github.com/gin-gonic/gin"
     "BackendFramework/internal/database"
+    "BackendFramework/internal/model"
+    "BackendFramework/internal/service"
 )
 
 // Response struct
@@ -57,3 +60,50 @@ func GetUserRegistrations(c *gin.Context) {
         "generated_at":          time.Now(),
     })
 }
+
+// GetNewCustomers - GET /v1/analytics/new-customers?outletId=, sumber metrik "Pelanggan" dan "Pelanggan Baru"
+func GetNewCustomers(c *gin.Context) {
+    var params model.CustomerAnalyticsQuery
+    if !bindQueryAndValidate(c, &params) {
+        return
+    }
+
+    analytics, err := service.NewAnalyticsService().GetCustomerAnalytics(params, outletScope(c))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "monthly_new_customers": analytics.MonthlyNewCustomers,
+        "total_customers":       analytics.TotalCustomers,
+        "current_month":         analytics.CurrentMonth,
+        "growth_rate":           analytics.GrowthRate,
+        "generated_at":          time.Now(),
+    })
+}
+
+// GetNetRevenue - GET /v1/analytics/net-revenue?from=&to=&outletId=
+// Sumber metrik "Omset", "Pengeluaran" dan "Pendapatan Bersih" di dashboard
+func GetNetRevenue(c *gin.Context) {
+    var params model.NetRevenueQuery
+    if !bindQueryAndValidate(c, &params) {
+        return
+    }
+
+    summary, err := service.NewExpenseService().GetNetRevenue(params, outletScope(c))
+    if err != nil {
+        if errors.Is(err, service.ErrInvalidExpensePeriod) {
+            c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    summary,
+        "message": "Net revenue fetched successfully",
+    })
+}
@@ -0,0 +1,357 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type AttendanceController struct {
+	attendanceService *service.AttendanceService
+}
+
+func NewAttendanceController() *AttendanceController {
+	return &AttendanceController{
+		attendanceService: service.NewAttendanceService(),
+	}
+}
+
+// GetOutletAttendanceQR - GET /v1/outlets/:id/attendance-qr
+func (ctrl *AttendanceController) GetOutletAttendanceQR(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid outlet ID")
+	if !ok {
+		return
+	}
+
+	path, expiresAt, err := ctrl.attendanceService.AttendanceQRFile(id)
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+	defer os.Remove(path)
+
+	// QR berganti terus; layar di outlet memuat ulang saat kedaluwarsa
+	c.Header("Cache-Control", "no-store")
+	c.Header("Expires", expiresAt.UTC().Format(http.TimeFormat))
+	c.FileAttachment(path, fmt.Sprintf("attendance-qr-outlet-%d.png", id))
+}
+
+// ---------------- SCHEDULE ----------------
+
+// GetSchedules - GET /v1/attendance/schedules?outletId=&employeeId=&from=&to=
+func (ctrl *AttendanceController) GetSchedules(c *gin.Context) {
+	var params model.ShiftScheduleQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	schedules, err := ctrl.attendanceService.GetSchedules(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch shift schedules",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    schedules,
+		"message": "Shift schedules fetched successfully",
+		"count":   len(schedules),
+	})
+}
+
+// CreateSchedules - POST /v1/attendance/schedules
+func (ctrl *AttendanceController) CreateSchedules(c *gin.Context) {
+	var req model.ShiftScheduleBatchRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	schedules, err := ctrl.attendanceService.CreateSchedules(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    schedules,
+		"message": "Shift schedules created successfully",
+		"count":   len(schedules),
+	})
+}
+
+// UpdateSchedule - PUT /v1/attendance/schedules/:id
+func (ctrl *AttendanceController) UpdateSchedule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid shift schedule ID")
+	if !ok {
+		return
+	}
+
+	var req model.ShiftScheduleRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	schedule, err := ctrl.attendanceService.UpdateSchedule(id, req, outletScope(c))
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    schedule,
+		"message": "Shift schedule updated successfully",
+	})
+}
+
+// DeleteSchedule - DELETE /v1/attendance/schedules/:id
+func (ctrl *AttendanceController) DeleteSchedule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid shift schedule ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.attendanceService.DeleteSchedule(id, outletScope(c)); err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Shift schedule deleted successfully",
+	})
+}
+
+// ---------------- CLOCK IN / OUT ----------------
+
+// ClockIn - POST /v1/attendance/clock-in
+func (ctrl *AttendanceController) ClockIn(c *gin.Context) {
+	var req model.ClockRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	attendance, err := ctrl.attendanceService.ClockIn(c.GetString("userID"), req)
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    attendance,
+		"message": "Clocked in successfully",
+	})
+}
+
+// ClockOut - POST /v1/attendance/clock-out
+func (ctrl *AttendanceController) ClockOut(c *gin.Context) {
+	var req model.ClockRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	attendance, err := ctrl.attendanceService.ClockOut(c.GetString("userID"), req)
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    attendance,
+		"message": "Clocked out successfully",
+	})
+}
+
+// CloseAttendance - POST /v1/attendance/:id/close, untuk karyawan yang lupa clock-out
+func (ctrl *AttendanceController) CloseAttendance(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid attendance ID")
+	if !ok {
+		return
+	}
+	var req model.AttendanceCloseRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	attendance, err := ctrl.attendanceService.CloseAttendance(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    attendance,
+		"message": "Attendance closed successfully",
+	})
+}
+
+// ReviewOvertime - POST /v1/attendance/:id/overtime-review
+func (ctrl *AttendanceController) ReviewOvertime(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid attendance ID")
+	if !ok {
+		return
+	}
+	var req model.AttendanceOvertimeReviewRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	attendance, err := ctrl.attendanceService.ReviewOvertime(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    attendance,
+		"message": "Attendance overtime reviewed successfully",
+	})
+}
+
+// ---------------- LIST & REPORT ----------------
+
+// GetMyAttendance - GET /v1/attendance/me?from=&to=
+func (ctrl *AttendanceController) GetMyAttendance(c *gin.Context) {
+	var params model.AttendanceQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	attendances, schedules, err := ctrl.attendanceService.MyAttendance(c.GetString("userID"), params)
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"attendances": attendances,
+			"schedules":   schedules,
+		},
+		"message": "Attendance fetched successfully",
+	})
+}
+
+// GetAttendances - GET /v1/attendance?outletId=&employeeId=&from=&to=
+func (ctrl *AttendanceController) GetAttendances(c *gin.Context) {
+	var params model.AttendanceQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	attendances, err := ctrl.attendanceService.GetAttendances(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch attendance",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    attendances,
+		"message": "Attendance fetched successfully",
+		"count":   len(attendances),
+	})
+}
+
+// GetReport - GET /v1/attendance/reports?outletId=&employeeId=&from=&to=
+func (ctrl *AttendanceController) GetReport(c *gin.Context) {
+	var params model.AttendanceQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	report, err := ctrl.attendanceService.GetReport(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to build attendance report",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+		"message": "Attendance report generated successfully",
+	})
+}
+
+// SyncOvertime - POST /v1/attendance/overtime/sync?outletId=&employeeId=&from=&to=
+func (ctrl *AttendanceController) SyncOvertime(c *gin.Context) {
+	var params model.AttendanceQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.attendanceService.SyncOvertime(params, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+		"message": "Attendance overtime sent to payroll",
+	})
+}
+
+func respondAttendanceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrShiftNotFound),
+		errors.Is(err, service.ErrAttendanceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrShiftExists),
+		errors.Is(err, service.ErrShiftAttended),
+		errors.Is(err, service.ErrAttendanceAlreadyClockedIn),
+		errors.Is(err, service.ErrAttendanceNotClockedIn),
+		errors.Is(err, service.ErrAttendanceClosed),
+		errors.Is(err, service.ErrAttendanceNoReview):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrAttendanceQRInvalid),
+		errors.Is(err, service.ErrAttendanceQRExpired),
+		errors.Is(err, service.ErrAttendanceOutsideGeofence),
+		errors.Is(err, service.ErrAttendanceOutletMismatch),
+		errors.Is(err, service.ErrAttendanceOutletLocation),
+		errors.Is(err, service.ErrAttendanceClockOutTime),
+		errors.Is(err, service.ErrAttendanceOvertimeTooLong):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrAttendanceNoEmployee):
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondEmployeeError(c, err)
+	}
+}
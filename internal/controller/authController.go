@@ -35,7 +35,6 @@ func Register(c *gin.Context) {
 		Address             string `json:"address" binding:"required"` 
 		Password            string `json:"password" binding:"required"`
 		ConfirmPassword     string `json:"confirmPassword" binding:"required"`
-		Group               int    `json:"group"`
 		AgreeTerms          bool   `json:"agreeTerms"`
 		SubscribeNewsletter bool   `json:"subscribeNewsletter"`
 	}
@@ -94,10 +93,6 @@ func Register(c *gin.Context) {
 	}
 	fmt.Printf("Email check passed\n")
 
-	if registerBody.Group == 0 {
-		registerBody.Group = 1
-	}
-
 	fmt.Printf("Creating user data\n")
 	
 	userData := bson.M{
@@ -108,7 +103,7 @@ func Register(c *gin.Context) {
 		"phone":                registerBody.Phone,
 		"address":              registerBody.Address, 
 		"password":             registerBody.Password,
-		"group":                registerBody.Group,
+		"group":                config.GROUP_USER,
 		"isAktif":              "active",
 		"agreeTerms":           registerBody.AgreeTerms,
 		"subscribeNewsletter":  registerBody.SubscribeNewsletter,
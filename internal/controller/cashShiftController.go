@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type CashShiftController struct {
+	cashShiftService *service.CashShiftService
+}
+
+func NewCashShiftController() *CashShiftController {
+	return &CashShiftController{
+		cashShiftService: service.NewCashShiftService(),
+	}
+}
+
+// GetShifts - GET /v1/cash-shifts?outletId=&cashierId=&status=&flagged=&from=&to=&page=&pageSize=
+func (ctrl *CashShiftController) GetShifts(c *gin.Context) {
+	var params model.CashShiftListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.cashShiftService.GetShifts(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch cash shifts",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Shifts,
+		"message":    "Cash shifts fetched successfully",
+		"count":      len(result.Shifts),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetCurrentShift - GET /v1/cash-shifts/current
+func (ctrl *CashShiftController) GetCurrentShift(c *gin.Context) {
+	shift, err := ctrl.cashShiftService.CurrentShift(c.GetString("userID"), outletScope(c))
+	if err != nil {
+		respondCashShiftError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    shift,
+		"message": "Cash shift fetched successfully",
+	})
+}
+
+// GetShift - GET /v1/cash-shifts/:id
+func (ctrl *CashShiftController) GetShift(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid cash shift ID")
+	if !ok {
+		return
+	}
+
+	shift, err := ctrl.cashShiftService.GetShift(id, outletScope(c))
+	if err != nil {
+		respondCashShiftError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    shift,
+		"message": "Cash shift fetched successfully",
+	})
+}
+
+// OpenShift - POST /v1/cash-shifts
+func (ctrl *CashShiftController) OpenShift(c *gin.Context) {
+	var req model.CashShiftOpenRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	shift, err := ctrl.cashShiftService.OpenShift(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondCashShiftError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    shift,
+		"message": "Cash shift opened successfully",
+	})
+}
+
+// AddMovement - POST /v1/cash-shifts/:id/movements
+func (ctrl *CashShiftController) AddMovement(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid cash shift ID")
+	if !ok {
+		return
+	}
+
+	var req model.CashShiftMovementRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	shift, err := ctrl.cashShiftService.AddMovement(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondCashShiftError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    shift,
+		"message": "Cash movement recorded successfully",
+	})
+}
+
+// CloseShift - POST /v1/cash-shifts/:id/close
+func (ctrl *CashShiftController) CloseShift(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid cash shift ID")
+	if !ok {
+		return
+	}
+
+	var req model.CashShiftCloseRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	shift, err := ctrl.cashShiftService.CloseShift(id, req, outletScope(c))
+	if err != nil {
+		respondCashShiftError(c, err)
+		return
+	}
+
+	message := "Cash shift closed successfully"
+	if shift.DiscrepancyFlagged {
+		message = "Cash shift closed with a discrepancy flagged for review"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    shift,
+		"message": message,
+	})
+}
+
+// SignOff - POST /v1/cash-shifts/:id/sign-off
+func (ctrl *CashShiftController) SignOff(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid cash shift ID")
+	if !ok {
+		return
+	}
+
+	// Catatan opsional kecuali selisihnya ditandai, body boleh kosong
+	var req model.CashShiftSignOffRequest
+	if c.Request.ContentLength > 0 && !bindAndValidate(c, &req) {
+		return
+	}
+
+	shift, err := ctrl.cashShiftService.SignOff(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondCashShiftError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    shift,
+		"message": "Cash shift signed off successfully",
+	})
+}
+
+// GetShiftReport - GET /v1/cash-shifts/:id/report
+func (ctrl *CashShiftController) GetShiftReport(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid cash shift ID")
+	if !ok {
+		return
+	}
+
+	pdf, shift, err := ctrl.cashShiftService.ReportPDF(id, outletScope(c))
+	if err != nil {
+		respondCashShiftError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="`+shift.Number+`.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// ---------------- DAILY CLOSING ----------------
+
+// GetDailyClosings - GET /v1/cash-shifts/daily-closings?outletId=&from=&to=
+func (ctrl *CashShiftController) GetDailyClosings(c *gin.Context) {
+	var params model.DailyClosingQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	closings, err := ctrl.cashShiftService.GetDailyClosings(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch daily closings",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    closings,
+		"message": "Daily closings fetched successfully",
+		"count":   len(closings),
+	})
+}
+
+// CloseDay - POST /v1/cash-shifts/daily-closings
+func (ctrl *CashShiftController) CloseDay(c *gin.Context) {
+	var req model.DailyClosingRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	closing, err := ctrl.cashShiftService.CloseDay(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondCashShiftError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    closing,
+		"message": "Business day closed successfully",
+	})
+}
+
+func respondCashShiftError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCashShiftNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrCashShiftAlreadyOpen),
+		errors.Is(err, service.ErrCashShiftNotOpen),
+		errors.Is(err, service.ErrCashShiftClosed),
+		errors.Is(err, service.ErrCashShiftNotClosed),
+		errors.Is(err, service.ErrDailyClosingExists),
+		errors.Is(err, service.ErrDailyClosingUnreconciled):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrCashShiftSelfSignOff):
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrCashShiftNoteRequired),
+		errors.Is(err, service.ErrDailyClosingFuture):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondInventoryError(c, err)
+	}
+}
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type CustomerController struct {
+	customerService *service.CustomerService
+}
+
+func NewCustomerController() *CustomerController {
+	return &CustomerController{
+		customerService: service.NewCustomerService(),
+	}
+}
+
+// GetCustomers - GET /v1/customers?search=&tag=&page=&pageSize=
+func (ctrl *CustomerController) GetCustomers(c *gin.Context) {
+	var params model.CustomerListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.customerService.GetCustomers(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch customers",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Customers,
+		"message":    "Customers fetched successfully",
+		"count":      len(result.Customers),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetCustomer - GET /v1/customers/:id
+func (ctrl *CustomerController) GetCustomer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	customer, err := ctrl.customerService.GetCustomer(id)
+	if err != nil {
+		respondCustomerError(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    customer,
+		"message": "Customer fetched successfully",
+	})
+}
+
+// GetCustomerSales - GET /v1/customers/:id/sales?page=&pageSize=
+func (ctrl *CustomerController) GetCustomerSales(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	var params model.CustomerSaleQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.customerService.GetCustomerSales(id, params, outletScope(c))
+	if err != nil {
+		respondCustomerError(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Sales,
+		"message":    "Purchase history fetched successfully",
+		"count":      len(result.Sales),
+		"pagination": result.Pagination,
+	})
+}
+
+// CreateCustomer - POST /v1/customers
+func (ctrl *CustomerController) CreateCustomer(c *gin.Context) {
+	var req model.CustomerRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	customer, err := ctrl.customerService.CreateCustomer(req, c.GetString("userID"))
+	if err != nil {
+		respondCustomerError(c, err, customer)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    customer,
+		"message": "Customer created successfully",
+	})
+}
+
+// UpdateCustomer - PUT /v1/customers/:id
+func (ctrl *CustomerController) UpdateCustomer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	var req model.CustomerRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	customer, err := ctrl.customerService.UpdateCustomer(id, req)
+	if err != nil {
+		respondCustomerError(c, err, customer)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    customer,
+		"message": "Customer updated successfully",
+	})
+}
+
+// DeleteCustomer - DELETE /v1/customers/:id
+func (ctrl *CustomerController) DeleteCustomer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.customerService.DeleteCustomer(id); err != nil {
+		respondCustomerError(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Customer deleted successfully",
+	})
+}
+
+// MergeCustomer - POST /v1/customers/:id/merge
+func (ctrl *CustomerController) MergeCustomer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	var req model.CustomerMergeRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	customer, err := ctrl.customerService.MergeCustomers(id, req.SourceID)
+	if err != nil {
+		respondCustomerError(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    customer,
+		"message": "Customers merged successfully",
+	})
+}
+
+// respondCustomerError ikut mengirim pelanggan yang sudah ada pada konflik duplikat
+func respondCustomerError(c *gin.Context, err error, existing *model.Customer) {
+	switch {
+	case errors.Is(err, service.ErrCustomerNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrCustomerDuplicate):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"data":    existing,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrCustomerMergeSelf):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondSaleError(c, err)
+	}
+}
@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type EmployeeController struct {
+	employeeService *service.EmployeeService
+}
+
+func NewEmployeeController() *EmployeeController {
+	return &EmployeeController{
+		employeeService: service.NewEmployeeService(),
+	}
+}
+
+// GetEmployees - GET /v1/employees?search=&outletId=&active=&page=&pageSize=
+func (ctrl *EmployeeController) GetEmployees(c *gin.Context) {
+	var params model.EmployeeListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.employeeService.GetEmployees(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch employees",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Employees,
+		"message":    "Employees fetched successfully",
+		"count":      len(result.Employees),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetEmployee - GET /v1/employees/:id
+func (ctrl *EmployeeController) GetEmployee(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid employee ID")
+	if !ok {
+		return
+	}
+
+	employee, err := ctrl.employeeService.GetEmployee(id, outletScope(c))
+	if err != nil {
+		respondEmployeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    employee,
+		"message": "Employee fetched successfully",
+	})
+}
+
+// CreateEmployee - POST /v1/employees
+func (ctrl *EmployeeController) CreateEmployee(c *gin.Context) {
+	var req model.EmployeeRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	employee, err := ctrl.employeeService.CreateEmployee(req, outletScope(c))
+	if err != nil {
+		respondEmployeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    employee,
+		"message": "Employee created successfully",
+	})
+}
+
+// UpdateEmployee - PUT /v1/employees/:id
+func (ctrl *EmployeeController) UpdateEmployee(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid employee ID")
+	if !ok {
+		return
+	}
+
+	var req model.EmployeeRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	employee, err := ctrl.employeeService.UpdateEmployee(id, req, outletScope(c))
+	if err != nil {
+		respondEmployeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    employee,
+		"message": "Employee updated successfully",
+	})
+}
+
+// SetComponents - PUT /v1/employees/:id/components
+func (ctrl *EmployeeController) SetComponents(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid employee ID")
+	if !ok {
+		return
+	}
+
+	var req model.EmployeeComponentsRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	employee, err := ctrl.employeeService.SetComponents(id, req, outletScope(c))
+	if err != nil {
+		respondEmployeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    employee,
+		"message": "Salary components updated successfully",
+	})
+}
+
+// GetOvertime - GET /v1/employees/:id/overtime?from=&to=
+func (ctrl *EmployeeController) GetOvertime(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid employee ID")
+	if !ok {
+		return
+	}
+
+	var params model.EmployeeOvertimeQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	entries, err := ctrl.employeeService.GetOvertime(id, params, outletScope(c))
+	if err != nil {
+		respondEmployeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+		"message": "Overtime fetched successfully",
+		"count":   len(entries),
+	})
+}
+
+// AddOvertime - POST /v1/employees/:id/overtime
+func (ctrl *EmployeeController) AddOvertime(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid employee ID")
+	if !ok {
+		return
+	}
+
+	var req model.EmployeeOvertimeRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	entry, err := ctrl.employeeService.AddOvertime(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondEmployeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    entry,
+		"message": "Overtime recorded successfully",
+	})
+}
+
+// DeleteOvertime - DELETE /v1/employees/:id/overtime/:overtimeId
+func (ctrl *EmployeeController) DeleteOvertime(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid employee ID")
+	if !ok {
+		return
+	}
+	overtimeID, ok := parseIDParam(c, "overtimeId", "Invalid overtime ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.employeeService.DeleteOvertime(id, overtimeID, outletScope(c)); err != nil {
+		respondEmployeeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Overtime deleted successfully",
+	})
+}
+
+func respondEmployeeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrEmployeeNotFound),
+		errors.Is(err, service.ErrEmployeeOvertimeMissing):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrEmployeeCodeExists),
+		errors.Is(err, service.ErrEmployeeUserLinked),
+		errors.Is(err, service.ErrEmployeeOvertimePaid):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrEmployeeInactive),
+		errors.Is(err, service.ErrEmployeeEndDate):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondInventoryError(c, err)
+	}
+}
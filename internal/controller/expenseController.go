@@ -0,0 +1,358 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+const expenseReceiptMaxSize = 5 * 1024 * 1024 // 5 MB
+
+type ExpenseController struct {
+	expenseService *service.ExpenseService
+}
+
+func NewExpenseController() *ExpenseController {
+	return &ExpenseController{
+		expenseService: service.NewExpenseService(),
+	}
+}
+
+// ---------------- CATEGORY ----------------
+
+// GetCategories - GET /v1/expenses/categories?active=true
+func (ctrl *ExpenseController) GetCategories(c *gin.Context) {
+	categories, err := ctrl.expenseService.GetCategories(c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch expense categories",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    categories,
+		"message": "Expense categories fetched successfully",
+		"count":   len(categories),
+	})
+}
+
+// CreateCategory - POST /v1/expenses/categories
+func (ctrl *ExpenseController) CreateCategory(c *gin.Context) {
+	var req model.ExpenseCategoryRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	category, err := ctrl.expenseService.CreateCategory(req)
+	if err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Expense category created successfully",
+	})
+}
+
+// UpdateCategory - PUT /v1/expenses/categories/:id
+func (ctrl *ExpenseController) UpdateCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid expense category ID")
+	if !ok {
+		return
+	}
+
+	var req model.ExpenseCategoryRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	category, err := ctrl.expenseService.UpdateCategory(id, req)
+	if err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Expense category updated successfully",
+	})
+}
+
+// DeleteCategory - DELETE /v1/expenses/categories/:id
+func (ctrl *ExpenseController) DeleteCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid expense category ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.expenseService.DeleteCategory(id); err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Expense category deleted successfully",
+	})
+}
+
+// ---------------- EXPENSE ----------------
+
+// GetExpenses - GET /v1/expenses?outletId=&categoryId=&status=&from=&to=&page=&pageSize=
+func (ctrl *ExpenseController) GetExpenses(c *gin.Context) {
+	var params model.ExpenseListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.expenseService.GetExpenses(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch expenses",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"data":        result.Expenses,
+		"message":     "Expenses fetched successfully",
+		"count":       len(result.Expenses),
+		"totalAmount": result.TotalAmount,
+		"pagination":  result.Pagination,
+	})
+}
+
+// GetExpense - GET /v1/expenses/:id
+func (ctrl *ExpenseController) GetExpense(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid expense ID")
+	if !ok {
+		return
+	}
+
+	expense, err := ctrl.expenseService.GetExpense(id, outletScope(c))
+	if err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    expense,
+		"message": "Expense fetched successfully",
+	})
+}
+
+// CreateExpense - POST /v1/expenses
+func (ctrl *ExpenseController) CreateExpense(c *gin.Context) {
+	var req model.ExpenseRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	expense, err := ctrl.expenseService.CreateExpense(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    expense,
+		"message": "Expense recorded successfully",
+	})
+}
+
+// UpdateExpense - PUT /v1/expenses/:id
+func (ctrl *ExpenseController) UpdateExpense(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid expense ID")
+	if !ok {
+		return
+	}
+
+	var req model.ExpenseRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	expense, err := ctrl.expenseService.UpdateExpense(id, req, outletScope(c))
+	if err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    expense,
+		"message": "Expense updated successfully",
+	})
+}
+
+// DeleteExpense - DELETE /v1/expenses/:id
+func (ctrl *ExpenseController) DeleteExpense(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid expense ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.expenseService.DeleteExpense(id, outletScope(c)); err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Expense deleted successfully",
+	})
+}
+
+// UploadReceipt - POST /v1/expenses/:id/receipt (multipart: file)
+func (ctrl *ExpenseController) UploadReceipt(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid expense ID")
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "file is required",
+		})
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ok, errMsg := middleware.ValidateFile(expenseReceiptMaxSize, file.Size, ext, []string{".jpg", ".jpeg", ".png", ".webp", ".pdf"}); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   errMsg,
+		})
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to read uploaded file",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer src.Close()
+	data, err := io.ReadAll(src)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to read uploaded file",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	expense, err := ctrl.expenseService.UploadReceipt(id, data, ext, outletScope(c))
+	if err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    expense,
+		"message": "Receipt uploaded successfully",
+	})
+}
+
+// ApproveExpense - POST /v1/expenses/:id/approve
+func (ctrl *ExpenseController) ApproveExpense(c *gin.Context) {
+	ctrl.reviewExpense(c, true)
+}
+
+// RejectExpense - POST /v1/expenses/:id/reject
+func (ctrl *ExpenseController) RejectExpense(c *gin.Context) {
+	ctrl.reviewExpense(c, false)
+}
+
+func (ctrl *ExpenseController) reviewExpense(c *gin.Context, approve bool) {
+	id, ok := parseIDParam(c, "id", "Invalid expense ID")
+	if !ok {
+		return
+	}
+
+	// Catatan review opsional, body boleh kosong
+	var req model.ExpenseReviewRequest
+	if c.Request.ContentLength > 0 && !bindAndValidate(c, &req) {
+		return
+	}
+
+	expense, err := ctrl.expenseService.ReviewExpense(id, approve, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondExpenseError(c, err)
+		return
+	}
+
+	message := "Expense rejected"
+	if approve {
+		message = "Expense approved"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    expense,
+		"message": message,
+	})
+}
+
+func respondExpenseError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrExpenseNotFound),
+		errors.Is(err, service.ErrExpenseCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrExpenseCategoryExists),
+		errors.Is(err, service.ErrExpenseCategoryInUse),
+		errors.Is(err, service.ErrExpenseReviewed),
+		errors.Is(err, service.ErrExpenseNotPending):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrExpenseSelfReview):
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrExpenseCategoryInactive),
+		errors.Is(err, service.ErrExpenseReceiptTypeInvalid):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrExpenseReceiptUploadFailed):
+		c.JSON(http.StatusBadGateway, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondInventoryError(c, err)
+	}
+}
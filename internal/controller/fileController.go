@@ -1,15 +1,18 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"BackendFramework/internal/model"
 	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/service"
 	"BackendFramework/internal/thirdparty"
 )
 
@@ -52,8 +55,10 @@ func UploadFile(c *gin.Context) {
 		})
 		return
 	}
-	// Upload the file to S3
-	s3Url, err := thirdparty.UploadFileBucket(localFilePath, "Akademik/test"+ext)
+	// Upload the file to S3, one folder per user so the files can be exported / erased later
+	userID := c.GetString("userID")
+	objectKey := fmt.Sprintf("Akademik/%s/%d%s", userID, time.Now().UnixNano(), ext)
+	s3Url, err := thirdparty.UploadFileBucket(localFilePath, objectKey)
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{
 			"code" :http.StatusInternalServerError,
@@ -62,6 +67,20 @@ func UploadFile(c *gin.Context) {
 		return
 	}
 
+	status, _ := service.InsertUserFile(&model.UserFile{
+		UserID:      userID,
+		FileName:    file.Filename,
+		Description: userInput.FileDescription,
+		ObjectKey:   objectKey,
+	})
+	if status == false {
+		c.JSON(http.StatusOK, gin.H{
+			"code" :http.StatusInternalServerError,
+			"error": "failed to save file record",
+		})
+		return
+	}
+
 	// Delete the local file after successful upload
 	if err := os.Remove(localFilePath); err != nil {
 		c.JSON(http.StatusOK, gin.H{
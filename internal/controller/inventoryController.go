@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type InventoryController struct {
+	inventoryService *service.InventoryService
+}
+
+func NewInventoryController() *InventoryController {
+	return &InventoryController{
+		inventoryService: service.NewInventoryService(),
+	}
+}
+
+// GetStock - GET /v1/inventory/stock?outletId=&productId=&asOf=&lowOnly=
+func (ctrl *InventoryController) GetStock(c *gin.Context) {
+	var params model.StockQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	stock, err := ctrl.inventoryService.GetStock(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch stock",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stock,
+		"message": "Stock fetched successfully",
+		"count":   len(stock),
+	})
+}
+
+// GetLowStock - GET /v1/inventory/stock/low?outletId=
+func (ctrl *InventoryController) GetLowStock(c *gin.Context) {
+	var params model.StockQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+	params.LowOnly = true
+
+	stock, err := ctrl.inventoryService.GetStock(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch low stock",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stock,
+		"message": "Low stock fetched successfully",
+		"count":   len(stock),
+	})
+}
+
+// GetMovements - GET /v1/inventory/movements?outletId=&productId=&type=&from=&to=&page=&pageSize=
+func (ctrl *InventoryController) GetMovements(c *gin.Context) {
+	var params model.StockMovementQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.inventoryService.GetMovements(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch stock movements",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Movements,
+		"message":    "Stock movements fetched successfully",
+		"count":      len(result.Movements),
+		"pagination": result.Pagination,
+	})
+}
+
+// RecordMovements - POST /v1/inventory/movements
+func (ctrl *InventoryController) RecordMovements(c *gin.Context) {
+	var req model.StockMovementRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	movements, err := ctrl.inventoryService.RecordMovements(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    movements,
+		"message": "Stock movements recorded successfully",
+		"count":   len(movements),
+	})
+}
+
+// SetThreshold - PUT /v1/inventory/thresholds
+func (ctrl *InventoryController) SetThreshold(c *gin.Context) {
+	var req model.StockThresholdRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	level, err := ctrl.inventoryService.SetThreshold(req, outletScope(c))
+	if err != nil {
+		respondInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    level,
+		"message": "Low-stock threshold saved successfully",
+	})
+}
+
+// RebuildStockLevels - POST /v1/inventory/rebuild?outletId= (tanpa outletId = semua outlet)
+func (ctrl *InventoryController) RebuildStockLevels(c *gin.Context) {
+	outletID, _ := strconv.ParseUint(c.Query("outletId"), 10, 32)
+
+	rebuilt, err := ctrl.inventoryService.RebuildStockLevels(uint(outletID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to rebuild stock levels",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Stock levels rebuilt from the ledger",
+		"count":   rebuilt,
+	})
+}
+
+func respondInventoryError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrStockOutletScope),
+		errors.Is(err, service.ErrProductNotFound),
+		errors.Is(err, service.ErrProductVariantNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrStockQuantitySign),
+		errors.Is(err, service.ErrStockMovementFuture),
+		errors.Is(err, service.ErrStockMovementBeforeCount):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, service.ErrStockAdjustmentForbidden):
+		status = http.StatusForbidden
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
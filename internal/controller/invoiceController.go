@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type InvoiceController struct {
+	invoiceService *service.InvoiceService
+}
+
+func NewInvoiceController() *InvoiceController {
+	return &InvoiceController{
+		invoiceService: service.NewInvoiceService(),
+	}
+}
+
+// GetInvoices - GET /v1/invoices?outletId=&year=&search=&page=&pageSize=
+func (ctrl *InvoiceController) GetInvoices(c *gin.Context) {
+	var params model.InvoiceListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.invoiceService.GetInvoices(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch invoices",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Invoices,
+		"message":    "Invoices fetched successfully",
+		"count":      len(result.Invoices),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetInvoice - GET /v1/invoices/:id
+func (ctrl *InvoiceController) GetInvoice(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid invoice ID")
+	if !ok {
+		return
+	}
+
+	invoice, err := ctrl.invoiceService.GetInvoice(id, outletScope(c))
+	if err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    invoice,
+		"message": "Invoice fetched successfully",
+	})
+}
+
+// CreateInvoice - POST /v1/invoices
+func (ctrl *InvoiceController) CreateInvoice(c *gin.Context) {
+	var req model.InvoiceRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	invoice, err := ctrl.invoiceService.CreateInvoice(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    invoice,
+		"message": "Invoice issued successfully",
+	})
+}
+
+// GetInvoicePDF - GET /v1/invoices/:id/pdf (dirender di memori, tanpa file sementara)
+func (ctrl *InvoiceController) GetInvoicePDF(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid invoice ID")
+	if !ok {
+		return
+	}
+
+	pdf, invoice, err := ctrl.invoiceService.InvoicePDF(id, outletScope(c))
+	if err != nil {
+		respondInvoiceError(c, err)
+		return
+	}
+
+	filename := strings.ReplaceAll(invoice.Number, "/", "-") + ".pdf"
+	c.Header("Content-Disposition", `inline; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func respondInvoiceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvoiceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrInvoiceExists),
+		errors.Is(err, service.ErrInvoiceSaleVoided):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrInvoiceDueDateEarly):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondSaleError(c, err)
+	}
+}
@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type LoyaltyController struct {
+	loyaltyService *service.LoyaltyService
+}
+
+func NewLoyaltyController() *LoyaltyController {
+	return &LoyaltyController{
+		loyaltyService: service.NewLoyaltyService(),
+	}
+}
+
+// GetRules - GET /v1/loyalty/rules
+func (ctrl *LoyaltyController) GetRules(c *gin.Context) {
+	rules, err := ctrl.loyaltyService.GetRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch earn rules",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rules,
+		"message": "Earn rules fetched successfully",
+		"count":   len(rules),
+	})
+}
+
+// CreateRule - POST /v1/loyalty/rules
+func (ctrl *LoyaltyController) CreateRule(c *gin.Context) {
+	var req model.LoyaltyEarnRuleRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	rule, err := ctrl.loyaltyService.CreateRule(req)
+	if err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    rule,
+		"message": "Earn rule created successfully",
+	})
+}
+
+// UpdateRule - PUT /v1/loyalty/rules/:id
+func (ctrl *LoyaltyController) UpdateRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid rule ID")
+	if !ok {
+		return
+	}
+
+	var req model.LoyaltyEarnRuleRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	rule, err := ctrl.loyaltyService.UpdateRule(id, req)
+	if err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+		"message": "Earn rule updated successfully",
+	})
+}
+
+// DeleteRule - DELETE /v1/loyalty/rules/:id
+func (ctrl *LoyaltyController) DeleteRule(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid rule ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.loyaltyService.DeleteRule(id); err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Earn rule deleted successfully",
+	})
+}
+
+// GetTiers - GET /v1/loyalty/tiers
+func (ctrl *LoyaltyController) GetTiers(c *gin.Context) {
+	tiers, err := ctrl.loyaltyService.GetTiers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch tiers",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tiers,
+		"message": "Tiers fetched successfully",
+		"count":   len(tiers),
+	})
+}
+
+// CreateTier - POST /v1/loyalty/tiers
+func (ctrl *LoyaltyController) CreateTier(c *gin.Context) {
+	var req model.LoyaltyTierRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	tier, err := ctrl.loyaltyService.CreateTier(req)
+	if err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    tier,
+		"message": "Tier created successfully",
+	})
+}
+
+// UpdateTier - PUT /v1/loyalty/tiers/:id
+func (ctrl *LoyaltyController) UpdateTier(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid tier ID")
+	if !ok {
+		return
+	}
+
+	var req model.LoyaltyTierRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	tier, err := ctrl.loyaltyService.UpdateTier(id, req)
+	if err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tier,
+		"message": "Tier updated successfully",
+	})
+}
+
+// DeleteTier - DELETE /v1/loyalty/tiers/:id
+func (ctrl *LoyaltyController) DeleteTier(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid tier ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.loyaltyService.DeleteTier(id); err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tier deleted successfully",
+	})
+}
+
+// RunMaintenance - POST /v1/loyalty/maintenance
+// Menghanguskan poin dan mengevaluasi ulang tier; dijalankan harian oleh scheduler.
+func (ctrl *LoyaltyController) RunMaintenance(c *gin.Context) {
+	result, err := ctrl.loyaltyService.RunMaintenance(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to run loyalty maintenance",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+		"message": "Loyalty maintenance completed",
+	})
+}
+
+// GetCustomerLoyalty - GET /v1/customers/:id/loyalty
+func (ctrl *LoyaltyController) GetCustomerLoyalty(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	summary, err := ctrl.loyaltyService.GetSummary(id)
+	if err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+		"message": "Loyalty summary fetched successfully",
+	})
+}
+
+// GetCustomerLedger - GET /v1/customers/:id/loyalty/ledger?type=&page=&pageSize=
+func (ctrl *LoyaltyController) GetCustomerLedger(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	var params model.LoyaltyLedgerQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.loyaltyService.GetLedger(id, params)
+	if err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Transactions,
+		"message":    "Loyalty ledger fetched successfully",
+		"count":      len(result.Transactions),
+		"pagination": result.Pagination,
+	})
+}
+
+// AdjustPoints - POST /v1/customers/:id/loyalty/adjustments
+func (ctrl *LoyaltyController) AdjustPoints(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	var req model.LoyaltyAdjustmentRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	entry, err := ctrl.loyaltyService.Adjust(id, req, c.GetString("userID"))
+	if err != nil {
+		respondLoyaltyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    entry,
+		"message": "Points adjusted successfully",
+	})
+}
+
+func respondLoyaltyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrLoyaltyRuleNotFound),
+		errors.Is(err, service.ErrLoyaltyTierNotFound),
+		errors.Is(err, service.ErrProductCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrLoyaltyRuleConflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondCustomerError(c, err, nil)
+	}
+}
@@ -2,28 +2,116 @@
 package controller
 
 import (
+    "errors"
     "net/http"
+    "os"
+    "path/filepath"
     "strconv"
+    "strings"
+    "time"
     "BackendFramework/internal/middleware"
     "BackendFramework/internal/model"
     "BackendFramework/internal/service"
+    "BackendFramework/internal/thirdparty"
     "github.com/gin-gonic/gin"
 )
 
+const outletImportMaxSize = 5 * 1024 * 1024 // 5 MB
+
 type OutletController struct {
     outletService *service.OutletService
+    regionService *service.RegionService
 }
 
 func NewOutletController() *OutletController {
     return &OutletController{
         outletService: service.NewOutletService(),
+        regionService: service.NewRegionService(),
+    }
+}
+
+// ResolveOutletScope stores the regions the caller may see as "outletScope".
+// It must run after JWTAuthMiddleware.
+func (ctrl *OutletController) ResolveOutletScope() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        scope, err := ctrl.regionService.ScopeForUser(c.GetString("userID"))
+        if err != nil {
+            c.JSON(http.StatusForbidden, gin.H{
+                "success": false,
+                "error": "Failed to resolve outlet access",
+                "details": err.Error(),
+            })
+            c.Abort()
+            return
+        }
+        c.Set("outletScope", scope)
+        c.Next()
+    }
+}
+
+// RequireOutletAccess answers 404 for an :id outside of the caller's regions,
+// so region managers cannot probe outlets they don't manage.
+func (ctrl *OutletController) RequireOutletAccess() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "success": false,
+                "error": "Invalid outlet ID",
+            })
+            c.Abort()
+            return
+        }
+
+        allowed, err := ctrl.outletService.OutletInScope(uint(id), outletScope(c))
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "success": false,
+                "error": err.Error(),
+            })
+            c.Abort()
+            return
+        }
+        if !allowed {
+            c.JSON(http.StatusNotFound, gin.H{
+                "success": false,
+                "error": "outlet not found",
+            })
+            c.Abort()
+            return
+        }
+        c.Next()
     }
 }
 
+func outletScope(c *gin.Context) model.OutletScope {
+    if scope, exists := c.Get("outletScope"); exists {
+        return scope.(model.OutletScope)
+    }
+    return model.OutletScope{}
+}
+
 func (ctrl *OutletController) GetOutlets(c *gin.Context) {
-    search := c.Query("search")
-    
-    outlets, err := ctrl.outletService.GetAllOutlets(search)
+    var params model.OutletListQuery
+    if err := c.ShouldBindQuery(&params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid query parameters",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if err := middleware.Validator.Struct(params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    result, err := ctrl.outletService.GetAllOutlets(params, outletScope(c))
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "success": false,
@@ -35,9 +123,10 @@ func (ctrl *OutletController) GetOutlets(c *gin.Context) {
 
     c.JSON(http.StatusOK, gin.H{
         "success": true,
-        "data": outlets,
+        "data": result.Outlets,
         "message": "Outlets fetched successfully",
-        "count": len(outlets),
+        "count": len(result.Outlets),
+        "pagination": result.Pagination,
     })
 }
 
@@ -65,6 +154,7 @@ func (ctrl *OutletController) GetOutlet(c *gin.Context) {
         return
     }
 
+    c.Header("ETag", versionETag(outlet.Version))
     c.JSON(http.StatusOK, gin.H{
         "success": true,
         "data": outlet,
@@ -93,7 +183,15 @@ func (ctrl *OutletController) CreateOutlet(c *gin.Context) {
         return
     }
 
-    outlet, err := ctrl.outletService.CreateOutlet(req)
+    if !outletScope(c).Allows(req.RegionID) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "success": false,
+            "error": service.ErrOutOfScope.Error(),
+        })
+        return
+    }
+
+    outlet, err := ctrl.outletService.CreateOutlet(req, c.GetString("userID"))
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "success": false,
@@ -103,6 +201,7 @@ func (ctrl *OutletController) CreateOutlet(c *gin.Context) {
         return
     }
 
+    c.Header("ETag", versionETag(outlet.Version))
     c.JSON(http.StatusCreated, gin.H{
         "success": true,
         "data": outlet,
@@ -111,6 +210,7 @@ func (ctrl *OutletController) CreateOutlet(c *gin.Context) {
 }
 
 
+// UpdateOutlet - PUT /api/v1/outlets/:id (If-Match wajib)
 func (ctrl *OutletController) UpdateOutlet(c *gin.Context) {
     idStr := c.Param("id")
     id, err := strconv.ParseUint(idStr, 10, 32)
@@ -122,6 +222,15 @@ func (ctrl *OutletController) UpdateOutlet(c *gin.Context) {
         return
     }
 
+    version, err := ifMatchVersion(c)
+    if err != nil {
+        c.JSON(ifMatchStatus(err), gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
+
     var req model.OutletRequest
     if err := c.ShouldBindJSON(&req); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{
@@ -141,11 +250,58 @@ func (ctrl *OutletController) UpdateOutlet(c *gin.Context) {
         return
     }
 
-    outlet, err := ctrl.outletService.UpdateOutlet(uint(id), req)
+    if !outletScope(c).Allows(req.RegionID) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "success": false,
+            "error": service.ErrOutOfScope.Error(),
+        })
+        return
+    }
+
+    outlet, err := ctrl.outletService.UpdateOutlet(uint(id), req, version, c.GetString("userID"))
+    ctrl.respondOutletUpdate(c, outlet, err)
+}
+
+// PatchOutlet - PATCH /api/v1/outlets/:id, hanya field yang dikirim yang diubah
+func (ctrl *OutletController) PatchOutlet(c *gin.Context) {
+    id, ok := parseIDParam(c, "id", "Invalid outlet ID")
+    if !ok {
+        return
+    }
+
+    version, err := ifMatchVersion(c)
+    if err != nil {
+        c.JSON(ifMatchStatus(err), gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
+
+    var req model.OutletPatchRequest
+    if !bindAndValidate(c, &req) {
+        return
+    }
+
+    if req.RegionID != nil && !outletScope(c).Allows(req.RegionID) {
+        c.JSON(http.StatusForbidden, gin.H{
+            "success": false,
+            "error": service.ErrOutOfScope.Error(),
+        })
+        return
+    }
+
+    outlet, err := ctrl.outletService.PatchOutlet(id, req, version, c.GetString("userID"))
+    ctrl.respondOutletUpdate(c, outlet, err)
+}
+
+func (ctrl *OutletController) respondOutletUpdate(c *gin.Context, outlet *model.OutletResponse, err error) {
     if err != nil {
         status := http.StatusInternalServerError
         if err.Error() == "outlet not found" {
             status = http.StatusNotFound
+        } else if errors.Is(err, service.ErrVersionConflict) {
+            status = http.StatusPreconditionFailed
         }
         c.JSON(status, gin.H{
             "success": false,
@@ -154,6 +310,7 @@ func (ctrl *OutletController) UpdateOutlet(c *gin.Context) {
         return
     }
 
+    c.Header("ETag", versionETag(outlet.Version))
     c.JSON(http.StatusOK, gin.H{
         "success": true,
         "data": outlet,
@@ -173,7 +330,7 @@ func (ctrl *OutletController) DeleteOutlet(c *gin.Context) {
         return
     }
 
-    err = ctrl.outletService.DeleteOutlet(uint(id))
+    err = ctrl.outletService.DeleteOutlet(uint(id), c.GetString("userID"))
     if err != nil {
         status := http.StatusInternalServerError
         if err.Error() == "outlet not found" {
@@ -192,8 +349,35 @@ func (ctrl *OutletController) DeleteOutlet(c *gin.Context) {
     })
 }
 
+// GetOutletStats - GET /api/v1/outlets/stats?from=YYYY-MM-DD&to=YYYY-MM-DD
 func (ctrl *OutletController) GetOutletStats(c *gin.Context) {
-    stats, err := ctrl.outletService.GetOutletStats()
+    var params model.OutletStatsQuery
+    if err := c.ShouldBindQuery(&params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid query parameters",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if err := middleware.Validator.Struct(params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    stats, err := ctrl.outletService.GetOutletStats(params, outletScope(c))
+    if errors.Is(err, service.ErrInvalidStatsRange) {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "success": false,
@@ -208,4 +392,538 @@ func (ctrl *OutletController) GetOutletStats(c *gin.Context) {
         "data": stats,
         "message": "Outlet statistics fetched successfully",
     })
+}
+
+// GetOutletHistory - GET /v1/outlets/:id/history
+func (ctrl *OutletController) GetOutletHistory(c *gin.Context) {
+    idStr := c.Param("id")
+    id, err := strconv.ParseUint(idStr, 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid outlet ID",
+        })
+        return
+    }
+
+    history, err := ctrl.outletService.GetOutletHistory(uint(id))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error": "Failed to fetch outlet history",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": history,
+        "message": "Outlet history fetched successfully",
+        "count": len(history),
+    })
+}
+
+// SaveSpecialDay - POST /v1/outlets/:id/special-days
+func (ctrl *OutletController) SaveSpecialDay(c *gin.Context) {
+    idStr := c.Param("id")
+    id, err := strconv.ParseUint(idStr, 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid outlet ID",
+        })
+        return
+    }
+
+    var req model.OutletSpecialDayRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if err := middleware.Validator.Struct(req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    specialDay, err := ctrl.outletService.SaveSpecialDay(uint(id), req, c.GetString("userID"))
+    if err != nil {
+        status := http.StatusInternalServerError
+        if err.Error() == "outlet not found" {
+            status = http.StatusNotFound
+        }
+        c.JSON(status, gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": specialDay,
+        "message": "Special day saved successfully",
+    })
+}
+
+// DeleteSpecialDay - DELETE /v1/outlets/:id/special-days/:dayId
+func (ctrl *OutletController) DeleteSpecialDay(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid outlet ID",
+        })
+        return
+    }
+    dayID, err := strconv.ParseUint(c.Param("dayId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid special day ID",
+        })
+        return
+    }
+
+    err = ctrl.outletService.DeleteSpecialDay(uint(id), uint(dayID), c.GetString("userID"))
+    if err != nil {
+        status := http.StatusInternalServerError
+        if err.Error() == "special day not found" {
+            status = http.StatusNotFound
+        }
+        c.JSON(status, gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Special day deleted successfully",
+    })
+}
+
+// GetNearbyOutlets - GET /v1/outlets/nearby?lat=&lng=&radius=
+func (ctrl *OutletController) GetNearbyOutlets(c *gin.Context) {
+    var params model.GeoQuery
+    if !bindGeoQuery(c, &params) {
+        return
+    }
+
+    outlets, err := ctrl.outletService.GetNearbyOutlets(*params.Lat, *params.Lng, params.Radius, outletScope(c))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error": "Failed to fetch nearby outlets",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": outlets,
+        "message": "Nearby outlets fetched successfully",
+        "count": len(outlets),
+    })
+}
+
+// GetServingOutlets - GET /v1/outlets/serving?lat=&lng=
+func (ctrl *OutletController) GetServingOutlets(c *gin.Context) {
+    var params model.GeoQuery
+    if !bindGeoQuery(c, &params) {
+        return
+    }
+
+    outlets, err := ctrl.outletService.GetServingOutlets(*params.Lat, *params.Lng, outletScope(c))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error": "Failed to fetch serving outlets",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": outlets,
+        "message": "Serving outlets fetched successfully",
+        "count": len(outlets),
+    })
+}
+
+// GetDeliveryAreas - GET /v1/outlets/:id/delivery-areas
+func (ctrl *OutletController) GetDeliveryAreas(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid outlet ID",
+        })
+        return
+    }
+
+    areas, err := ctrl.outletService.GetDeliveryAreas(uint(id))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error": "Failed to fetch delivery areas",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": areas,
+        "message": "Delivery areas fetched successfully",
+        "count": len(areas),
+    })
+}
+
+// CreateDeliveryArea - POST /v1/outlets/:id/delivery-areas
+func (ctrl *OutletController) CreateDeliveryArea(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid outlet ID",
+        })
+        return
+    }
+
+    var req model.DeliveryAreaRequest
+    if err := c.ShouldBindJSON(&req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid request data",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if err := middleware.Validator.Struct(req); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    area, err := ctrl.outletService.CreateDeliveryArea(uint(id), req, c.GetString("userID"))
+    if err != nil {
+        status := http.StatusInternalServerError
+        if err.Error() == "outlet not found" {
+            status = http.StatusNotFound
+        }
+        c.JSON(status, gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusCreated, gin.H{
+        "success": true,
+        "data": area,
+        "message": "Delivery area created successfully",
+    })
+}
+
+// DeleteDeliveryArea - DELETE /v1/outlets/:id/delivery-areas/:areaId
+func (ctrl *OutletController) DeleteDeliveryArea(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid outlet ID",
+        })
+        return
+    }
+    areaID, err := strconv.ParseUint(c.Param("areaId"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid delivery area ID",
+        })
+        return
+    }
+
+    err = ctrl.outletService.DeleteDeliveryArea(uint(id), uint(areaID), c.GetString("userID"))
+    if err != nil {
+        status := http.StatusInternalServerError
+        if err.Error() == "delivery area not found" {
+            status = http.StatusNotFound
+        }
+        c.JSON(status, gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "message": "Delivery area deleted successfully",
+    })
+}
+
+func bindGeoQuery(c *gin.Context, params *model.GeoQuery) bool {
+    if err := c.ShouldBindQuery(params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid query parameters",
+            "details": err.Error(),
+        })
+        return false
+    }
+
+    if err := middleware.Validator.Struct(params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Validation failed",
+            "details": err.Error(),
+        })
+        return false
+    }
+    return true
+}
+
+// ImportOutlets - POST /api/v1/outlets/import (multipart: file, sheetName, dryRun)
+func (ctrl *OutletController) ImportOutlets(c *gin.Context) {
+    file, err := c.FormFile("file")
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "file is required",
+        })
+        return
+    }
+
+    ext := strings.ToLower(filepath.Ext(file.Filename))
+    if ok, errMsg := middleware.ValidateFile(outletImportMaxSize, file.Size, ext, []string{".xlsx"}); !ok {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": errMsg,
+        })
+        return
+    }
+
+    sheetName := c.DefaultPostForm("sheetName", service.OutletSheetName)
+    dryRun, _ := strconv.ParseBool(c.DefaultPostForm("dryRun", c.Query("dryRun")))
+
+    tmp, err := os.CreateTemp("", "outlet-import-*.xlsx")
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error": "Failed to save uploaded file",
+            "details": err.Error(),
+        })
+        return
+    }
+    tmp.Close()
+    defer os.Remove(tmp.Name())
+
+    if err := c.SaveUploadedFile(file, tmp.Name()); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error": "Failed to save uploaded file",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    _, rows, ok := thirdparty.ReadExcelFile(sheetName, tmp.Name())
+    if !ok {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Failed to read sheet " + sheetName + " from the Excel file",
+        })
+        return
+    }
+
+    result, err := ctrl.outletService.ImportOutlets(rows, dryRun, outletScope(c), c.GetString("userID"))
+    if err != nil {
+        status := http.StatusInternalServerError
+        if errors.Is(err, service.ErrVersionConflict) {
+            status = http.StatusConflict
+        }
+        c.JSON(status, gin.H{
+            "success": false,
+            "error": "Failed to import outlets",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    // Ada baris yang tidak valid: tidak ada yang disimpan
+    if result.Failed > 0 && !dryRun {
+        c.JSON(http.StatusUnprocessableEntity, gin.H{
+            "success": false,
+            "data": result,
+            "error": "Some rows are invalid, nothing was imported",
+        })
+        return
+    }
+
+    message := "Outlets imported successfully"
+    if dryRun {
+        message = "Dry run finished, nothing was saved"
+    }
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": result,
+        "message": message,
+    })
+}
+
+// ExportOutlets - GET /api/v1/outlets/export, memakai filter yang sama dengan listing
+func (ctrl *OutletController) ExportOutlets(c *gin.Context) {
+    var params model.OutletListQuery
+    if err := c.ShouldBindQuery(&params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid query parameters",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if err := middleware.Validator.Struct(params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    path, err := ctrl.outletService.ExportOutlets(params, outletScope(c))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error": "Failed to export outlets",
+            "details": err.Error(),
+        })
+        return
+    }
+    defer os.Remove(path)
+
+    c.FileAttachment(path, "outlets-"+time.Now().Format("20060102")+".xlsx")
+}
+
+// GetOutletQR - GET /api/v1/outlets/:id/qr?size=&format=png|svg
+func (ctrl *OutletController) GetOutletQR(c *gin.Context) {
+    id, ok := parseIDParam(c, "id", "Invalid outlet ID")
+    if !ok {
+        return
+    }
+
+    var params model.OutletQRQuery
+    if err := c.ShouldBindQuery(&params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid query parameters",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if err := middleware.Validator.Struct(params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    image, contentType, err := ctrl.outletService.GenerateOutletQR(id, params)
+    if err != nil {
+        status := http.StatusInternalServerError
+        if err.Error() == "outlet not found" {
+            status = http.StatusNotFound
+        }
+        c.JSON(status, gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
+
+    c.Data(http.StatusOK, contentType, image)
+}
+
+// GetOutletQRSheet - GET /api/v1/outlets/qr-sheet, PDF siap cetak berisi QR semua outlet
+func (ctrl *OutletController) GetOutletQRSheet(c *gin.Context) {
+    var params model.OutletListQuery
+    if err := c.ShouldBindQuery(&params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Invalid query parameters",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    if err := middleware.Validator.Struct(params); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error": "Validation failed",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    pdf, err := ctrl.outletService.GenerateQRSheet(params, outletScope(c))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error": "Failed to generate QR sheet",
+            "details": err.Error(),
+        })
+        return
+    }
+
+    c.Header("Content-Disposition", `attachment; filename="outlet-qr-codes.pdf"`)
+    c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// GetPublicOutlet - GET /api/v1/public/outlets/:id, tanpa login (tujuan QR code)
+func (ctrl *OutletController) GetPublicOutlet(c *gin.Context) {
+    id, ok := parseIDParam(c, "id", "Invalid outlet ID")
+    if !ok {
+        return
+    }
+
+    outlet, err := ctrl.outletService.GetPublicOutlet(id)
+    if err != nil {
+        status := http.StatusInternalServerError
+        if errors.Is(err, service.ErrOutletNotPublic) {
+            status = http.StatusNotFound
+        }
+        c.JSON(status, gin.H{
+            "success": false,
+            "error": err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data": outlet,
+        "message": "Outlet fetched successfully",
+    })
 }
\ No newline at end of file
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type PayrollController struct {
+	payrollService *service.PayrollService
+}
+
+func NewPayrollController() *PayrollController {
+	return &PayrollController{
+		payrollService: service.NewPayrollService(),
+	}
+}
+
+// GetRuns - GET /v1/payroll/runs?year=&status=&page=&pageSize=
+func (ctrl *PayrollController) GetRuns(c *gin.Context) {
+	var params model.PayrollRunListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.payrollService.GetRuns(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch payroll runs",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Runs,
+		"message":    "Payroll runs fetched successfully",
+		"count":      len(result.Runs),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetRun - GET /v1/payroll/runs/:id
+func (ctrl *PayrollController) GetRun(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid payroll run ID")
+	if !ok {
+		return
+	}
+
+	run, err := ctrl.payrollService.GetRun(id)
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    run,
+		"message": "Payroll run fetched successfully",
+	})
+}
+
+// CreateRun - POST /v1/payroll/runs
+func (ctrl *PayrollController) CreateRun(c *gin.Context) {
+	var req model.PayrollRunRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	run, err := ctrl.payrollService.CreateRun(req, c.GetString("userID"))
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    run,
+		"message": "Payroll run calculated successfully",
+	})
+}
+
+// RecalculateRun - POST /v1/payroll/runs/:id/recalculate
+func (ctrl *PayrollController) RecalculateRun(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid payroll run ID")
+	if !ok {
+		return
+	}
+
+	run, err := ctrl.payrollService.RecalculateRun(id)
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    run,
+		"message": "Payroll run recalculated successfully",
+	})
+}
+
+// LockRun - POST /v1/payroll/runs/:id/lock
+func (ctrl *PayrollController) LockRun(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid payroll run ID")
+	if !ok {
+		return
+	}
+
+	run, err := ctrl.payrollService.LockRun(id, c.GetString("userID"))
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    run,
+		"message": "Payroll run locked successfully",
+	})
+}
+
+// DeleteRun - DELETE /v1/payroll/runs/:id
+func (ctrl *PayrollController) DeleteRun(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid payroll run ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.payrollService.DeleteRun(id); err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Payroll run deleted successfully",
+	})
+}
+
+// SendPayslips - POST /v1/payroll/runs/:id/payslips/send?resend=true
+func (ctrl *PayrollController) SendPayslips(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid payroll run ID")
+	if !ok {
+		return
+	}
+
+	result, err := ctrl.payrollService.SendPayslips(id, c.Query("resend") == "true")
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+		"message": "Payslips sent",
+	})
+}
+
+// GetPayslipPDF - GET /v1/payroll/runs/:id/payslips/:payslipId/pdf
+func (ctrl *PayrollController) GetPayslipPDF(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid payroll run ID")
+	if !ok {
+		return
+	}
+	payslipID, ok := parseIDParam(c, "payslipId", "Invalid payslip ID")
+	if !ok {
+		return
+	}
+
+	pdf, payslip, err := ctrl.payrollService.PayslipPDF(id, payslipID)
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("payslip-%s-%d.pdf", payslip.EmployeeCode, payslip.RunID)
+	c.Header("Content-Disposition", `inline; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// ExportRun - GET /v1/payroll/runs/:id/export
+func (ctrl *PayrollController) ExportRun(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid payroll run ID")
+	if !ok {
+		return
+	}
+
+	path, run, err := ctrl.payrollService.ExportRun(id)
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+	defer os.Remove(path)
+
+	filename := "payroll-" + run.Period
+	if run.OutletID != 0 {
+		filename += fmt.Sprintf("-outlet-%d", run.OutletID)
+	}
+	c.FileAttachment(path, filename+".xlsx")
+}
+
+func respondPayrollError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPayrollRunNotFound),
+		errors.Is(err, service.ErrPayslipNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrPayrollRunExists),
+		errors.Is(err, service.ErrPayrollRunLocked),
+		errors.Is(err, service.ErrPayrollRunNotLocked):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondInventoryError(c, err)
+	}
+}
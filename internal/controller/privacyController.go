@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/service"
+)
+
+type PrivacyController struct {
+	privacyService *service.PrivacyService
+}
+
+func NewPrivacyController() *PrivacyController {
+	return &PrivacyController{
+		privacyService: service.NewPrivacyService(),
+	}
+}
+
+// ExportUserData - GET /v1/admin/users/:usrId/export?format=zip|json
+func (ctrl *PrivacyController) ExportUserData(c *gin.Context) {
+	usrId := c.Param("usrId")
+
+	export, err := ctrl.privacyService.ExportUserData(usrId)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   "Failed to export user data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if c.DefaultQuery("format", "zip") == "json" {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    export,
+			"message": "User data exported successfully",
+		})
+		return
+	}
+
+	archive, err := ctrl.privacyService.BuildExportArchive(export)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to build export archive",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	fileName := fmt.Sprintf("user-data-%s-%s.zip", usrId, time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// EraseUserData - DELETE /v1/admin/users/:usrId/personal-data
+func (ctrl *PrivacyController) EraseUserData(c *gin.Context) {
+	usrId := c.Param("usrId")
+
+	result, err := ctrl.privacyService.EraseUserData(usrId, c.GetString("userID"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   "Failed to erase user data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+		"message": "User personal data erased successfully",
+	})
+}
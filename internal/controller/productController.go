@@ -0,0 +1,341 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type ProductController struct {
+	productService *service.ProductService
+}
+
+func NewProductController() *ProductController {
+	return &ProductController{
+		productService: service.NewProductService(),
+	}
+}
+
+// GetCategories - GET /v1/product-categories
+func (ctrl *ProductController) GetCategories(c *gin.Context) {
+	categories, err := ctrl.productService.GetCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch product categories",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    categories,
+		"message": "Product categories fetched successfully",
+		"count":   len(categories),
+	})
+}
+
+// CreateCategory - POST /v1/product-categories
+func (ctrl *ProductController) CreateCategory(c *gin.Context) {
+	var req model.ProductCategoryRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	category, err := ctrl.productService.CreateCategory(req)
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Product category created successfully",
+	})
+}
+
+// UpdateCategory - PUT /v1/product-categories/:id
+func (ctrl *ProductController) UpdateCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	var req model.ProductCategoryRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	category, err := ctrl.productService.UpdateCategory(id, req)
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    category,
+		"message": "Product category updated successfully",
+	})
+}
+
+// DeleteCategory - DELETE /v1/product-categories/:id
+func (ctrl *ProductController) DeleteCategory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.productService.DeleteCategory(id); err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Product category deleted successfully",
+	})
+}
+
+// GetProducts - GET /v1/products?search=&categoryId=&active=&page=&pageSize=
+func (ctrl *ProductController) GetProducts(c *gin.Context) {
+	var params model.ProductListQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := middleware.Validator.Struct(params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := ctrl.productService.GetProducts(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch products",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Products,
+		"message":    "Products fetched successfully",
+		"count":      len(result.Products),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetProduct - GET /v1/products/:id
+func (ctrl *ProductController) GetProduct(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	product, err := ctrl.productService.GetProductByID(id)
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    product,
+		"message": "Product fetched successfully",
+	})
+}
+
+// GetProductByCode - GET /v1/products/lookup/:code (SKU atau barcode hasil scan)
+func (ctrl *ProductController) GetProductByCode(c *gin.Context) {
+	product, variant, err := ctrl.productService.GetProductByCode(c.Param("code"))
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"product": product,
+			"variant": variant,
+		},
+		"message": "Product fetched successfully",
+	})
+}
+
+// CreateProduct - POST /v1/products
+func (ctrl *ProductController) CreateProduct(c *gin.Context) {
+	var req model.ProductRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	product, err := ctrl.productService.CreateProduct(req)
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    product,
+		"message": "Product created successfully",
+	})
+}
+
+// UpdateProduct - PUT /v1/products/:id
+func (ctrl *ProductController) UpdateProduct(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	var req model.ProductRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	product, err := ctrl.productService.UpdateProduct(id, req)
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    product,
+		"message": "Product updated successfully",
+	})
+}
+
+// DeleteProduct - DELETE /v1/products/:id (soft delete)
+func (ctrl *ProductController) DeleteProduct(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.productService.DeleteProduct(id); err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Product deleted successfully",
+	})
+}
+
+// SetOutletPrice - PUT /v1/products/:id/outlet-prices/:outletId
+func (ctrl *ProductController) SetOutletPrice(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+	outletID, ok := parseIDParam(c, "outletId", "Invalid outlet ID")
+	if !ok {
+		return
+	}
+
+	var req model.ProductOutletPriceRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	price, err := ctrl.productService.SetOutletPrice(id, outletID, req, outletScope(c))
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    price,
+		"message": "Outlet price saved successfully",
+	})
+}
+
+// DeleteOutletPrice - DELETE /v1/products/:id/outlet-prices/:outletId?variantId=
+func (ctrl *ProductController) DeleteOutletPrice(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+	outletID, ok := parseIDParam(c, "outletId", "Invalid outlet ID")
+	if !ok {
+		return
+	}
+	variantID, _ := strconv.ParseUint(c.Query("variantId"), 10, 32)
+
+	if err := ctrl.productService.DeleteOutletPrice(id, outletID, uint(variantID), outletScope(c)); err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Outlet price deleted successfully",
+	})
+}
+
+// ResolvePrice - GET /v1/products/:id/price?outletId=&variantId=
+func (ctrl *ProductController) ResolvePrice(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid product ID")
+	if !ok {
+		return
+	}
+	outletID, _ := strconv.ParseUint(c.Query("outletId"), 10, 32)
+	variantID, _ := strconv.ParseUint(c.Query("variantId"), 10, 32)
+
+	price, err := ctrl.productService.ResolvePrice(id, uint(variantID), uint(outletID))
+	if err != nil {
+		respondProductError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    price,
+		"message": "Price resolved successfully",
+	})
+}
+
+func respondProductError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrProductNotFound),
+		errors.Is(err, service.ErrProductCategoryNotFound),
+		errors.Is(err, service.ErrProductVariantNotFound),
+		errors.Is(err, service.ErrProductModifierNotFound),
+		errors.Is(err, service.ErrProductOutletNotFound),
+		errors.Is(err, service.ErrProductOutletPriceAbsent):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrDuplicateProductCode),
+		errors.Is(err, service.ErrProductCategoryNotEmpty):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
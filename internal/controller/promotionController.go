@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type PromotionController struct {
+	promotionService *service.PromotionService
+}
+
+func NewPromotionController() *PromotionController {
+	return &PromotionController{
+		promotionService: service.NewPromotionService(),
+	}
+}
+
+// GetPromotions - GET /v1/promotions?search=&type=&active=&outletId=&page=&pageSize=
+func (ctrl *PromotionController) GetPromotions(c *gin.Context) {
+	var params model.PromotionListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.promotionService.GetPromotions(params)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch promotions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Promotions,
+		"message":    "Promotions fetched successfully",
+		"count":      len(result.Promotions),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetPromotion - GET /v1/promotions/:id
+func (ctrl *PromotionController) GetPromotion(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid promotion ID")
+	if !ok {
+		return
+	}
+
+	promotion, err := ctrl.promotionService.GetPromotion(id)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    promotion,
+		"message": "Promotion fetched successfully",
+	})
+}
+
+// CreatePromotion - POST /v1/promotions
+func (ctrl *PromotionController) CreatePromotion(c *gin.Context) {
+	var req model.PromotionRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	promotion, err := ctrl.promotionService.CreatePromotion(req)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    promotion,
+		"message": "Promotion created successfully",
+	})
+}
+
+// UpdatePromotion - PUT /v1/promotions/:id
+func (ctrl *PromotionController) UpdatePromotion(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid promotion ID")
+	if !ok {
+		return
+	}
+
+	var req model.PromotionRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	promotion, err := ctrl.promotionService.UpdatePromotion(id, req)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    promotion,
+		"message": "Promotion updated successfully",
+	})
+}
+
+// DeletePromotion - DELETE /v1/promotions/:id
+func (ctrl *PromotionController) DeletePromotion(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid promotion ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.promotionService.DeletePromotion(id); err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Promotion deleted successfully",
+	})
+}
+
+// PreviewPromotions - POST /v1/promotions/preview
+func (ctrl *PromotionController) PreviewPromotions(c *gin.Context) {
+	var req model.PromotionPreviewRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	preview, err := ctrl.promotionService.Preview(req, outletScope(c))
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    preview,
+		"message": "Promotions evaluated successfully",
+	})
+}
+
+func respondPromotionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrPromoNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrPromoCodeExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrPromoValueInvalid),
+		errors.Is(err, service.ErrPromoPeriodInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondCustomerError(c, err, nil)
+	}
+}
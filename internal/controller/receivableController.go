@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type ReceivableController struct {
+	receivableService *service.ReceivableService
+}
+
+func NewReceivableController() *ReceivableController {
+	return &ReceivableController{
+		receivableService: service.NewReceivableService(),
+	}
+}
+
+// GetReceivables - GET /v1/receivables?customerId=&outletId=&status=&overdue=&page=&pageSize=
+func (ctrl *ReceivableController) GetReceivables(c *gin.Context) {
+	var params model.ReceivableListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.receivableService.GetReceivables(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch receivables",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Receivables,
+		"message":    "Receivables fetched successfully",
+		"count":      len(result.Receivables),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetReceivable - GET /v1/receivables/:id
+func (ctrl *ReceivableController) GetReceivable(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid receivable ID")
+	if !ok {
+		return
+	}
+
+	receivable, err := ctrl.receivableService.GetReceivable(id, outletScope(c))
+	if err != nil {
+		respondReceivableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    receivable,
+		"message": "Receivable fetched successfully",
+	})
+}
+
+// RecordPayment - POST /v1/receivables/:id/payments
+func (ctrl *ReceivableController) RecordPayment(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid receivable ID")
+	if !ok {
+		return
+	}
+
+	var req model.ReceivablePaymentRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	receivable, err := ctrl.receivableService.RecordPayment(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondReceivableError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    receivable,
+		"message": "Payment recorded successfully",
+	})
+}
+
+// GetAging - GET /v1/receivables/aging?asOf=YYYY-MM-DD&outletId=
+func (ctrl *ReceivableController) GetAging(c *gin.Context) {
+	var params model.ReceivableAgingQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	report, err := ctrl.receivableService.GetAging(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to build aging report",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+		"message": "Aging report fetched successfully",
+	})
+}
+
+// SendReminders - POST /v1/receivables/reminders
+// Mengirim email pengingat piutang jatuh tempo; dijalankan harian oleh scheduler.
+func (ctrl *ReceivableController) SendReminders(c *gin.Context) {
+	result, err := ctrl.receivableService.SendReminders(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to send receivable reminders",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+		"message": "Receivable reminders sent",
+	})
+}
+
+// GetCustomerStatement - GET /v1/customers/:id/statement?from=&to= (PDF)
+func (ctrl *ReceivableController) GetCustomerStatement(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid customer ID")
+	if !ok {
+		return
+	}
+
+	var params model.CustomerStatementQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	pdf, statement, err := ctrl.receivableService.CustomerStatementPDF(id, params, outletScope(c))
+	if err != nil {
+		respondReceivableError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%d-%s.pdf", statement.Customer.ID, statement.To.Format("20060102"))
+	c.Header("Content-Disposition", `inline; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func respondReceivableError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrReceivableNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrReceivableSettled):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrReceivableOverpaid):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidStatementPeriod):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondCustomerError(c, err, nil)
+	}
+}
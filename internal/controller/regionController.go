@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type RegionController struct {
+	regionService *service.RegionService
+}
+
+func NewRegionController() *RegionController {
+	return &RegionController{
+		regionService: service.NewRegionService(),
+	}
+}
+
+// GetBrands - GET /v1/brands
+func (ctrl *RegionController) GetBrands(c *gin.Context) {
+	brands, err := ctrl.regionService.GetBrands()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch brands",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    brands,
+		"message": "Brands fetched successfully",
+		"count":   len(brands),
+	})
+}
+
+// CreateBrand - POST /v1/brands
+func (ctrl *RegionController) CreateBrand(c *gin.Context) {
+	var req model.BrandRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	brand, err := ctrl.regionService.CreateBrand(req)
+	if err != nil {
+		respondRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    brand,
+		"message": "Brand created successfully",
+	})
+}
+
+// UpdateBrand - PUT /v1/brands/:id
+func (ctrl *RegionController) UpdateBrand(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid brand ID")
+	if !ok {
+		return
+	}
+
+	var req model.BrandRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	brand, err := ctrl.regionService.UpdateBrand(id, req)
+	if err != nil {
+		respondRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    brand,
+		"message": "Brand updated successfully",
+	})
+}
+
+// DeleteBrand - DELETE /v1/brands/:id
+func (ctrl *RegionController) DeleteBrand(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid brand ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.regionService.DeleteBrand(id); err != nil {
+		respondRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Brand deleted successfully",
+	})
+}
+
+// GetRegions - GET /v1/regions?brandId=
+func (ctrl *RegionController) GetRegions(c *gin.Context) {
+	brandID, _ := strconv.ParseUint(c.Query("brandId"), 10, 32)
+
+	regions, err := ctrl.regionService.GetRegions(uint(brandID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch regions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    regions,
+		"message": "Regions fetched successfully",
+		"count":   len(regions),
+	})
+}
+
+// GetRegionTree - GET /v1/regions/tree?brandId=
+func (ctrl *RegionController) GetRegionTree(c *gin.Context) {
+	brandID, _ := strconv.ParseUint(c.Query("brandId"), 10, 32)
+
+	tree, err := ctrl.regionService.GetRegionTree(uint(brandID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch region tree",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tree,
+		"message": "Region tree fetched successfully",
+	})
+}
+
+// CreateRegion - POST /v1/regions
+func (ctrl *RegionController) CreateRegion(c *gin.Context) {
+	var req model.RegionRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	region, err := ctrl.regionService.CreateRegion(req)
+	if err != nil {
+		respondRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    region,
+		"message": "Region created successfully",
+	})
+}
+
+// UpdateRegion - PUT /v1/regions/:id
+func (ctrl *RegionController) UpdateRegion(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid region ID")
+	if !ok {
+		return
+	}
+
+	var req model.RegionRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	region, err := ctrl.regionService.UpdateRegion(id, req)
+	if err != nil {
+		respondRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    region,
+		"message": "Region updated successfully",
+	})
+}
+
+// DeleteRegion - DELETE /v1/regions/:id
+func (ctrl *RegionController) DeleteRegion(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid region ID")
+	if !ok {
+		return
+	}
+
+	if err := ctrl.regionService.DeleteRegion(id); err != nil {
+		respondRegionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Region deleted successfully",
+	})
+}
+
+func respondRegionError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrBrandNotFound), errors.Is(err, service.ErrRegionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrRegionParent):
+		status = http.StatusBadRequest
+	case errors.Is(err, service.ErrRegionNotEmpty), errors.Is(err, service.ErrBrandNotEmpty):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{
+		"success": false,
+		"error":   err.Error(),
+	})
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/middleware"
+)
+
+// bindAndValidate binds the JSON body into req and runs the struct validation,
+// writing the 400 response itself when either step fails.
+func bindAndValidate(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return false
+	}
+
+	if err := middleware.Validator.Struct(req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// bindQueryAndValidate is bindAndValidate for query-string params.
+func bindQueryAndValidate(c *gin.Context, params interface{}) bool {
+	if err := c.ShouldBindQuery(params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid query parameters",
+			"details": err.Error(),
+		})
+		return false
+	}
+	if err := middleware.Validator.Struct(params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Validation failed",
+			"details": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func parseIDParam(c *gin.Context, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   message,
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+var (
+	errIfMatchMissing = errors.New("If-Match header with the ETag from GET is required")
+	errIfMatchInvalid = errors.New("If-Match header does not match the current version")
+)
+
+// versionETag formats a row version as a strong ETag.
+func versionETag(version uint) string {
+	return strconv.Quote(strconv.FormatUint(uint64(version), 10))
+}
+
+// ifMatchVersion reads the version the client last saw from If-Match.
+// A missing header maps to 428, an unparseable one to 412.
+func ifMatchVersion(c *gin.Context) (uint, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, errIfMatchMissing
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	value, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, errIfMatchInvalid
+	}
+	version, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, errIfMatchInvalid
+	}
+	return uint(version), nil
+}
+
+func ifMatchStatus(err error) int {
+	if errors.Is(err, errIfMatchMissing) {
+		return http.StatusPreconditionRequired
+	}
+	return http.StatusPreconditionFailed
+}
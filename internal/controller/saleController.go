@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type SaleController struct {
+	saleService *service.SaleService
+}
+
+func NewSaleController() *SaleController {
+	return &SaleController{
+		saleService: service.NewSaleService(),
+	}
+}
+
+// CreateSale - POST /v1/sales
+// 201 untuk transaksi baru, 200 jika clientTransactionId sudah pernah diposting.
+func (ctrl *SaleController) CreateSale(c *gin.Context) {
+	var req model.SaleRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	sale, created, err := ctrl.saleService.CreateSale(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondSaleError(c, err)
+		return
+	}
+
+	if !created {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    sale,
+			"message": "Sale was already posted",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    sale,
+		"message": "Sale posted successfully",
+	})
+}
+
+// GetSales - GET /v1/sales?outletId=&status=&cashierId=&from=&to=&page=&pageSize=
+func (ctrl *SaleController) GetSales(c *gin.Context) {
+	var params model.SaleListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.saleService.GetSales(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch sales",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Sales,
+		"message":    "Sales fetched successfully",
+		"count":      len(result.Sales),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetSale - GET /v1/sales/:id
+func (ctrl *SaleController) GetSale(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sale ID")
+	if !ok {
+		return
+	}
+
+	sale, err := ctrl.saleService.GetSale(id, outletScope(c))
+	if err != nil {
+		respondSaleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sale,
+		"message": "Sale fetched successfully",
+	})
+}
+
+// GetSaleByClientID - GET /v1/sales/client/:clientTransactionId
+func (ctrl *SaleController) GetSaleByClientID(c *gin.Context) {
+	sale, err := ctrl.saleService.GetSaleByClientID(c.Param("clientTransactionId"), outletScope(c))
+	if err != nil {
+		respondSaleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sale,
+		"message": "Sale fetched successfully",
+	})
+}
+
+// RequestVoid - POST /v1/sales/:id/void
+func (ctrl *SaleController) RequestVoid(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sale ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaleVoidRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	reversal, err := ctrl.saleService.RequestVoid(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondSaleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    reversal,
+		"message": "Void request submitted for supervisor approval",
+	})
+}
+
+// RequestReturn - POST /v1/sales/:id/returns
+func (ctrl *SaleController) RequestReturn(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid sale ID")
+	if !ok {
+		return
+	}
+
+	var req model.SaleReturnRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	reversal, err := ctrl.saleService.RequestReturn(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondSaleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    reversal,
+		"message": "Return request submitted for supervisor approval",
+	})
+}
+
+// GetReversals - GET /v1/sales/reversals?outletId=&type=&status=&page=&pageSize=
+func (ctrl *SaleController) GetReversals(c *gin.Context) {
+	var params model.SaleReversalListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.saleService.GetReversals(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch voids and returns",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Reversals,
+		"message":    "Voids and returns fetched successfully",
+		"count":      len(result.Reversals),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetReversal - GET /v1/sales/reversals/:id
+func (ctrl *SaleController) GetReversal(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid request ID")
+	if !ok {
+		return
+	}
+
+	reversal, err := ctrl.saleService.GetReversal(id, outletScope(c))
+	if err != nil {
+		respondSaleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reversal,
+		"message": "Void or return fetched successfully",
+	})
+}
+
+// ApproveReversal - POST /v1/sales/reversals/:id/approve
+func (ctrl *SaleController) ApproveReversal(c *gin.Context) {
+	ctrl.reviewReversal(c, true)
+}
+
+// RejectReversal - POST /v1/sales/reversals/:id/reject
+func (ctrl *SaleController) RejectReversal(c *gin.Context) {
+	ctrl.reviewReversal(c, false)
+}
+
+func (ctrl *SaleController) reviewReversal(c *gin.Context, approve bool) {
+	id, ok := parseIDParam(c, "id", "Invalid request ID")
+	if !ok {
+		return
+	}
+
+	// Catatan review opsional, body boleh kosong
+	var req model.SaleReversalReviewRequest
+	if c.Request.ContentLength > 0 && !bindAndValidate(c, &req) {
+		return
+	}
+
+	review, message := ctrl.saleService.RejectReversal, "Request rejected"
+	if approve {
+		review, message = ctrl.saleService.ApproveReversal, "Request approved, stock and refunds posted"
+	}
+	reversal, err := review(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondSaleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reversal,
+		"message": message,
+	})
+}
+
+// GetReversalReport - GET /v1/sales/reversal-report?outletId=&from=&to=
+func (ctrl *SaleController) GetReversalReport(c *gin.Context) {
+	var params model.SaleReversalReportQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	report, err := ctrl.saleService.GetReversalReport(params, outletScope(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidReversalPeriod) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   "Failed to build void and return report",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+		"message": "Void and return report fetched successfully",
+	})
+}
+
+func respondSaleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSaleNotFound),
+		errors.Is(err, service.ErrSaleReversalNotFound),
+		errors.Is(err, service.ErrSaleLineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrSaleClientIDConflict),
+		errors.Is(err, service.ErrSaleReversalPending),
+		errors.Is(err, service.ErrSaleReversalReviewed),
+		errors.Is(err, service.ErrSaleNotVoidable),
+		errors.Is(err, service.ErrSaleNotReturnable),
+		errors.Is(err, service.ErrSaleDayClosed),
+		errors.Is(err, service.ErrCashShiftNotOpen),
+		errors.Is(err, service.ErrDailyClosingExists),
+		errors.Is(err, service.ErrPromoUsageExhausted):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrSaleReversalSelfReview):
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrSaleProductInactive),
+		errors.Is(err, service.ErrSaleModifierInvalid),
+		errors.Is(err, service.ErrSaleDiscountTooLarge),
+		errors.Is(err, service.ErrSaleUnderpaid),
+		errors.Is(err, service.ErrSaleNonCashChange),
+		errors.Is(err, service.ErrSaleSoldAtInvalid),
+		errors.Is(err, service.ErrSaleReturnQuantity),
+		errors.Is(err, service.ErrCustomerNotFound),
+		errors.Is(err, service.ErrLoyaltyNoCustomer),
+		errors.Is(err, service.ErrLoyaltyPointsAmount),
+		errors.Is(err, service.ErrLoyaltyInsufficientPoints),
+		errors.Is(err, service.ErrReceivableNoCustomer),
+		errors.Is(err, service.ErrReceivableCreditNotAllowed),
+		errors.Is(err, service.ErrReceivableCreditLimit):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondInventoryError(c, err)
+	}
+}
@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+	"BackendFramework/internal/thirdparty"
+)
+
+const stockCountUploadMaxSize = 5 * 1024 * 1024 // 5 MB
+
+type StockCountController struct {
+	countService *service.StockCountService
+}
+
+func NewStockCountController() *StockCountController {
+	return &StockCountController{
+		countService: service.NewStockCountService(),
+	}
+}
+
+// GetCounts - GET /v1/stock-counts?outletId=&status=&page=&pageSize=
+func (ctrl *StockCountController) GetCounts(c *gin.Context) {
+	var params model.StockCountListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.countService.GetCounts(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch stock counts",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Counts,
+		"message":    "Stock counts fetched successfully",
+		"count":      len(result.Counts),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetCount - GET /v1/stock-counts/:id
+func (ctrl *StockCountController) GetCount(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock count ID")
+	if !ok {
+		return
+	}
+
+	count, err := ctrl.countService.GetCount(id, outletScope(c))
+	if err != nil {
+		respondStockCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    count,
+		"message": "Stock count fetched successfully",
+	})
+}
+
+// CreateCount - POST /v1/stock-counts (membekukan snapshot stok outlet)
+func (ctrl *StockCountController) CreateCount(c *gin.Context) {
+	var req model.StockCountRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	count, err := ctrl.countService.CreateCount(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondStockCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    count,
+		"message": "Stock count started successfully",
+	})
+}
+
+// EnterCounts - PUT /v1/stock-counts/:id/lines
+func (ctrl *StockCountController) EnterCounts(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock count ID")
+	if !ok {
+		return
+	}
+
+	var req model.StockCountEntryRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	count, err := ctrl.countService.EnterCounts(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondStockCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    count,
+		"message": "Counted quantities saved successfully",
+	})
+}
+
+// ImportCounts - POST /v1/stock-counts/:id/import (multipart: file, sheetName)
+func (ctrl *StockCountController) ImportCounts(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock count ID")
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "file is required",
+		})
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ok, errMsg := middleware.ValidateFile(stockCountUploadMaxSize, file.Size, ext, []string{".xlsx"}); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   errMsg,
+		})
+		return
+	}
+
+	sheetName := c.DefaultPostForm("sheetName", service.StockCountSheetName)
+
+	tmp, err := os.CreateTemp("", "stock-count-*.xlsx")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to save uploaded file",
+			"details": err.Error(),
+		})
+		return
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := c.SaveUploadedFile(file, tmp.Name()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to save uploaded file",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	_, rows, ok := thirdparty.ReadExcelFile(sheetName, tmp.Name())
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Failed to read sheet " + sheetName + " from the Excel file",
+		})
+		return
+	}
+
+	count, rowErrors, err := ctrl.countService.ImportCounts(id, rows, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondStockCountError(c, err)
+		return
+	}
+
+	// Ada baris yang tidak valid: tidak ada yang disimpan
+	if len(rowErrors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   "Some rows are invalid, nothing was saved",
+			"details": rowErrors,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    count,
+		"message": "Counted quantities imported successfully",
+	})
+}
+
+// ApproveCount - POST /v1/stock-counts/:id/approve
+func (ctrl *StockCountController) ApproveCount(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock count ID")
+	if !ok {
+		return
+	}
+
+	count, err := ctrl.countService.ApproveCount(id, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondStockCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    count,
+		"message": "Stock count approved and adjustments posted",
+	})
+}
+
+// CancelCount - POST /v1/stock-counts/:id/cancel
+func (ctrl *StockCountController) CancelCount(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock count ID")
+	if !ok {
+		return
+	}
+
+	count, err := ctrl.countService.CancelCount(id, outletScope(c))
+	if err != nil {
+		respondStockCountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    count,
+		"message": "Stock count cancelled successfully",
+	})
+}
+
+// GetVarianceReport - GET /v1/stock-counts/:id/variance-report (PDF)
+func (ctrl *StockCountController) GetVarianceReport(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock count ID")
+	if !ok {
+		return
+	}
+
+	pdf, err := ctrl.countService.VarianceReport(id, outletScope(c))
+	if err != nil {
+		respondStockCountError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="stock-count-variance.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func respondStockCountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrStockCountNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrStockCountStatus),
+		errors.Is(err, service.ErrStockCountOpen):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrStockCountNothingCounted):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondInventoryError(c, err)
+	}
+}
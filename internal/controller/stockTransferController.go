@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/service"
+)
+
+type StockTransferController struct {
+	transferService *service.StockTransferService
+}
+
+func NewStockTransferController() *StockTransferController {
+	return &StockTransferController{
+		transferService: service.NewStockTransferService(),
+	}
+}
+
+// GetTransfers - GET /v1/stock-transfers?outletId=&status=&page=&pageSize=
+func (ctrl *StockTransferController) GetTransfers(c *gin.Context) {
+	var params model.StockTransferListQuery
+	if !bindQueryAndValidate(c, &params) {
+		return
+	}
+
+	result, err := ctrl.transferService.GetTransfers(params, outletScope(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to fetch stock transfers",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       result.Transfers,
+		"message":    "Stock transfers fetched successfully",
+		"count":      len(result.Transfers),
+		"pagination": result.Pagination,
+	})
+}
+
+// GetTransfer - GET /v1/stock-transfers/:id
+func (ctrl *StockTransferController) GetTransfer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock transfer ID")
+	if !ok {
+		return
+	}
+
+	transfer, err := ctrl.transferService.GetTransfer(id, outletScope(c))
+	if err != nil {
+		respondStockTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    transfer,
+		"message": "Stock transfer fetched successfully",
+	})
+}
+
+// CreateTransfer - POST /v1/stock-transfers (draft)
+func (ctrl *StockTransferController) CreateTransfer(c *gin.Context) {
+	var req model.StockTransferRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	transfer, err := ctrl.transferService.CreateTransfer(req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondStockTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    transfer,
+		"message": "Stock transfer created successfully",
+	})
+}
+
+// UpdateTransfer - PUT /v1/stock-transfers/:id (hanya draft)
+func (ctrl *StockTransferController) UpdateTransfer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock transfer ID")
+	if !ok {
+		return
+	}
+
+	var req model.StockTransferRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	transfer, err := ctrl.transferService.UpdateTransfer(id, req, outletScope(c))
+	if err != nil {
+		respondStockTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    transfer,
+		"message": "Stock transfer updated successfully",
+	})
+}
+
+// SendTransfer - POST /v1/stock-transfers/:id/send
+func (ctrl *StockTransferController) SendTransfer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock transfer ID")
+	if !ok {
+		return
+	}
+
+	transfer, err := ctrl.transferService.SendTransfer(id, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondStockTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    transfer,
+		"message": "Stock transfer sent successfully",
+	})
+}
+
+// ReceiveTransfer - POST /v1/stock-transfers/:id/receive
+func (ctrl *StockTransferController) ReceiveTransfer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock transfer ID")
+	if !ok {
+		return
+	}
+
+	var req model.StockTransferReceiptRequest
+	if !bindAndValidate(c, &req) {
+		return
+	}
+
+	transfer, err := ctrl.transferService.ReceiveTransfer(id, req, outletScope(c), c.GetString("userID"))
+	if err != nil {
+		respondStockTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    transfer,
+		"message": "Stock transfer receipt recorded successfully",
+	})
+}
+
+// CancelTransfer - POST /v1/stock-transfers/:id/cancel (hanya draft)
+func (ctrl *StockTransferController) CancelTransfer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "Invalid stock transfer ID")
+	if !ok {
+		return
+	}
+
+	transfer, err := ctrl.transferService.CancelTransfer(id, outletScope(c))
+	if err != nil {
+		respondStockTransferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    transfer,
+		"message": "Stock transfer cancelled successfully",
+	})
+}
+
+func respondStockTransferError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrStockTransferNotFound),
+		errors.Is(err, service.ErrStockTransferLineNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrStockTransferStatus):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, service.ErrStockTransferEmptyReceipt):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		respondInventoryError(c, err)
+	}
+}
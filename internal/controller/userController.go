@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
+	"BackendFramework/internal/middleware"
 	"BackendFramework/internal/model"
 	"BackendFramework/internal/service"
 	"golang.org/x/crypto/bcrypt"
@@ -67,22 +69,23 @@ func UpdateUserInGroup(c *gin.Context) {
 
 	userInput := validatedInput.(*model.UserInput)
 	
-	
-	status, err := service.UpdateUserInGroup(usrId, userInput)
-	if !status {
-		errorMsg := "Update user in group failed"
-		if err != nil {
-			errorMsg = err.Error()
-		}
-		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{
+			"code":    ifMatchStatus(err),
 			"message": "Update user in group failed",
-			"error":   errorMsg,
+			"error":   err.Error(),
 		})
 		return
 	}
+	
+	status, err := service.UpdateUserInGroup(usrId, userInput, version, c.GetString("userID"))
+	if !status {
+		respondUserUpdateError(c, err)
+		return
+	}
 
+	c.Header("ETag", versionETag(version+1))
 	c.JSON(http.StatusOK, gin.H{
 		"code":    http.StatusOK,
 		"message": "User updated successfully",
@@ -109,7 +112,7 @@ func DeleteUserFromGroup(c *gin.Context) {
 	group := c.Query("group")
 
 	
-	status, err := service.DeleteUserFromGroup(usrId, group)
+	status, err := service.DeleteUserFromGroup(usrId, group, c.GetString("userID"))
 	if !status {
 		errorMsg := "Delete user from group failed"
 		if err != nil {
@@ -154,6 +157,7 @@ func GetUser(c *gin.Context) {
 			})
 			return
 		}
+		c.Header("ETag", versionETag(user.Version))
 		c.JSON(http.StatusOK, gin.H{
 			"code":      http.StatusOK,
 			"message":   "User retrieved successfully",
@@ -191,7 +195,7 @@ func InsertUser(c *gin.Context) {
 	userInput := validatedInput.(*model.UserInput)
 
 	// Update untuk handle error return dari service
-	status, err := service.InsertUser(userInput)
+	status, err := service.InsertUser(userInput, c.GetString("userID"))
 	if !status {
 		errorMsg := "Insert user failed"
 		if err != nil {
@@ -301,6 +305,16 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{
+			"code":    ifMatchStatus(err),
+			"message": "Update user failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
 	validatedInput, exists := c.Get("validatedInput")
 	if !exists {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -314,21 +328,13 @@ func UpdateUser(c *gin.Context) {
 	userInput := validatedInput.(*model.UserInput)
 
 	// Update untuk handle error return dari service (pass usrId as parameter)
-	status, err := service.UpdateUser(usrId, userInput)
+	status, err := service.UpdateUser(usrId, userInput, version, c.GetString("userID"))
 	if !status {
-		errorMsg := "Update user failed"
-		if err != nil {
-			errorMsg = err.Error()
-		}
-		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    http.StatusInternalServerError,
-			"message": "Update user failed",
-			"error":   errorMsg,
-		})
+		respondUserUpdateError(c, err)
 		return
 	}
 
+	c.Header("ETag", versionETag(version+1))
 	c.JSON(http.StatusOK, gin.H{
 		"code":    http.StatusOK,
 		"message": "User updated successfully",
@@ -339,6 +345,81 @@ func UpdateUser(c *gin.Context) {
 	})
 }
 
+// PatchUser hanya mengubah field yang dikirim di body
+func PatchUser(c *gin.Context) {
+	usrId := c.Param("usrId")
+	if usrId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "User ID is required",
+			"error":   "User ID parameter is not provided",
+		})
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(ifMatchStatus(err), gin.H{
+			"code":    ifMatchStatus(err),
+			"message": "Update user failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var userInput model.UserPatchInput
+	if err := c.ShouldBindJSON(&userInput); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Invalid input data",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err := middleware.Validator.Struct(userInput); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	status, err := service.PatchUser(usrId, &userInput, version, c.GetString("userID"))
+	if !status {
+		respondUserUpdateError(c, err)
+		return
+	}
+
+	c.Header("ETag", versionETag(version+1))
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "User updated successfully",
+		"data": gin.H{
+			"userId": usrId,
+		},
+	})
+}
+
+func respondUserUpdateError(c *gin.Context, err error) {
+	code := http.StatusInternalServerError
+	errorMsg := "Update user failed"
+	if err != nil {
+		errorMsg = err.Error()
+		if errors.Is(err, service.ErrVersionConflict) {
+			code = http.StatusPreconditionFailed
+		} else if errors.Is(err, service.ErrUserChangeForbidden) {
+			code = http.StatusForbidden
+		}
+	}
+
+	c.JSON(code, gin.H{
+		"code":    code,
+		"message": "Update user failed",
+		"error":   errorMsg,
+	})
+}
+
 func DeleteUser(c *gin.Context) {
 	usrId := c.Param("usrId")
 	if usrId == "" {
@@ -351,7 +432,7 @@ func DeleteUser(c *gin.Context) {
 	}
 
 	// Update untuk handle error return dari service
-	status, err := service.DeleteUser(usrId)
+	status, err := service.DeleteUser(usrId, c.GetString("userID"))
 	if !status {
 		errorMsg := "Delete user failed"
 		if err != nil {
@@ -414,6 +495,34 @@ func GetUserProfile(c *gin.Context) {
 	})
 }
 
+func GetUserHistory(c *gin.Context) {
+	usrId := c.Param("usrId")
+	if usrId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    http.StatusBadRequest,
+			"message": "User ID is required",
+			"error":   "User ID parameter is not provided",
+		})
+		return
+	}
+
+	history, err := service.GetUserHistory(usrId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    http.StatusNotFound,
+			"message": "Failed to retrieve user history",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    http.StatusOK,
+		"message": "User history retrieved successfully",
+		"data":    history,
+	})
+}
+
 // Helper function untuk validasi input (opsional)
 func validateRegisterInput(input *model.RegisterInput) error {
 	if input.Username == "" {
@@ -82,6 +82,57 @@ func CloseCore() {
 func AutoMigrate() {
 	err := DbCore.AutoMigrate(
 		&model.User{}, // Menggunakan model User yang sudah didefinisikan
+		&model.Outlet{},
+		&model.OutletHours{},
+		&model.OutletSpecialDay{},
+		&model.OutletDeliveryArea{},
+		&model.Brand{},
+		&model.Region{},
+		&model.UserFile{},
+		&model.AuditLog{},
+		&model.ProductCategory{},
+		&model.Product{},
+		&model.ProductVariant{},
+		&model.ProductModifier{},
+		&model.ProductOutletPrice{},
+		&model.StockMovement{},
+		&model.StockLevel{},
+		&model.DocumentSequence{},
+		&model.StockTransfer{},
+		&model.StockTransferLine{},
+		&model.StockCount{},
+		&model.StockCountLine{},
+		&model.Sale{},
+		&model.SaleLine{},
+		&model.SaleTender{},
+		&model.SaleReversal{},
+		&model.SaleReversalLine{},
+		&model.SaleRefund{},
+		&model.Invoice{},
+		&model.InvoiceLine{},
+		&model.Customer{},
+		&model.LoyaltyEarnRule{},
+		&model.LoyaltyTier{},
+		&model.LoyaltyTransaction{},
+		&model.LoyaltyTierChange{},
+		&model.Promotion{},
+		&model.SalePromotion{},
+		&model.Receivable{},
+		&model.ReceivablePayment{},
+		&model.ExpenseCategory{},
+		&model.Expense{},
+		&model.Employee{},
+		&model.EmployeeComponent{},
+		&model.EmployeeOvertime{},
+		&model.PayrollRun{},
+		&model.Payslip{},
+		&model.PayslipLine{},
+		&model.ShiftSchedule{},
+		&model.Attendance{},
+		&model.CashShift{},
+		&model.CashShiftDenomination{},
+		&model.CashShiftMovement{},
+		&model.DailyClosing{},
 		// Tambahkan model lain di sini jika ada
 	)
 	if err != nil {
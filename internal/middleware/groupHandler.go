@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+// RequireGroup only lets the request through when the logged in user belongs
+// to one of the given groups. It must run after JWTAuthMiddleware.
+func RequireGroup(groups ...int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+
+		var user model.User
+		if err := database.DbCore.Where("username = ?", userID).First(&user).Error; err != nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":  http.StatusForbidden,
+				"error": "User not found",
+			})
+			c.Abort()
+			return
+		}
+
+		for _, group := range groups {
+			if user.Group == group {
+				c.Set("userGroup", user.Group)
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"code":  http.StatusForbidden,
+			"error": "You are not allowed to access this resource",
+		})
+		c.Abort()
+	}
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import(
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -21,7 +23,13 @@ func validateStruct(s interface{}) error {
 
 func InputValidator(obj interface{}) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if err := c.ShouldBind(obj); err != nil {
+		// Body dibaca ulang oleh controller, jadi kembalikan setelah binding
+		bodyBytes, _ := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		bindErr := c.ShouldBind(obj)
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+
+		if err := bindErr; err != nil {
 			// LogError(err, "Invalid JSON input")
 			c.JSON(http.StatusOK, gin.H{
 				"code" : http.StatusBadRequest,
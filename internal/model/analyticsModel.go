@@ -20,6 +20,27 @@ type AnalyticsResponse struct {
 	GrowthRate           float64           `json:"growth_rate"`
 }
 
+// Pelanggan baru per bulan, bentuknya sama dengan MonthlyUserData ditambah
+// Period (YYYY-MM) supaya bulan yang sama di dua tahun tidak tertukar
+type MonthlyCustomerData struct {
+	Period    string  `json:"period" gorm:"column:period"`
+	Month     string  `json:"month" gorm:"column:month"`
+	Customers int     `json:"customers" gorm:"column:customers"`
+	Growth    float64 `json:"growth" gorm:"column:growth"`
+}
+
+// Query string GET /v1/analytics/new-customers
+type CustomerAnalyticsQuery struct {
+	OutletID uint `form:"outletId"` // hanya pelanggan yang pernah belanja di outlet ini
+}
+
+type CustomerAnalyticsResponse struct {
+	MonthlyNewCustomers []MonthlyCustomerData `json:"monthly_new_customers"`
+	TotalCustomers      int                   `json:"total_customers"`
+	CurrentMonth        int                   `json:"current_month"`
+	GrowthRate          float64               `json:"growth_rate"`
+}
+
 type AnalyticsUser struct {
 	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
 	Email     string    `json:"email" gorm:"column:email;uniqueIndex;not null"`
@@ -0,0 +1,135 @@
+package model
+
+import "time"
+
+// ShiftSchedule adalah jadwal kerja satu karyawan pada satu tanggal di sebuah
+// outlet. Jam mulai/selesai disimpan juga sebagai waktu absolut (zona waktu
+// outlet) supaya shift yang melewati tengah malam tetap bisa dibandingkan.
+type ShiftSchedule struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	EmployeeID     uint      `json:"employeeId" gorm:"not null;uniqueIndex:idx_shift_employee_date,priority:1"`
+	Employee       *Employee `json:"employee,omitempty" gorm:"foreignKey:EmployeeID"`
+	OutletID       uint      `json:"outletId" gorm:"not null;index:idx_shift_outlet_date,priority:1"`
+	Date           time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_shift_employee_date,priority:2;index:idx_shift_outlet_date,priority:2"`
+	StartTime      string    `json:"startTime" gorm:"not null;size:5"` // HH:MM
+	EndTime        string    `json:"endTime" gorm:"not null;size:5"`   // HH:MM, <= StartTime berarti selesai besok
+	ScheduledStart time.Time `json:"scheduledStart" gorm:"not null;index"`
+	ScheduledEnd   time.Time `json:"scheduledEnd" gorm:"not null"`
+	Note           string    `json:"note" gorm:"size:255"`
+	CreatedBy      string    `json:"createdBy" gorm:"size:100"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// Attendance adalah satu kali clock-in/clock-out. ScheduleID kosong berarti
+// karyawan masuk di luar jadwal; lemburnya tidak dihitung otomatis. Lembur
+// dibatasi sampai ATTENDANCE_OVERTIME_MAX_MINUTES atau shift berikutnya; clock-out
+// yang melewati batas itu ditandai OvertimeReview dan tidak ikut sync ke payroll
+// sebelum dicek supervisor.
+type Attendance struct {
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	EmployeeID        uint           `json:"employeeId" gorm:"not null;index:idx_attendance_employee_in,priority:1"`
+	Employee          *Employee      `json:"employee,omitempty" gorm:"foreignKey:EmployeeID"`
+	OutletID          uint           `json:"outletId" gorm:"not null;index"`
+	ScheduleID        *uint          `json:"scheduleId" gorm:"uniqueIndex"`
+	Schedule          *ShiftSchedule `json:"schedule,omitempty" gorm:"foreignKey:ScheduleID"`
+	ClockInAt         time.Time      `json:"clockInAt" gorm:"not null;index:idx_attendance_employee_in,priority:2"`
+	ClockInLatitude   float64        `json:"clockInLatitude" gorm:"type:decimal(10,7)"`
+	ClockInLongitude  float64        `json:"clockInLongitude" gorm:"type:decimal(10,7)"`
+	ClockInDistance   int            `json:"clockInDistance"` // meter dari titik outlet
+	ClockOutAt        *time.Time     `json:"clockOutAt" gorm:"index"`
+	ClockOutLatitude  *float64       `json:"clockOutLatitude" gorm:"type:decimal(10,7)"`
+	ClockOutLongitude *float64       `json:"clockOutLongitude" gorm:"type:decimal(10,7)"`
+	ClockOutDistance  *int           `json:"clockOutDistance"`
+	LateMinutes       int            `json:"lateMinutes" gorm:"not null"`
+	EarlyLeaveMinutes int            `json:"earlyLeaveMinutes" gorm:"not null"`
+	WorkedMinutes     int            `json:"workedMinutes" gorm:"not null"`
+	OvertimeMinutes   int            `json:"overtimeMinutes" gorm:"not null"`
+	OvertimeReview    bool           `json:"overtimeReview" gorm:"not null;default:false"`
+	OvertimeReviewBy  string         `json:"overtimeReviewBy" gorm:"size:100"`
+	OvertimeSyncedAt  *time.Time     `json:"overtimeSyncedAt"`         // sudah diteruskan ke lembur payroll
+	ClosedBy          string         `json:"closedBy" gorm:"size:100"` // supervisor yang menutup absensi tanpa clock-out
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt"`
+}
+
+// Body POST /v1/attendance/schedules, beberapa shift sekaligus (mis. jadwal seminggu)
+type ShiftScheduleBatchRequest struct {
+	Shifts []ShiftScheduleRequest `json:"shifts" validate:"required,min=1,max=200,dive"`
+}
+
+type ShiftScheduleRequest struct {
+	EmployeeID uint   `json:"employeeId" validate:"required"`
+	OutletID   uint   `json:"outletId" validate:"required"`
+	Date       string `json:"date" validate:"required,datetime=2006-01-02"`
+	StartTime  string `json:"startTime" validate:"required,datetime=15:04"`
+	EndTime    string `json:"endTime" validate:"required,datetime=15:04"`
+	Note       string `json:"note" validate:"max=255"`
+}
+
+// Query string GET /v1/attendance/schedules
+type ShiftScheduleQuery struct {
+	OutletID   uint   `form:"outletId"`
+	EmployeeID uint   `form:"employeeId"`
+	From       string `form:"from" validate:"required,datetime=2006-01-02"`
+	To         string `form:"to" validate:"required,datetime=2006-01-02"`
+}
+
+// Body POST /v1/attendance/clock-in dan /clock-out. QRCode adalah isi QR absensi
+// yang ditempel di outlet, posisi diambil dari GPS perangkat.
+type ClockRequest struct {
+	QRCode    string   `json:"qrCode" validate:"required,max=500"`
+	Latitude  *float64 `json:"latitude" validate:"required,latitude"`
+	Longitude *float64 `json:"longitude" validate:"required,longitude"`
+}
+
+// Body POST /v1/attendance/:id/close, menutup absensi yang lupa di-clock-out tanpa lembur
+type AttendanceCloseRequest struct {
+	ClockOutAt *time.Time `json:"clockOutAt" validate:"required"`
+}
+
+// Body POST /v1/attendance/:id/overtime-review, menit lembur yang disetujui (0 = ditolak)
+type AttendanceOvertimeReviewRequest struct {
+	OvertimeMinutes *int `json:"overtimeMinutes" validate:"required,min=0"`
+}
+
+// Query string GET /v1/attendance, /me dan /reports
+type AttendanceQuery struct {
+	OutletID   uint   `form:"outletId"`
+	EmployeeID uint   `form:"employeeId"`
+	From       string `form:"from" validate:"required,datetime=2006-01-02"`
+	To         string `form:"to" validate:"required,datetime=2006-01-02"`
+}
+
+// AttendanceEmployeeSummary merangkum kehadiran satu karyawan dalam periode laporan.
+type AttendanceEmployeeSummary struct {
+	EmployeeID        uint   `json:"employeeId"`
+	EmployeeCode      string `json:"employeeCode"`
+	EmployeeName      string `json:"employeeName"`
+	OutletID          uint   `json:"outletId"`
+	ScheduledShifts   int    `json:"scheduledShifts"`
+	AttendedShifts    int    `json:"attendedShifts"`
+	Absences          int    `json:"absences"`
+	UnscheduledDays   int    `json:"unscheduledDays"`
+	LateCount         int    `json:"lateCount"`
+	LateMinutes       int    `json:"lateMinutes"`
+	EarlyLeaveMinutes int    `json:"earlyLeaveMinutes"`
+	WorkedMinutes     int    `json:"workedMinutes"`
+	OvertimeMinutes   int    `json:"overtimeMinutes"`
+	MissingClockOuts  int    `json:"missingClockOuts"`
+}
+
+type AttendanceReport struct {
+	From         time.Time                   `json:"from"`
+	To           time.Time                   `json:"to"`
+	Employees    []AttendanceEmployeeSummary `json:"employees"`
+	Absences     []ShiftSchedule             `json:"absences"`     // shift yang sudah lewat tanpa clock-in
+	LateArrivals []Attendance                `json:"lateArrivals"` // clock-in melewati toleransi
+	Overtime     []Attendance                `json:"overtime"`
+}
+
+type AttendanceOvertimeSyncResult struct {
+	Synced   int `json:"synced"`
+	Minutes  int `json:"minutes"`
+	Deferred int `json:"deferred"` // dipindah ke periode berikutnya karena payroll-nya sudah dikunci
+}
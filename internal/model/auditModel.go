@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	AuditEntityOutlet = "outlet"
+	AuditEntityUser   = "user"
+
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	AuditActionErase  = "erase"
+)
+
+// Satu baris riwayat perubahan sebuah entity, lengkap dengan nilai sebelum/sesudah
+type AuditLog struct {
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	EntityType string        `json:"entityType" gorm:"not null;size:50;index:idx_audit_entity"`
+	EntityID   string        `json:"entityId" gorm:"not null;size:100;index:idx_audit_entity"`
+	Action     string        `json:"action" gorm:"not null;size:20"`
+	ActorID    string        `json:"actorId" gorm:"size:100;index"`
+	Changes    []AuditChange `json:"changes" gorm:"type:json;serializer:json"`
+	CreatedAt  time.Time     `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+type AuditChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
@@ -0,0 +1,158 @@
+package model
+
+import "time"
+
+// Status shift kasir: open -> closed (kas dihitung) -> reconciled (ditandatangani manajer)
+const (
+	CashShiftOpen       = "open"
+	CashShiftClosed     = "closed"
+	CashShiftReconciled = "reconciled"
+)
+
+// Kas masuk/keluar laci di luar transaksi penjualan
+const (
+	CashMovementPayIn  = "pay_in" // tambahan modal/uang kecil
+	CashMovementPayout = "payout" // pembayaran tunai dari laci, mis. beli es batu
+)
+
+// CashShift adalah satu shift laci kasir di outlet. Penjualan tunai selama shift
+// terbuka dicatat ke shift ini; saat ditutup, kas yang dihitung per pecahan
+// dibandingkan dengan kas yang seharusnya ada.
+type CashShift struct {
+	ID                 uint                    `json:"id" gorm:"primaryKey"`
+	Number             string                  `json:"number" gorm:"not null;size:30;uniqueIndex"`
+	OutletID           uint                    `json:"outletId" gorm:"not null;index:idx_cash_shift_outlet_date,priority:1"`
+	CashierID          string                  `json:"cashierId" gorm:"not null;size:100;index"`
+	BusinessDate       time.Time               `json:"businessDate" gorm:"type:date;not null;index:idx_cash_shift_outlet_date,priority:2"` // tanggal buka shift di zona waktu outlet
+	Status             string                  `json:"status" gorm:"not null;size:20;index"`
+	OpeningFloat       int64                   `json:"openingFloat" gorm:"not null"`
+	OpeningNote        string                  `json:"openingNote" gorm:"size:255"`
+	OpenedAt           time.Time               `json:"openedAt" gorm:"not null"`
+	ClosedAt           *time.Time              `json:"closedAt"`
+	ClosingNote        string                  `json:"closingNote" gorm:"size:255"`
+	CashSales          int64                   `json:"cashSales" gorm:"not null"`   // tunai diterima dikurangi kembalian
+	CashRefunds        int64                   `json:"cashRefunds" gorm:"not null"` // refund tunai void/retur
+	PayIns             int64                   `json:"payIns" gorm:"not null"`
+	Payouts            int64                   `json:"payouts" gorm:"not null"`
+	ExpectedCash       int64                   `json:"expectedCash" gorm:"not null"`
+	CountedCash        int64                   `json:"countedCash" gorm:"not null"`
+	Discrepancy        int64                   `json:"discrepancy" gorm:"not null"` // counted - expected; minus berarti kurang
+	DiscrepancyFlagged bool                    `json:"discrepancyFlagged" gorm:"not null;index"`
+	SignedOffBy        string                  `json:"signedOffBy" gorm:"size:100"`
+	SignedOffAt        *time.Time              `json:"signedOffAt"`
+	SignOffNote        string                  `json:"signOffNote" gorm:"size:255"`
+	Denominations      []CashShiftDenomination `json:"denominations,omitempty" gorm:"foreignKey:ShiftID"`
+	Movements          []CashShiftMovement     `json:"movements,omitempty" gorm:"foreignKey:ShiftID"`
+	CreatedAt          time.Time               `json:"createdAt"`
+	UpdatedAt          time.Time               `json:"updatedAt"`
+}
+
+// CashShiftDenomination adalah jumlah lembar/keping satu pecahan saat tutup shift.
+type CashShiftDenomination struct {
+	ID      uint  `json:"id" gorm:"primaryKey"`
+	ShiftID uint  `json:"shiftId" gorm:"not null;index"`
+	Value   int64 `json:"value" gorm:"not null"`
+	Count   int   `json:"count" gorm:"not null"`
+	Amount  int64 `json:"amount" gorm:"not null"`
+}
+
+type CashShiftMovement struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ShiftID   uint      `json:"shiftId" gorm:"not null;index"`
+	Type      string    `json:"type" gorm:"not null;size:20"`
+	Amount    int64     `json:"amount" gorm:"not null"`
+	Reason    string    `json:"reason" gorm:"not null;size:255"`
+	CreatedBy string    `json:"createdBy" gorm:"size:100"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DailyClosing menutup hari bisnis satu outlet setelah semua shift-nya
+// direkonsiliasi. Setelah ditutup, shift baru tidak bisa dibuka dan penjualan,
+// void maupun retur tidak bisa diposting di tanggal itu.
+type DailyClosing struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	OutletID     uint      `json:"outletId" gorm:"not null;uniqueIndex:idx_daily_closing_outlet_date,priority:1"`
+	BusinessDate time.Time `json:"businessDate" gorm:"type:date;not null;uniqueIndex:idx_daily_closing_outlet_date,priority:2"`
+	ShiftCount   int       `json:"shiftCount" gorm:"not null"`
+	ExpectedCash int64     `json:"expectedCash" gorm:"not null"`
+	CountedCash  int64     `json:"countedCash" gorm:"not null"`
+	Discrepancy  int64     `json:"discrepancy" gorm:"not null"`
+	Note         string    `json:"note" gorm:"size:255"`
+	ClosedBy     string    `json:"closedBy" gorm:"size:100"`
+	ClosedAt     time.Time `json:"closedAt" gorm:"not null"`
+}
+
+type CashShiftOpenRequest struct {
+	OutletID     uint   `json:"outletId" validate:"required"`
+	OpeningFloat int64  `json:"openingFloat" validate:"min=0"`
+	Note         string `json:"note" validate:"max=255"`
+}
+
+type CashShiftMovementRequest struct {
+	Type   string `json:"type" validate:"required,oneof=pay_in payout"`
+	Amount int64  `json:"amount" validate:"required,min=1"`
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// Body POST /v1/cash-shifts/:id/close, jumlah lembar/keping per pecahan rupiah
+type CashShiftCloseRequest struct {
+	Denominations []CashDenominationInput `json:"denominations" validate:"max=20,dive"`
+	Note          string                  `json:"note" validate:"max=255"`
+}
+
+type CashDenominationInput struct {
+	Value int64 `json:"value" validate:"required,oneof=100000 50000 20000 10000 5000 2000 1000 500 200 100"`
+	Count int   `json:"count" validate:"min=0"`
+}
+
+type CashShiftSignOffRequest struct {
+	Note string `json:"note" validate:"max=255"` // wajib jika selisih ditandai
+}
+
+// Query string GET /v1/cash-shifts
+type CashShiftListQuery struct {
+	OutletID  uint   `form:"outletId"`
+	CashierID string `form:"cashierId"`
+	Status    string `form:"status" validate:"omitempty,oneof=open closed reconciled"`
+	Flagged   *bool  `form:"flagged"`
+	From      string `form:"from" validate:"omitempty,datetime=2006-01-02"` // tanggal bisnis
+	To        string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+	Page      int    `form:"page" validate:"omitempty,min=1"`
+	PageSize  int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type CashShiftListResult struct {
+	Shifts     []CashShift `json:"shifts"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+type DailyClosingRequest struct {
+	OutletID uint   `json:"outletId" validate:"required"`
+	Date     string `json:"date" validate:"required,datetime=2006-01-02"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+// Query string GET /v1/cash-shifts/daily-closings
+type DailyClosingQuery struct {
+	OutletID uint   `form:"outletId"`
+	From     string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type CashShiftTenderTotal struct {
+	Method string `json:"method"`
+	Count  int    `json:"count"`
+	Amount int64  `json:"amount"`
+}
+
+// CashShiftReport adalah data yang dicetak ke PDF laporan shift.
+type CashShiftReport struct {
+	CompanyName  string
+	OutletName   string
+	Shift        CashShift
+	SaleCount    int
+	GrossSales   int64
+	TenderTotals []CashShiftTenderTotal
+	Tolerance    int64
+	PrintedAt    time.Time
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Customer (pelanggan) berlaku untuk semua outlet. Phone disimpan dalam format
+// 62xxxxxxxxxx dan email dalam huruf kecil, keduanya unik supaya satu orang
+// tidak tercatat dua kali. NULL berarti tidak diisi.
+type Customer struct {
+	ID       uint       `json:"id" gorm:"primaryKey"`
+	Name     string     `json:"name" gorm:"not null;size:255;index"`
+	Phone    *string    `json:"phone" gorm:"size:20;uniqueIndex"`
+	Email    *string    `json:"email" gorm:"size:255;uniqueIndex"`
+	Birthday *time.Time `json:"birthday" gorm:"type:date"`
+	Tags     []string   `json:"tags" gorm:"type:json;serializer:json"`
+	Notes    string     `json:"notes" gorm:"type:text"`
+	// Saldo poin loyalti (cache dari loyalty_transactions) dan tier saat ini
+	Points int64 `json:"points" gorm:"not null;default:0"`
+	TierID *uint `json:"tierId" gorm:"index"`
+	// Penjualan kredit (B2B): 0 berarti pelanggan tidak boleh berhutang
+	CreditLimit     int64 `json:"creditLimit" gorm:"not null;default:0"`
+	PaymentTermDays int   `json:"paymentTermDays" gorm:"not null;default:0"` // 0 = tempo default
+	// Diisi saat pelanggan ini digabung ke pelanggan lain
+	MergedIntoID *uint          `json:"mergedIntoId,omitempty" gorm:"index"`
+	CreatedBy    string         `json:"createdBy" gorm:"size:100"`
+	CreatedAt    time.Time      `json:"createdAt" gorm:"index"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type CustomerRequest struct {
+	Name     string   `json:"name" validate:"required,min=2,max=255"`
+	Phone    string   `json:"phone" validate:"required_without=Email,omitempty,min=8,max=20"`
+	Email    string   `json:"email" validate:"required_without=Phone,omitempty,email,max=255"`
+	Birthday string   `json:"birthday" validate:"omitempty,datetime=2006-01-02"`
+	Tags     []string `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50"`
+	Notes    string   `json:"notes" validate:"max=2000"`
+	// Batas kredit & tempo pembayaran untuk penjualan kredit
+	CreditLimit     int64 `json:"creditLimit" validate:"min=0"`
+	PaymentTermDays int   `json:"paymentTermDays" validate:"min=0,max=365"`
+}
+
+// Body POST /v1/customers/:id/merge, pelanggan sourceId digabung ke :id
+type CustomerMergeRequest struct {
+	SourceID uint `json:"sourceId" validate:"required"`
+}
+
+// Query string GET /v1/customers
+type CustomerListQuery struct {
+	Search   string `form:"search"` // nama, nomor HP atau email
+	Tag      string `form:"tag"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type CustomerListResult struct {
+	Customers  []Customer `json:"customers"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Ringkasan belanja pelanggan dari transaksi yang tidak di-void
+type CustomerPurchaseSummary struct {
+	Transactions  int64      `json:"transactions"`
+	TotalSpent    int64      `json:"totalSpent"`
+	FirstPurchase *time.Time `json:"firstPurchase"`
+	LastPurchase  *time.Time `json:"lastPurchase"`
+}
+
+type CustomerDetail struct {
+	Customer
+	Purchases CustomerPurchaseSummary `json:"purchases"`
+}
+
+// Query string GET /v1/customers/:id/sales
+type CustomerSaleQuery struct {
+	Page     int `form:"page" validate:"omitempty,min=1"`
+	PageSize int `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
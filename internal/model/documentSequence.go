@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Counter nomor dokumen (transfer, struk, invoice, ...). Name berisi jenis dokumen
+// beserta periodenya, mis. "stock_transfer:2026", agar penomoran tidak bolong.
+type DocumentSequence struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null;size:100;uniqueIndex"`
+	LastValue uint      `json:"lastValue" gorm:"not null;default:0"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
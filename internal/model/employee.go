@@ -0,0 +1,119 @@
+package model
+
+import "time"
+
+// Jenis komponen gaji tetap per karyawan
+const (
+	SalaryAllowance = "allowance" // tunjangan, menambah gaji
+	SalaryDeduction = "deduction" // potongan, mengurangi gaji
+)
+
+// Sumber data lembur
+const (
+	OvertimeManual     = "manual"
+	OvertimeAttendance = "attendance"
+)
+
+// Employee adalah karyawan yang digaji lewat payroll. UserID menghubungkan
+// karyawan dengan akun login (opsional). Karyawan yang keluar dinonaktifkan,
+// tidak dihapus, supaya slip gaji lamanya tetap lengkap. EndDate adalah hari
+// kerja terakhirnya; gaji pokok bulan masuk/keluar dihitung proporsional.
+type Employee struct {
+	ID           uint                `json:"id" gorm:"primaryKey"`
+	Code         string              `json:"code" gorm:"not null;size:30;uniqueIndex"`
+	Name         string              `json:"name" gorm:"not null;size:255;index"`
+	Email        string              `json:"email" gorm:"size:255"`
+	Phone        string              `json:"phone" gorm:"size:30"`
+	OutletID     uint                `json:"outletId" gorm:"not null;index"`
+	Position     string              `json:"position" gorm:"size:100"`
+	UserID       *string             `json:"userId" gorm:"size:100;uniqueIndex"`
+	JoinDate     time.Time           `json:"joinDate" gorm:"type:date;not null"`
+	EndDate      *time.Time          `json:"endDate" gorm:"type:date;index"`
+	Active       bool                `json:"active" gorm:"not null;index"`
+	BaseSalary   int64               `json:"baseSalary" gorm:"not null"`
+	OvertimeRate int64               `json:"overtimeRate" gorm:"not null;default:0"` // upah lembur per jam, 0 = gaji pokok / 173
+	BankName     string              `json:"bankName" gorm:"size:100"`
+	BankAccount  string              `json:"bankAccount" gorm:"size:50"`
+	Components   []EmployeeComponent `json:"components,omitempty" gorm:"foreignKey:EmployeeID"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	UpdatedAt    time.Time           `json:"updatedAt"`
+}
+
+// EmployeeComponent adalah tunjangan/potongan tetap bulanan seorang karyawan.
+type EmployeeComponent struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	EmployeeID uint   `json:"employeeId" gorm:"not null;index"`
+	Name       string `json:"name" gorm:"not null;size:100"`
+	Type       string `json:"type" gorm:"not null;size:20"`
+	Amount     int64  `json:"amount" gorm:"not null"`
+}
+
+type EmployeeRequest struct {
+	Code         string  `json:"code" validate:"required,max=30"`
+	Name         string  `json:"name" validate:"required,max=255"`
+	Email        string  `json:"email" validate:"omitempty,email,max=255"`
+	Phone        string  `json:"phone" validate:"max=30"`
+	OutletID     uint    `json:"outletId" validate:"required"`
+	Position     string  `json:"position" validate:"max=100"`
+	UserID       *string `json:"userId" validate:"omitempty,max=100"`
+	JoinDate     string  `json:"joinDate" validate:"required,datetime=2006-01-02"`
+	EndDate      string  `json:"endDate" validate:"omitempty,datetime=2006-01-02"` // kosong + active=false = keluar hari ini
+	Active       *bool   `json:"active"`
+	BaseSalary   int64   `json:"baseSalary" validate:"min=0"`
+	OvertimeRate int64   `json:"overtimeRate" validate:"min=0"`
+	BankName     string  `json:"bankName" validate:"max=100"`
+	BankAccount  string  `json:"bankAccount" validate:"max=50"`
+}
+
+// Body PUT /v1/employees/:id/components, menggantikan seluruh komponen
+type EmployeeComponentsRequest struct {
+	Components []EmployeeComponentInput `json:"components" validate:"max=50,dive"`
+}
+
+type EmployeeComponentInput struct {
+	Name   string `json:"name" validate:"required,max=100"`
+	Type   string `json:"type" validate:"required,oneof=allowance deduction"`
+	Amount int64  `json:"amount" validate:"required,min=1"`
+}
+
+// Query string GET /v1/employees
+type EmployeeListQuery struct {
+	Search   string `form:"search"` // kode atau nama
+	OutletID uint   `form:"outletId"`
+	Active   *bool  `form:"active"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type EmployeeListResult struct {
+	Employees  []Employee `json:"employees"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// EmployeeOvertime adalah jam lembur satu karyawan pada satu hari. PayrollRunID
+// terisi setelah lembur ini dibayar di payroll yang dikunci; sejak itu tidak bisa
+// diubah lagi.
+type EmployeeOvertime struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	EmployeeID   uint      `json:"employeeId" gorm:"not null;index:idx_overtime_employee_date,priority:1"`
+	Date         time.Time `json:"date" gorm:"type:date;not null;index:idx_overtime_employee_date,priority:2"`
+	Minutes      int       `json:"minutes" gorm:"not null"`
+	Source       string    `json:"source" gorm:"not null;size:20"`
+	Note         string    `json:"note" gorm:"size:255"`
+	PayrollRunID *uint     `json:"payrollRunId" gorm:"index"`
+	CreatedBy    string    `json:"createdBy" gorm:"size:100"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+type EmployeeOvertimeRequest struct {
+	Date    string `json:"date" validate:"required,datetime=2006-01-02"`
+	Minutes int    `json:"minutes" validate:"required,min=1,max=1440"`
+	Note    string `json:"note" validate:"max=255"`
+}
+
+// Query string GET /v1/employees/:id/overtime
+type EmployeeOvertimeQuery struct {
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status pengeluaran. Pengeluaran sampai batas EXPENSE_APPROVAL_THRESHOLD langsung
+// approved; di atasnya pending sampai disetujui/ditolak supervisor.
+const (
+	ExpensePending  = "pending"
+	ExpenseApproved = "approved"
+	ExpenseRejected = "rejected"
+)
+
+type ExpenseCategory struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null;size:100;uniqueIndex"`
+	Description string    `json:"description" gorm:"size:255"`
+	Active      bool      `json:"active" gorm:"not null"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type ExpenseCategoryRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=255"`
+	Active      *bool  `json:"active"`
+}
+
+// Expense (pengeluaran operasional) sebuah outlet. Foto/scan nota disimpan di
+// bucket dengan key ReceiptKey; ReceiptURL adalah link sementara untuk diunduh.
+type Expense struct {
+	ID               uint             `json:"id" gorm:"primaryKey"`
+	Number           string           `json:"number" gorm:"not null;size:30;uniqueIndex"`
+	OutletID         uint             `json:"outletId" gorm:"not null;index"`
+	CategoryID       uint             `json:"categoryId" gorm:"not null;index"`
+	Category         *ExpenseCategory `json:"category,omitempty"`
+	Amount           int64            `json:"amount" gorm:"not null"`
+	ExpenseDate      time.Time        `json:"expenseDate" gorm:"type:date;not null;index"`
+	PaymentMethod    string           `json:"paymentMethod" gorm:"not null;size:20"`
+	Vendor           string           `json:"vendor" gorm:"size:255"`
+	Description      string           `json:"description" gorm:"size:500"`
+	ReceiptKey       string           `json:"-" gorm:"size:255"`
+	ReceiptURL       string           `json:"receiptUrl,omitempty" gorm:"-"`
+	Status           string           `json:"status" gorm:"not null;size:20;index"`
+	RequiresApproval bool             `json:"requiresApproval" gorm:"not null"`
+	SubmittedBy      string           `json:"submittedBy" gorm:"size:100"`
+	ReviewedBy       string           `json:"reviewedBy" gorm:"size:100"`
+	ReviewedAt       *time.Time       `json:"reviewedAt"`
+	ReviewNote       string           `json:"reviewNote" gorm:"size:255"`
+	CreatedAt        time.Time        `json:"createdAt"`
+	UpdatedAt        time.Time        `json:"updatedAt"`
+	DeletedAt        gorm.DeletedAt   `json:"-" gorm:"index"`
+}
+
+type ExpenseRequest struct {
+	OutletID      uint   `json:"outletId" validate:"required"`
+	CategoryID    uint   `json:"categoryId" validate:"required"`
+	Amount        int64  `json:"amount" validate:"required,min=1"`
+	ExpenseDate   string `json:"expenseDate" validate:"required,datetime=2006-01-02"`
+	PaymentMethod string `json:"paymentMethod" validate:"required,oneof=cash transfer card qris ewallet"`
+	Vendor        string `json:"vendor" validate:"max=255"`
+	Description   string `json:"description" validate:"max=500"`
+}
+
+type ExpenseReviewRequest struct {
+	Note string `json:"note" validate:"max=255"`
+}
+
+// Query string GET /v1/expenses
+type ExpenseListQuery struct {
+	OutletID   uint   `form:"outletId"`
+	CategoryID uint   `form:"categoryId"`
+	Status     string `form:"status" validate:"omitempty,oneof=pending approved rejected"`
+	From       string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To         string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+	Page       int    `form:"page" validate:"omitempty,min=1"`
+	PageSize   int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type ExpenseListResult struct {
+	Expenses    []Expense  `json:"expenses"`
+	TotalAmount int64      `json:"totalAmount"` // jumlah seluruh hasil filter, bukan hanya halaman ini
+	Pagination  Pagination `json:"pagination"`
+}
+
+// Query string GET /v1/analytics/net-revenue
+type NetRevenueQuery struct {
+	From     string `form:"from" validate:"omitempty,datetime=2006-01-02"` // default awal bulan ini
+	To       string `form:"to" validate:"omitempty,datetime=2006-01-02"`   // default hari ini
+	OutletID uint   `form:"outletId"`
+}
+
+type ExpenseCategoryTotal struct {
+	CategoryID uint   `json:"categoryId"`
+	Name       string `json:"name"`
+	Amount     int64  `json:"amount"`
+}
+
+// NetRevenueSummary adalah sumber angka "Omset", "Pengeluaran" dan "Pendapatan
+// Bersih" di dashboard. NetRevenue = GrossSales - Returns - Expenses; hanya
+// pengeluaran yang sudah approved yang dihitung.
+type NetRevenueSummary struct {
+	From               string                 `json:"from"`
+	To                 string                 `json:"to"`
+	GrossSales         int64                  `json:"grossSales"` // total transaksi yang tidak di-void
+	Returns            int64                  `json:"returns"`    // refund retur yang disetujui dalam periode
+	NetSales           int64                  `json:"netSales"`
+	Expenses           int64                  `json:"expenses"`
+	PendingExpenses    int64                  `json:"pendingExpenses"` // belum disetujui, belum mengurangi
+	ExpensesByCategory []ExpenseCategoryTotal `json:"expensesByCategory"`
+	NetRevenue         int64                  `json:"netRevenue"`
+}
@@ -0,0 +1,102 @@
+package model
+
+import "time"
+
+// Jenis pergerakan stok. Quantity di ledger bertanda: positif = masuk, negatif = keluar.
+const (
+	StockMovementPurchase   = "purchase"
+	StockMovementSale       = "sale"
+	StockMovementAdjustment = "adjustment"
+	StockMovementTransfer   = "transfer"
+	StockMovementReturn     = "return"
+	StockMovementWaste      = "waste"
+)
+
+// Reference type di ledger stok untuk movement yang dicatat manual lewat
+// POST /v1/inventory/movements (tanpa dokumen sumber)
+const StockReferenceManual = "manual"
+
+// StockMovement adalah ledger append-only; stok tidak pernah diubah langsung.
+// VariantID 0 berarti produk dasar (tanpa varian).
+type StockMovement struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	OutletID      uint      `json:"outletId" gorm:"not null;index:idx_stock_movement_item,priority:1"`
+	ProductID     uint      `json:"productId" gorm:"not null;index:idx_stock_movement_item,priority:2"`
+	VariantID     uint      `json:"variantId" gorm:"not null;default:0;index:idx_stock_movement_item,priority:3"`
+	Type          string    `json:"type" gorm:"not null;size:20;index"`
+	Quantity      float64   `json:"quantity" gorm:"type:decimal(15,3);not null"`
+	ReferenceType string    `json:"referenceType" gorm:"size:30;index:idx_stock_movement_reference"`
+	ReferenceID   uint      `json:"referenceId" gorm:"index:idx_stock_movement_reference"`
+	Note          string    `json:"note" gorm:"size:255"`
+	ActorID       string    `json:"actorId" gorm:"size:100"`
+	OccurredAt    time.Time `json:"occurredAt" gorm:"not null;index"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// StockLevel adalah cache saldo ledger per outlet/produk/varian beserta ambang stok minimum
+type StockLevel struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	OutletID          uint      `json:"outletId" gorm:"not null;uniqueIndex:idx_stock_level_item,priority:1"`
+	ProductID         uint      `json:"productId" gorm:"not null;uniqueIndex:idx_stock_level_item,priority:2"`
+	VariantID         uint      `json:"variantId" gorm:"not null;default:0;uniqueIndex:idx_stock_level_item,priority:3"`
+	Quantity          float64   `json:"quantity" gorm:"type:decimal(15,3);not null;default:0"`
+	LowStockThreshold *float64  `json:"lowStockThreshold" gorm:"type:decimal(15,3)"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// StockMovementInput adalah movement manual. Penjualan, transfer dan retur hanya
+// lewat dokumennya masing-masing; adjustment hanya untuk supervisor.
+type StockMovementInput struct {
+	OutletID   uint       `json:"outletId" validate:"required"`
+	ProductID  uint       `json:"productId" validate:"required"`
+	VariantID  uint       `json:"variantId"`
+	Type       string     `json:"type" validate:"required,oneof=purchase adjustment waste"`
+	Quantity   float64    `json:"quantity" validate:"required,ne=0"`
+	Note       string     `json:"note" validate:"max=255"`
+	OccurredAt *time.Time `json:"occurredAt"`
+}
+
+type StockMovementRequest struct {
+	Movements []StockMovementInput `json:"movements" validate:"required,min=1,max=500,dive"`
+}
+
+type StockThresholdRequest struct {
+	OutletID          uint     `json:"outletId" validate:"required"`
+	ProductID         uint     `json:"productId" validate:"required"`
+	VariantID         uint     `json:"variantId"`
+	LowStockThreshold *float64 `json:"lowStockThreshold" validate:"omitempty,min=0"` // null = hapus ambang
+}
+
+// Query string GET /v1/inventory/stock
+type StockQuery struct {
+	OutletID  uint   `form:"outletId"`
+	ProductID uint   `form:"productId"`
+	AsOf      string `form:"asOf" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	LowOnly   bool   `form:"lowOnly"`
+}
+
+// Query string GET /v1/inventory/movements
+type StockMovementQuery struct {
+	OutletID  uint   `form:"outletId"`
+	ProductID uint   `form:"productId"`
+	VariantID *uint  `form:"variantId"`
+	Type      string `form:"type" validate:"omitempty,oneof=purchase sale adjustment transfer return waste"`
+	From      string `form:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To        string `form:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Page      int    `form:"page" validate:"omitempty,min=1"`
+	PageSize  int    `form:"pageSize" validate:"omitempty,min=1,max=200"`
+}
+
+type StockOnHand struct {
+	OutletID          uint     `json:"outletId"`
+	ProductID         uint     `json:"productId"`
+	VariantID         uint     `json:"variantId"`
+	Quantity          float64  `json:"quantity"`
+	LowStockThreshold *float64 `json:"lowStockThreshold"`
+	IsLow             bool     `json:"isLow"`
+}
+
+type StockMovementListResult struct {
+	Movements  []StockMovement `json:"movements"`
+	Pagination Pagination      `json:"pagination"`
+}
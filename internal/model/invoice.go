@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// Invoice diterbitkan dari satu transaksi penjualan. Nomor urut tanpa lompatan per
+// outlet per tahun; invoice tidak pernah dihapus. Data penjual, pembeli dan item
+// disalin saat terbit supaya cetak ulang selalu sama.
+type Invoice struct {
+	ID              uint          `json:"id" gorm:"primaryKey"`
+	Number          string        `json:"number" gorm:"not null;size:50;uniqueIndex"`
+	OutletID        uint          `json:"outletId" gorm:"not null;index:idx_invoice_outlet_year,priority:1"`
+	Year            int           `json:"year" gorm:"not null;index:idx_invoice_outlet_year,priority:2"`
+	Sequence        uint          `json:"sequence" gorm:"not null"`
+	SaleID          uint          `json:"saleId" gorm:"not null;uniqueIndex"`
+	IssuedAt        time.Time     `json:"issuedAt" gorm:"not null"`
+	DueDate         *time.Time    `json:"dueDate" gorm:"type:date"`
+	SellerName      string        `json:"sellerName" gorm:"size:255"`
+	SellerAddress   string        `json:"sellerAddress" gorm:"size:500"`
+	SellerTaxID     string        `json:"sellerTaxId" gorm:"size:30"` // NPWP penjual
+	OutletName      string        `json:"outletName" gorm:"size:255"`
+	OutletAddress   string        `json:"outletAddress" gorm:"type:text"`
+	CustomerName    string        `json:"customerName" gorm:"not null;size:255"`
+	CustomerAddress string        `json:"customerAddress" gorm:"size:500"`
+	CustomerTaxID   string        `json:"customerTaxId" gorm:"size:30"` // NPWP pembeli
+	CustomerEmail   string        `json:"customerEmail" gorm:"size:255"`
+	CustomerPhone   string        `json:"customerPhone" gorm:"size:30"`
+	Subtotal        int64         `json:"subtotal" gorm:"not null"`
+	DiscountTotal   int64         `json:"discountTotal" gorm:"not null"`
+	TaxTotal        int64         `json:"taxTotal" gorm:"not null"`
+	Total           int64         `json:"total" gorm:"not null"`
+	Note            string        `json:"note" gorm:"size:500"`
+	ArchiveKey      string        `json:"archiveKey" gorm:"size:255"` // key PDF di bucket
+	CreatedBy       string        `json:"createdBy" gorm:"size:100"`
+	Lines           []InvoiceLine `json:"lines" gorm:"foreignKey:InvoiceID"`
+	CreatedAt       time.Time     `json:"createdAt"`
+	UpdatedAt       time.Time     `json:"updatedAt"`
+}
+
+type InvoiceLine struct {
+	ID          uint    `json:"id" gorm:"primaryKey"`
+	InvoiceID   uint    `json:"invoiceId" gorm:"not null;index"`
+	Description string  `json:"description" gorm:"not null;size:255"`
+	SKU         string  `json:"sku" gorm:"column:sku;size:64"`
+	Quantity    float64 `json:"quantity" gorm:"type:decimal(15,3);not null"`
+	UnitPrice   int64   `json:"unitPrice" gorm:"not null"`
+	Discount    int64   `json:"discount" gorm:"not null"`
+	TaxRate     float64 `json:"taxRate" gorm:"type:decimal(5,2);not null"`
+	TaxAmount   int64   `json:"taxAmount" gorm:"not null"`
+	LineTotal   int64   `json:"lineTotal" gorm:"not null"`
+}
+
+type InvoiceRequest struct {
+	SaleID          uint   `json:"saleId" validate:"required"`
+	CustomerName    string `json:"customerName" validate:"required,max=255"`
+	CustomerAddress string `json:"customerAddress" validate:"max=500"`
+	CustomerTaxID   string `json:"customerTaxId" validate:"max=30"`
+	CustomerEmail   string `json:"customerEmail" validate:"omitempty,email,max=255"`
+	CustomerPhone   string `json:"customerPhone" validate:"max=30"`
+	DueDate         string `json:"dueDate" validate:"omitempty,datetime=2006-01-02"`
+	Note            string `json:"note" validate:"max=500"`
+}
+
+// Query string GET /v1/invoices
+type InvoiceListQuery struct {
+	OutletID uint   `form:"outletId"`
+	Year     int    `form:"year" validate:"omitempty,min=2000,max=9999"`
+	Search   string `form:"search"` // nomor invoice atau nama pembeli
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type InvoiceListResult struct {
+	Invoices   []Invoice  `json:"invoices"`
+	Pagination Pagination `json:"pagination"`
+}
@@ -0,0 +1,119 @@
+package model
+
+import "time"
+
+// Jenis baris ledger poin. Baris positif (earn, refund, adjust +) punya sisa
+// (Remaining) yang dipakai FIFO berdasarkan tanggal hangus.
+const (
+	LoyaltyEarn     = "earn"     // poin dari transaksi
+	LoyaltyRedeem   = "redeem"   // ditukar sebagai tender di kasir
+	LoyaltyExpire   = "expire"   // hangus
+	LoyaltyReversal = "reversal" // poin transaksi ditarik karena void/retur
+	LoyaltyRefund   = "refund"   // poin yang ditukar dikembalikan karena void/retur
+	LoyaltyAdjust   = "adjust"   // koreksi manual admin
+)
+
+// Reference type ledger poin untuk baris yang berasal dari transaksi penjualan
+const LoyaltyReferenceSale = "sale"
+
+// Aturan perolehan poin: setiap SpendAmount rupiah (tanpa pajak) mendapat Points
+// poin. Aturan dengan CategoryID berlaku untuk produk kategori itu, aturan tanpa
+// kategori menjadi default untuk produk lainnya.
+type LoyaltyEarnRule struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null;size:100"`
+	CategoryID  *uint     `json:"categoryId" gorm:"index"`
+	SpendAmount int64     `json:"spendAmount" gorm:"not null"`
+	Points      int64     `json:"points" gorm:"not null"`
+	Active      bool      `json:"active" gorm:"not null"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Tier ditentukan dari total belanja bergulir; tier dengan MinSpend tertinggi
+// yang terpenuhi yang berlaku. Multiplier dikalikan ke poin yang didapat.
+type LoyaltyTier struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Name       string    `json:"name" gorm:"not null;size:50;uniqueIndex"`
+	MinSpend   int64     `json:"minSpend" gorm:"not null;uniqueIndex"`
+	Multiplier float64   `json:"multiplier" gorm:"type:decimal(4,2);not null"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// LoyaltyTransaction adalah ledger poin, tidak pernah diubah kecuali Remaining.
+// Saldo pelanggan = SUM(points).
+type LoyaltyTransaction struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	CustomerID    uint       `json:"customerId" gorm:"not null;index"`
+	Type          string     `json:"type" gorm:"not null;size:20;index"`
+	Points        int64      `json:"points" gorm:"not null"`
+	Remaining     int64      `json:"remaining" gorm:"not null;default:0"`
+	ExpiresAt     *time.Time `json:"expiresAt" gorm:"index"`
+	ReferenceType string     `json:"referenceType" gorm:"size:30;index:idx_loyalty_reference,priority:1"`
+	ReferenceID   uint       `json:"referenceId" gorm:"index:idx_loyalty_reference,priority:2"`
+	Note          string     `json:"note" gorm:"size:255"`
+	ActorID       string     `json:"actorId" gorm:"size:100"`
+	OccurredAt    time.Time  `json:"occurredAt" gorm:"not null;index"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// Riwayat naik/turun tier, dasar email notifikasi ke pelanggan
+type LoyaltyTierChange struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	CustomerID   uint      `json:"customerId" gorm:"not null;index"`
+	FromTierID   *uint     `json:"fromTierId"`
+	ToTierID     *uint     `json:"toTierId"`
+	RollingSpend int64     `json:"rollingSpend" gorm:"not null"`
+	ChangedAt    time.Time `json:"changedAt" gorm:"not null"`
+}
+
+type LoyaltyEarnRuleRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	CategoryID  *uint  `json:"categoryId"`
+	SpendAmount int64  `json:"spendAmount" validate:"required,gt=0"`
+	Points      int64  `json:"points" validate:"required,gt=0"`
+	Active      *bool  `json:"active"`
+}
+
+type LoyaltyTierRequest struct {
+	Name       string  `json:"name" validate:"required,max=50"`
+	MinSpend   int64   `json:"minSpend" validate:"min=0"`
+	Multiplier float64 `json:"multiplier" validate:"required,gt=0,lte=10"`
+}
+
+// Body POST /v1/customers/:id/loyalty/adjustments
+type LoyaltyAdjustmentRequest struct {
+	Points int64  `json:"points" validate:"required"` // boleh negatif
+	Note   string `json:"note" validate:"required,max=255"`
+}
+
+// Query string GET /v1/customers/:id/loyalty/ledger
+type LoyaltyLedgerQuery struct {
+	Type     string `form:"type" validate:"omitempty,oneof=earn redeem expire reversal refund adjust"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type LoyaltyLedgerResult struct {
+	Transactions []LoyaltyTransaction `json:"transactions"`
+	Pagination   Pagination           `json:"pagination"`
+}
+
+type LoyaltySummary struct {
+	CustomerID   uint         `json:"customerId"`
+	Points       int64        `json:"points"`
+	PointValue   int64        `json:"pointValue"` // rupiah per poin saat ditukar
+	Tier         *LoyaltyTier `json:"tier"`
+	NextTier     *LoyaltyTier `json:"nextTier"`
+	RollingSpend int64        `json:"rollingSpend"`
+	// Poin yang akan hangus dalam 30 hari ke depan
+	ExpiringSoon int64 `json:"expiringSoon"`
+}
+
+// Hasil POST /v1/loyalty/maintenance
+type LoyaltyMaintenanceResult struct {
+	CustomersExpired int   `json:"customersExpired"`
+	PointsExpired    int64 `json:"pointsExpired"`
+	TierChanges      int   `json:"tierChanges"`
+}
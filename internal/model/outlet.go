@@ -7,34 +7,103 @@ import (
 
 type Outlet struct {
     ID        uint           `json:"id" gorm:"primarykey"`
-    Name      string         `json:"name" gorm:"not null;size:255" validate:"required,min=3,max=255"`
-    Address   string         `json:"address" gorm:"not null;type:text" validate:"required,min=10"`
+    // Kode outlet dari master list ops, NULL kalau belum diberi kode
+    Code      *string        `json:"code" gorm:"size:50;uniqueIndex"`
+    Name      string         `json:"name" gorm:"not null;size:255;index:idx_outlets_search,class:FULLTEXT" validate:"required,min=3,max=255"`
+    Address   string         `json:"address" gorm:"not null;type:text;index:idx_outlets_search,class:FULLTEXT" validate:"required,min=10"`
+    Latitude  *float64       `json:"latitude" gorm:"type:decimal(10,7);index:idx_outlets_location" validate:"omitempty,latitude"`
+    Longitude *float64       `json:"longitude" gorm:"type:decimal(10,7);index:idx_outlets_location" validate:"omitempty,longitude"`
     Phone     string         `json:"phone" gorm:"not null;size:20" validate:"required,min=10,max=20"`
-    Manager   string         `json:"manager" gorm:"not null;size:255" validate:"required,min=3,max=255"`
-    Status    string         `json:"status" gorm:"not null;default:'active'" validate:"required,oneof=active inactive"`
-    OpenHours string         `json:"openHours" gorm:"not null;size:50" validate:"required"`
+    Manager   string         `json:"manager" gorm:"not null;size:255;index;index:idx_outlets_search,class:FULLTEXT" validate:"required,min=3,max=255"`
+    Status    string         `json:"status" gorm:"not null;size:20;index;default:'active'" validate:"required,oneof=active inactive"`
+    RegionID  *uint          `json:"regionId" gorm:"index"`
+    OpenHours string         `json:"openHours" gorm:"size:50"`
+    TimeZone  string         `json:"timeZone" gorm:"not null;size:64;default:'Asia/Jakarta'" validate:"omitempty,timezone"`
+    Hours       []OutletHours      `json:"hours" gorm:"foreignKey:OutletID"`
+    SpecialDays []OutletSpecialDay `json:"specialDays" gorm:"foreignKey:OutletID"`
+    // Naik satu setiap update, dipakai sebagai ETag
+    Version   uint           `json:"version" gorm:"not null;default:1"`
     CreatedAt time.Time      `json:"createdAt"`
     UpdatedAt time.Time      `json:"updatedAt"`
     DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 type OutletRequest struct {
+    Code      *string `json:"code" validate:"omitempty,min=1,max=50"`
     Name      string `json:"name" validate:"required,min=3,max=255"`
     Address   string `json:"address" validate:"required,min=10"`
+    // Kosongkan keduanya untuk geocoding otomatis dari alamat
+    Latitude  *float64 `json:"latitude" validate:"omitempty,latitude,required_with=Longitude"`
+    Longitude *float64 `json:"longitude" validate:"omitempty,longitude,required_with=Latitude"`
     Phone     string `json:"phone" validate:"required,min=10,max=20"`
     Manager   string `json:"manager" validate:"required,min=3,max=255"`
     Status    string `json:"status" validate:"required,oneof=active inactive"`
-    OpenHours string `json:"openHours" validate:"required"`
+    RegionID  *uint  `json:"regionId"`
+    // OpenHours tetap ada sebagai label bebas, jadwal terstruktur ada di Hours
+    OpenHours string             `json:"openHours" validate:"max=50"`
+    TimeZone  string             `json:"timeZone" validate:"omitempty,timezone"`
+    Hours     []OutletHoursInput `json:"hours" validate:"omitempty,dive"`
+}
+
+// Body PATCH /v1/outlets/:id, field yang tidak dikirim tidak diubah
+type OutletPatchRequest struct {
+    Code      *string  `json:"code" validate:"omitempty,min=1,max=50"`
+    Name      *string  `json:"name" validate:"omitempty,min=3,max=255"`
+    Address   *string  `json:"address" validate:"omitempty,min=10"`
+    Latitude  *float64 `json:"latitude" validate:"omitempty,latitude,required_with=Longitude"`
+    Longitude *float64 `json:"longitude" validate:"omitempty,longitude,required_with=Latitude"`
+    Phone     *string  `json:"phone" validate:"omitempty,min=10,max=20"`
+    Manager   *string  `json:"manager" validate:"omitempty,min=3,max=255"`
+    Status    *string  `json:"status" validate:"omitempty,oneof=active inactive"`
+    RegionID  *uint    `json:"regionId"`
+    OpenHours *string  `json:"openHours" validate:"omitempty,max=50"`
+    TimeZone  *string  `json:"timeZone" validate:"omitempty,timezone"`
+    Hours     []OutletHoursInput `json:"hours" validate:"omitempty,dive"`
+}
+
+// Query string GET /v1/outlets
+type OutletListQuery struct {
+    Search    string `form:"search"`
+    Status    string `form:"status" validate:"omitempty,oneof=active inactive"`
+    Manager   string `form:"manager"`
+    RegionID  uint   `form:"regionId"`
+    SortBy    string `form:"sortBy" validate:"omitempty,oneof=name createdAt status"`
+    SortOrder string `form:"sortOrder" validate:"omitempty,oneof=asc desc"`
+    OpenAt    string `form:"openAt" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+    Page      int    `form:"page" validate:"omitempty,min=1"`
+    PageSize  int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type Pagination struct {
+    Page       int   `json:"page"`
+    PageSize   int   `json:"pageSize"`
+    Total      int64 `json:"total"`
+    TotalPages int   `json:"totalPages"`
+}
+
+type OutletListResult struct {
+    Outlets    []OutletResponse `json:"outlets"`
+    Pagination Pagination       `json:"pagination"`
 }
 
 type OutletResponse struct {
     ID        uint      `json:"id"`
+    Code      *string   `json:"code"`
     Name      string    `json:"name"`
     Address   string    `json:"address"`
+    Latitude  *float64  `json:"latitude"`
+    Longitude *float64  `json:"longitude"`
     Phone     string    `json:"phone"`
     Manager   string    `json:"manager"`
     Status    string    `json:"status"`
+    RegionID  *uint     `json:"regionId"`
     OpenHours string    `json:"openHours"`
+    TimeZone  string    `json:"timeZone"`
+    Hours       []OutletHours      `json:"hours"`
+    SpecialDays []OutletSpecialDay `json:"specialDays"`
+    // nil kalau outlet belum punya jadwal terstruktur
+    IsOpenNow *bool     `json:"isOpenNow"`
+    Version   uint      `json:"version"`
     CreatedAt time.Time `json:"createdAt"`
     UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -42,12 +111,21 @@ type OutletResponse struct {
 func (o *Outlet) ToResponse() OutletResponse {
     return OutletResponse{
         ID:        o.ID,
+        Code:      o.Code,
         Name:      o.Name,
         Address:   o.Address,
+        Latitude:  o.Latitude,
+        Longitude: o.Longitude,
         Phone:     o.Phone,
         Manager:   o.Manager,
         Status:    o.Status,
+        RegionID:  o.RegionID,
         OpenHours: o.OpenHours,
+        TimeZone:  o.TimeZone,
+        Hours:       o.Hours,
+        SpecialDays: o.SpecialDays,
+        IsOpenNow: o.isOpenNow(),
+        Version:   o.Version,
         CreatedAt: o.CreatedAt,
         UpdatedAt: o.UpdatedAt,
     }
@@ -0,0 +1,33 @@
+package model
+
+type GeoPoint struct {
+	Lat float64 `json:"lat" validate:"latitude"`
+	Lng float64 `json:"lng" validate:"longitude"`
+}
+
+// Area antar (polygon) yang dilayani sebuah outlet
+type OutletDeliveryArea struct {
+	ID       uint       `json:"id" gorm:"primaryKey"`
+	OutletID uint       `json:"outletId" gorm:"not null;index"`
+	Name     string     `json:"name" gorm:"not null;size:100"`
+	Polygon  []GeoPoint `json:"polygon" gorm:"type:json;serializer:json"`
+	Active   bool       `json:"active" gorm:"not null;default:true"`
+}
+
+type DeliveryAreaRequest struct {
+	Name    string     `json:"name" validate:"required,max=100"`
+	Polygon []GeoPoint `json:"polygon" validate:"required,min=3,dive"`
+	Active  *bool      `json:"active"`
+}
+
+// Query string GET /v1/outlets/nearby dan /v1/outlets/serving
+type GeoQuery struct {
+	Lat    *float64 `form:"lat" validate:"required,latitude"`
+	Lng    *float64 `form:"lng" validate:"required,longitude"`
+	Radius float64  `form:"radius" validate:"omitempty,gt=0,max=100"` // km
+}
+
+type NearbyOutlet struct {
+	OutletResponse
+	DistanceKm float64 `json:"distanceKm"`
+}
@@ -0,0 +1,219 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultOutletTimeZone = "Asia/Jakarta"
+	dateLayout            = "2006-01-02"
+)
+
+// Jam buka mingguan. Satu hari boleh punya beberapa baris (split shift);
+// Close <= Open berarti tutupnya lewat tengah malam (overnight).
+type OutletHours struct {
+	ID       uint   `json:"-" gorm:"primaryKey"`
+	OutletID uint   `json:"-" gorm:"not null;index"`
+	Weekday  int    `json:"weekday" gorm:"not null"` // 0 = Minggu ... 6 = Sabtu
+	Open     string `json:"open" gorm:"not null;size:5"`
+	Close    string `json:"close" gorm:"not null;size:5"`
+}
+
+// Pengecualian jadwal untuk tanggal tertentu (libur / hari khusus)
+type OutletSpecialDay struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	OutletID    uint              `json:"-" gorm:"not null;uniqueIndex:idx_outlet_special_day"`
+	Date        string            `json:"date" gorm:"not null;size:10;uniqueIndex:idx_outlet_special_day"`
+	Closed      bool              `json:"closed" gorm:"not null;default:false"`
+	Description string            `json:"description" gorm:"size:255"`
+	Intervals   []OpeningInterval `json:"intervals" gorm:"type:json;serializer:json"`
+}
+
+type OpeningInterval struct {
+	Open  string `json:"open" validate:"required,datetime=15:04"`
+	Close string `json:"close" validate:"required,datetime=15:04"`
+}
+
+type OutletHoursInput struct {
+	Weekday int    `json:"weekday" validate:"min=0,max=6"`
+	Open    string `json:"open" validate:"required,datetime=15:04"`
+	Close   string `json:"close" validate:"required,datetime=15:04"`
+}
+
+type OutletSpecialDayRequest struct {
+	Date        string            `json:"date" validate:"required,datetime=2006-01-02"`
+	Closed      bool              `json:"closed"`
+	Description string            `json:"description" validate:"max=255"`
+	Intervals   []OpeningInterval `json:"intervals" validate:"required_without=Closed,omitempty,dive"`
+}
+
+func ToOutletHours(inputs []OutletHoursInput) []OutletHours {
+	hours := []OutletHours{}
+	for _, input := range inputs {
+		hours = append(hours, OutletHours{
+			Weekday: input.Weekday,
+			Open:    input.Open,
+			Close:   input.Close,
+		})
+	}
+	return hours
+}
+
+// Location returns the outlet time zone, falling back to the default zone.
+func (o *Outlet) Location() *time.Location {
+	name := o.TimeZone
+	if name == "" {
+		name = DefaultOutletTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// HasSchedule reports whether the outlet has structured opening hours.
+func (o *Outlet) HasSchedule() bool {
+	return len(o.Hours) > 0 || len(o.SpecialDays) > 0
+}
+
+// IsOpenAt evaluates the weekly hours and special days (which must be loaded)
+// in the outlet's own time zone. An overnight interval that started yesterday
+// is still open until its closing time today.
+func (o *Outlet) IsOpenAt(t time.Time) bool {
+	local := t.In(o.Location())
+	minutes := local.Hour()*60 + local.Minute()
+
+	for _, interval := range o.intervalsOn(local) {
+		open, close := clockMinutes(interval.Open), clockMinutes(interval.Close)
+		if close > open {
+			if minutes >= open && minutes < close {
+				return true
+			}
+		} else if minutes >= open {
+			return true
+		}
+	}
+
+	for _, interval := range o.intervalsOn(local.AddDate(0, 0, -1)) {
+		open, close := clockMinutes(interval.Open), clockMinutes(interval.Close)
+		if close <= open && minutes < close {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Outlet) isOpenNow() *bool {
+	if !o.HasSchedule() {
+		return nil
+	}
+	open := o.IsOpenAt(time.Now())
+	return &open
+}
+
+// intervalsOn returns the opening intervals of one local calendar day, a special
+// day on that date replaces the weekly schedule.
+func (o *Outlet) intervalsOn(day time.Time) []OpeningInterval {
+	date := day.Format(dateLayout)
+	for _, special := range o.SpecialDays {
+		if special.Date == date {
+			if special.Closed {
+				return nil
+			}
+			return special.Intervals
+		}
+	}
+
+	var intervals []OpeningInterval
+	for _, hours := range o.Hours {
+		if hours.Weekday == int(day.Weekday()) {
+			intervals = append(intervals, OpeningInterval{Open: hours.Open, Close: hours.Close})
+		}
+	}
+	return intervals
+}
+
+// clockMinutes converts "HH:MM" into minutes after midnight.
+func clockMinutes(clock string) int {
+	parts := strings.SplitN(clock, ":", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	hour, _ := strconv.Atoi(parts[0])
+	minute, _ := strconv.Atoi(parts[1])
+	return hour*60 + minute
+}
+
+// Singkatan hari untuk jadwal di spreadsheet, index = time.Weekday
+var weekdayAbbreviations = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// FormatWeeklyHours renders the weekly schedule as text, e.g.
+// "Mon 08:00-17:00; Sat 09:00-12:00, 18:00-23:00". ParseWeeklyHours reads it back.
+func FormatWeeklyHours(hours []OutletHours) string {
+	sorted := append([]OutletHours{}, hours...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Weekday != sorted[j].Weekday {
+			return sorted[i].Weekday < sorted[j].Weekday
+		}
+		return sorted[i].Open < sorted[j].Open
+	})
+
+	var days []string
+	for i := 0; i < len(sorted); {
+		weekday := sorted[i].Weekday
+		var intervals []string
+		for ; i < len(sorted) && sorted[i].Weekday == weekday; i++ {
+			intervals = append(intervals, sorted[i].Open+"-"+sorted[i].Close)
+		}
+		name := strconv.Itoa(weekday)
+		if weekday >= 0 && weekday < len(weekdayAbbreviations) {
+			name = weekdayAbbreviations[weekday]
+		}
+		days = append(days, name+" "+strings.Join(intervals, ", "))
+	}
+	return strings.Join(days, "; ")
+}
+
+// ParseWeeklyHours parses the FormatWeeklyHours text. Clock values are not
+// checked here, they go through the OutletHoursInput validation afterwards.
+func ParseWeeklyHours(text string) ([]OutletHoursInput, error) {
+	hours := []OutletHoursInput{}
+	for _, day := range strings.Split(text, ";") {
+		day = strings.TrimSpace(day)
+		if day == "" {
+			continue
+		}
+
+		fields := strings.SplitN(day, " ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid schedule %q, expected e.g. \"Mon 08:00-17:00\"", day)
+		}
+		weekday := -1
+		for i, name := range weekdayAbbreviations {
+			if strings.EqualFold(fields[0], name) {
+				weekday = i
+			}
+		}
+		if weekday < 0 {
+			return nil, fmt.Errorf("unknown weekday %q", fields[0])
+		}
+
+		for _, interval := range strings.Split(fields[1], ",") {
+			clocks := strings.SplitN(strings.TrimSpace(interval), "-", 2)
+			if len(clocks) != 2 {
+				return nil, fmt.Errorf("invalid interval %q on %s", strings.TrimSpace(interval), fields[0])
+			}
+			hours = append(hours, OutletHoursInput{
+				Weekday: weekday,
+				Open:    strings.TrimSpace(clocks[0]),
+				Close:   strings.TrimSpace(clocks[1]),
+			})
+		}
+	}
+	return hours, nil
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOutletIsOpenAt(t *testing.T) {
+	outlet := Outlet{
+		TimeZone: "Asia/Jakarta",
+		Hours: []OutletHours{
+			{Weekday: 1, Open: "08:00", Close: "17:00"},
+			{Weekday: 5, Open: "20:00", Close: "02:00"},
+			{Weekday: 6, Open: "09:00", Close: "12:00"},
+			{Weekday: 6, Open: "18:00", Close: "23:00"},
+		},
+		SpecialDays: []OutletSpecialDay{
+			{Date: "2026-03-09", Closed: true},
+			{Date: "2026-03-14", Closed: true},
+			{Date: "2026-03-16", Intervals: []OpeningInterval{{Open: "10:00", Close: "12:00"}}},
+		},
+	}
+	loc := outlet.Location()
+	local := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{name: "opening minute", at: local(2, 8, 0), want: true},
+		{name: "before opening", at: local(2, 7, 59), want: false},
+		{name: "closing minute is closed", at: local(2, 17, 0), want: false},
+		{name: "other time zone is converted", at: time.Date(2026, 3, 2, 1, 30, 0, 0, time.UTC), want: true},
+		{name: "overnight before midnight", at: local(6, 23, 30), want: true},
+		{name: "overnight after midnight", at: local(7, 1, 59), want: true},
+		{name: "overnight closing minute", at: local(7, 2, 0), want: false},
+		{name: "between split intervals", at: local(7, 13, 0), want: false},
+		{name: "second split interval", at: local(7, 18, 30), want: true},
+		{name: "day without hours", at: local(3, 12, 0), want: false},
+		{name: "special day closed", at: local(9, 10, 0), want: false},
+		{name: "previous night runs into a closed special day", at: local(14, 1, 0), want: true},
+		{name: "special hours replace the weekly hours", at: local(16, 9, 0), want: false},
+		{name: "inside special hours", at: local(16, 11, 0), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outlet.IsOpenAt(tt.at); got != tt.want {
+				t.Errorf("IsOpenAt(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWeeklyHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []OutletHoursInput
+		wantErr bool
+	}{
+		{name: "empty", text: "", want: []OutletHoursInput{}},
+		{
+			name: "split shift and lower case day",
+			text: "mon 08:00-17:00; Sat 09:00-12:00, 18:00-23:00;",
+			want: []OutletHoursInput{
+				{Weekday: 1, Open: "08:00", Close: "17:00"},
+				{Weekday: 6, Open: "09:00", Close: "12:00"},
+				{Weekday: 6, Open: "18:00", Close: "23:00"},
+			},
+		},
+		{name: "unknown weekday", text: "Monday 08:00-17:00", wantErr: true},
+		{name: "day without intervals", text: "Mon", wantErr: true},
+		{name: "interval without close", text: "Mon 08:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWeeklyHours(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWeeklyHours = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatWeeklyHoursRoundTrip(t *testing.T) {
+	hours := []OutletHours{
+		{Weekday: 6, Open: "18:00", Close: "23:00"},
+		{Weekday: 1, Open: "08:00", Close: "17:00"},
+		{Weekday: 6, Open: "09:00", Close: "12:00"},
+	}
+	text := FormatWeeklyHours(hours)
+	if want := "Mon 08:00-17:00; Sat 09:00-12:00, 18:00-23:00"; text != want {
+		t.Fatalf("FormatWeeklyHours = %q, want %q", text, want)
+	}
+	parsed, err := ParseWeeklyHours(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ToOutletHours(parsed); !reflect.DeepEqual(got, []OutletHours{hours[1], hours[2], hours[0]}) {
+		t.Errorf("round trip = %+v", got)
+	}
+}
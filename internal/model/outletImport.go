@@ -0,0 +1,26 @@
+package model
+
+// Hasil validasi satu baris spreadsheet import outlet
+type OutletImportRow struct {
+	Row      int      `json:"row"` // nomor baris di Excel, header = baris 1
+	Code     string   `json:"code,omitempty"`
+	Name     string   `json:"name"`
+	Action   string   `json:"action,omitempty"` // create / update, kosong kalau baris tidak valid
+	OutletID uint     `json:"outletId,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+type OutletImportResult struct {
+	DryRun  bool              `json:"dryRun"`
+	Applied bool              `json:"applied"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []OutletImportRow `json:"rows"`
+}
+
+const (
+	OutletImportCreate = "create"
+	OutletImportUpdate = "update"
+)
@@ -0,0 +1,40 @@
+package model
+
+// Query string GET /v1/outlets/:id/qr
+type OutletQRQuery struct {
+	Size   int    `form:"size" validate:"omitempty,min=64,max=2048"`
+	Format string `form:"format" validate:"omitempty,oneof=png svg"`
+}
+
+// Data outlet yang boleh dilihat tanpa login (halaman tujuan QR code)
+type PublicOutletResponse struct {
+	ID          uint               `json:"id"`
+	Code        *string            `json:"code"`
+	Name        string             `json:"name"`
+	Address     string             `json:"address"`
+	Phone       string             `json:"phone"`
+	Latitude    *float64           `json:"latitude"`
+	Longitude   *float64           `json:"longitude"`
+	OpenHours   string             `json:"openHours"`
+	TimeZone    string             `json:"timeZone"`
+	Hours       []OutletHours      `json:"hours"`
+	SpecialDays []OutletSpecialDay `json:"specialDays"`
+	IsOpenNow   *bool              `json:"isOpenNow"`
+}
+
+func (o *Outlet) ToPublicResponse() PublicOutletResponse {
+	return PublicOutletResponse{
+		ID:          o.ID,
+		Code:        o.Code,
+		Name:        o.Name,
+		Address:     o.Address,
+		Phone:       o.Phone,
+		Latitude:    o.Latitude,
+		Longitude:   o.Longitude,
+		OpenHours:   o.OpenHours,
+		TimeZone:    o.TimeZone,
+		Hours:       o.Hours,
+		SpecialDays: o.SpecialDays,
+		IsOpenNow:   o.isOpenNow(),
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+// Query string GET /v1/outlets/stats, rentang tanggal dibuatnya outlet (inklusif)
+type OutletStatsQuery struct {
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type OutletStats struct {
+	From            string             `json:"from,omitempty"`
+	To              string             `json:"to,omitempty"`
+	Total           int64              `json:"total"`
+	Active          int64              `json:"active"`
+	Inactive        int64              `json:"inactive"`
+	ByStatus        map[string]int64   `json:"byStatus"`
+	ByRegion        []RegionStats      `json:"byRegion"`
+	ByManager       []ManagerStats     `json:"byManager"`
+	CreatedPerMonth []MonthlyCount     `json:"createdPerMonth"`
+	Incomplete      []IncompleteOutlet `json:"incomplete"`
+	StaffPerOutlet  []OutletStaffCount `json:"staffPerOutlet"`
+}
+
+// Angka per region sudah termasuk outlet di sub-region
+type RegionStats struct {
+	RegionID uint   `json:"regionId"`
+	ParentID *uint  `json:"parentId"`
+	BrandID  uint   `json:"brandId"`
+	Name     string `json:"name"`
+	Total    int64  `json:"total"`
+	Active   int64  `json:"active"`
+	Inactive int64  `json:"inactive"`
+}
+
+type ManagerStats struct {
+	Manager  string `json:"manager"`
+	Total    int64  `json:"total"`
+	Active   int64  `json:"active"`
+	Inactive int64  `json:"inactive"`
+}
+
+type MonthlyCount struct {
+	Month string `json:"month"` // YYYY-MM
+	Count int64  `json:"count"`
+}
+
+// Outlet yang datanya belum lengkap beserta field yang kosong
+type IncompleteOutlet struct {
+	ID      uint     `json:"id"`
+	Name    string   `json:"name"`
+	Missing []string `json:"missing"`
+}
+
+type OutletStaffCount struct {
+	OutletID uint   `json:"outletId"`
+	Name     string `json:"name"`
+	Staff    int64  `json:"staff"`
+}
@@ -0,0 +1,108 @@
+package model
+
+import "time"
+
+// Status payroll run. Draft bisa dihitung ulang atau dihapus; locked final.
+const (
+	PayrollDraft  = "draft"
+	PayrollLocked = "locked"
+)
+
+// Jenis baris slip gaji
+const (
+	PayslipBase      = "base"
+	PayslipAllowance = "allowance"
+	PayslipOvertime  = "overtime"
+	PayslipDeduction = "deduction"
+)
+
+// PayrollRun adalah penggajian satu periode (bulan) untuk semua outlet
+// (OutletID 0) atau satu outlet.
+type PayrollRun struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	Period          string     `json:"period" gorm:"not null;size:7;uniqueIndex:idx_payroll_period_outlet,priority:1"` // YYYY-MM
+	OutletID        uint       `json:"outletId" gorm:"not null;default:0;uniqueIndex:idx_payroll_period_outlet,priority:2"`
+	PeriodStart     time.Time  `json:"periodStart" gorm:"type:date;not null"`
+	PeriodEnd       time.Time  `json:"periodEnd" gorm:"type:date;not null"`
+	Status          string     `json:"status" gorm:"not null;size:20;index"`
+	EmployeeCount   int        `json:"employeeCount" gorm:"not null"`
+	TotalGross      int64      `json:"totalGross" gorm:"not null"`
+	TotalDeductions int64      `json:"totalDeductions" gorm:"not null"`
+	TotalNet        int64      `json:"totalNet" gorm:"not null"`
+	Note            string     `json:"note" gorm:"size:255"`
+	CreatedBy       string     `json:"createdBy" gorm:"size:100"`
+	CalculatedAt    time.Time  `json:"calculatedAt"`
+	LockedBy        string     `json:"lockedBy" gorm:"size:100"`
+	LockedAt        *time.Time `json:"lockedAt"`
+	Payslips        []Payslip  `json:"payslips,omitempty" gorm:"foreignKey:RunID"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// Payslip menyalin data karyawan dan komponen gaji saat dihitung, supaya slip yang
+// sudah dikunci tidak berubah walaupun data karyawan diubah.
+type Payslip struct {
+	ID              uint          `json:"id" gorm:"primaryKey"`
+	RunID           uint          `json:"runId" gorm:"not null;index"`
+	EmployeeID      uint          `json:"employeeId" gorm:"not null;index"`
+	EmployeeCode    string        `json:"employeeCode" gorm:"size:30"`
+	EmployeeName    string        `json:"employeeName" gorm:"size:255"`
+	EmployeeEmail   string        `json:"employeeEmail" gorm:"size:255"`
+	OutletID        uint          `json:"outletId" gorm:"not null"`
+	Position        string        `json:"position" gorm:"size:100"`
+	BankName        string        `json:"bankName" gorm:"size:100"`
+	BankAccount     string        `json:"bankAccount" gorm:"size:50"`
+	BaseSalary      int64         `json:"baseSalary" gorm:"not null"` // prorata jika masuk/keluar di tengah periode
+	Allowances      int64         `json:"allowances" gorm:"not null"`
+	OvertimeMinutes int           `json:"overtimeMinutes" gorm:"not null"`
+	OvertimePay     int64         `json:"overtimePay" gorm:"not null"`
+	GrossPay        int64         `json:"grossPay" gorm:"not null"`
+	Deductions      int64         `json:"deductions" gorm:"not null"`
+	NetPay          int64         `json:"netPay" gorm:"not null"`
+	EmailedAt       *time.Time    `json:"emailedAt"`
+	Lines           []PayslipLine `json:"lines,omitempty" gorm:"foreignKey:PayslipID"`
+}
+
+type PayslipLine struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	PayslipID uint   `json:"payslipId" gorm:"not null;index"`
+	Type      string `json:"type" gorm:"not null;size:20"`
+	Name      string `json:"name" gorm:"not null;size:100"`
+	Amount    int64  `json:"amount" gorm:"not null"`
+}
+
+type PayrollRunRequest struct {
+	Period   string `json:"period" validate:"required,datetime=2006-01"`
+	OutletID uint   `json:"outletId"` // 0 = semua outlet
+	Note     string `json:"note" validate:"max=255"`
+}
+
+// Query string GET /v1/payroll/runs
+type PayrollRunListQuery struct {
+	Year     int    `form:"year" validate:"omitempty,min=2000,max=9999"`
+	Status   string `form:"status" validate:"omitempty,oneof=draft locked"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type PayrollRunListResult struct {
+	Runs       []PayrollRun `json:"runs"`
+	Pagination Pagination   `json:"pagination"`
+}
+
+type PayslipEmailResult struct {
+	Sent    int `json:"sent"`
+	Skipped int `json:"skipped"` // tanpa email atau sudah pernah dikirim
+	Failed  int `json:"failed"`
+}
+
+// PayslipDocument adalah data yang dicetak ke PDF slip gaji.
+type PayslipDocument struct {
+	CompanyName    string
+	CompanyAddress string
+	Period         string
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	OutletName     string
+	Payslip        Payslip
+}
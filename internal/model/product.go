@@ -0,0 +1,146 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Semua harga disimpan dalam rupiah utuh (tanpa sen)
+
+type ProductCategory struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null;size:100;uniqueIndex"`
+	Description string         `json:"description" gorm:"size:255"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type Product struct {
+	ID           uint                 `json:"id" gorm:"primaryKey"`
+	CategoryID   *uint                `json:"categoryId" gorm:"index"`
+	Category     *ProductCategory     `json:"category,omitempty"`
+	Name         string               `json:"name" gorm:"not null;size:255;index"`
+	Description  string               `json:"description" gorm:"type:text"`
+	SKU          string               `json:"sku" gorm:"column:sku;not null;size:64;uniqueIndex"`
+	Barcode      *string              `json:"barcode" gorm:"size:64;uniqueIndex"`
+	BasePrice    int64                `json:"basePrice" gorm:"not null;default:0"`
+	TaxRate      float64              `json:"taxRate" gorm:"type:decimal(5,2);not null;default:0"` // persen, mis. 11.00
+	Unit         string               `json:"unit" gorm:"size:20;default:'pcs'"`
+	Active       bool                 `json:"active" gorm:"not null"`
+	Variants     []ProductVariant     `json:"variants" gorm:"foreignKey:ProductID"`
+	Modifiers    []ProductModifier    `json:"modifiers" gorm:"foreignKey:ProductID"`
+	OutletPrices []ProductOutletPrice `json:"outletPrices" gorm:"foreignKey:ProductID"`
+	CreatedAt    time.Time            `json:"createdAt"`
+	UpdatedAt    time.Time            `json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt       `json:"-" gorm:"index"`
+}
+
+// Varian produk (ukuran, rasa, ...). Price nil berarti ikut BasePrice produk.
+type ProductVariant struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	ProductID uint           `json:"productId" gorm:"not null;index"`
+	Name      string         `json:"name" gorm:"not null;size:100"`
+	SKU       string         `json:"sku" gorm:"column:sku;not null;size:64;uniqueIndex"`
+	Barcode   *string        `json:"barcode" gorm:"size:64;uniqueIndex"`
+	Price     *int64         `json:"price"`
+	Active    bool           `json:"active" gorm:"not null"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Tambahan yang bisa dipilih saat transaksi (extra shot, topping, ...)
+type ProductModifier struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	ProductID uint           `json:"productId" gorm:"not null;index"`
+	Name      string         `json:"name" gorm:"not null;size:100"`
+	Price     int64          `json:"price" gorm:"not null;default:0"`
+	Active    bool           `json:"active" gorm:"not null"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Harga khusus per outlet. VariantID 0 berlaku untuk produk dasar.
+type ProductOutletPrice struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProductID uint      `json:"productId" gorm:"not null;uniqueIndex:idx_product_outlet_price"`
+	OutletID  uint      `json:"outletId" gorm:"not null;uniqueIndex:idx_product_outlet_price;index"`
+	VariantID uint      `json:"variantId" gorm:"not null;default:0;uniqueIndex:idx_product_outlet_price"`
+	Price     int64     `json:"price" gorm:"not null"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ProductCategoryRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=255"`
+}
+
+type ProductRequest struct {
+	CategoryID  *uint                  `json:"categoryId"`
+	Name        string                 `json:"name" validate:"required,min=2,max=255"`
+	Description string                 `json:"description"`
+	SKU         string                 `json:"sku" validate:"required,max=64"`
+	Barcode     *string                `json:"barcode" validate:"omitempty,min=1,max=64"`
+	BasePrice   int64                  `json:"basePrice" validate:"min=0"`
+	TaxRate     float64                `json:"taxRate" validate:"min=0,max=100"`
+	Unit        string                 `json:"unit" validate:"max=20"`
+	Active      *bool                  `json:"active"`
+	Variants    []ProductVariantInput  `json:"variants" validate:"omitempty,dive"`
+	Modifiers   []ProductModifierInput `json:"modifiers" validate:"omitempty,dive"`
+}
+
+// ID diisi untuk mengubah varian yang sudah ada; varian lama yang tidak dikirim dihapus
+type ProductVariantInput struct {
+	ID      uint    `json:"id"`
+	Name    string  `json:"name" validate:"required,max=100"`
+	SKU     string  `json:"sku" validate:"required,max=64"`
+	Barcode *string `json:"barcode" validate:"omitempty,min=1,max=64"`
+	Price   *int64  `json:"price" validate:"omitempty,min=0"`
+	Active  *bool   `json:"active"`
+}
+
+type ProductModifierInput struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name" validate:"required,max=100"`
+	Price  int64  `json:"price" validate:"min=0"`
+	Active *bool  `json:"active"`
+}
+
+type ProductOutletPriceRequest struct {
+	VariantID uint  `json:"variantId"`
+	Price     int64 `json:"price" validate:"min=0"`
+}
+
+// Query string GET /v1/products
+type ProductListQuery struct {
+	Search     string `form:"search"`
+	CategoryID uint   `form:"categoryId"`
+	Active     *bool  `form:"active"`
+	Page       int    `form:"page" validate:"omitempty,min=1"`
+	PageSize   int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type ProductListResult struct {
+	Products   []Product  `json:"products"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// Harga efektif satu produk/varian di satu outlet
+type ResolvedPrice struct {
+	ProductID uint    `json:"productId"`
+	VariantID uint    `json:"variantId"`
+	OutletID  uint    `json:"outletId"`
+	Price     int64   `json:"price"`
+	TaxRate   float64 `json:"taxRate"`
+	Source    string  `json:"source"` // base / variant / outlet
+}
+
+const (
+	PriceSourceBase    = "base"
+	PriceSourceVariant = "variant"
+	PriceSourceOutlet  = "outlet"
+)
@@ -0,0 +1,219 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Jenis promo
+const (
+	PromoPercentage = "percentage"  // Value persen dari item yang memenuhi syarat
+	PromoFixed      = "fixed"       // potongan Value rupiah dari item yang memenuhi syarat
+	PromoBuyXGetY   = "buy_x_get_y" // beli BuyQuantity, GetQuantity termurah diskon Value persen
+	PromoBundle     = "bundle"      // paket BundleItems dengan harga BundlePrice
+)
+
+// Alasan promo tidak berlaku, dikembalikan oleh preview
+const (
+	PromoSkipInactive         = "inactive"
+	PromoSkipNotStarted       = "not_started"
+	PromoSkipEnded            = "ended"
+	PromoSkipOutlet           = "outlet_not_included"
+	PromoSkipDay              = "day_not_included"
+	PromoSkipTime             = "outside_time_window"
+	PromoSkipCodeRequired     = "voucher_code_required"
+	PromoSkipUsageLimit       = "usage_limit_reached"
+	PromoSkipCustomerRequired = "customer_required"
+	PromoSkipCustomerLimit    = "customer_limit_reached"
+	PromoSkipMinSpend         = "minimum_spend_not_met"
+	PromoSkipNoItems          = "no_eligible_items"
+	PromoSkipNotStackable     = "not_stackable"
+)
+
+// Promotion berlaku untuk item dengan ProductIDs/CategoryIDs (kosong = semua item)
+// di OutletIDs (kosong = semua outlet), dalam periode StartsAt-EndsAt, pada
+// DaysOfWeek dan jam StartTime-EndTime waktu lokal outlet. Promo dengan Code hanya
+// berlaku jika kode voucher diinput kasir. Promo dievaluasi berurutan dari
+// Priority tertinggi; promo yang tidak Stackable tidak digabung dengan promo lain.
+type Promotion struct {
+	ID               uint                  `json:"id" gorm:"primaryKey"`
+	Name             string                `json:"name" gorm:"not null;size:255"`
+	Code             *string               `json:"code" gorm:"size:50;uniqueIndex"`
+	Type             string                `json:"type" gorm:"not null;size:20"`
+	Value            float64               `json:"value" gorm:"type:decimal(15,2);not null;default:0"`
+	MaxDiscount      int64                 `json:"maxDiscount" gorm:"not null;default:0"` // 0 = tanpa batas
+	MinSpend         int64                 `json:"minSpend" gorm:"not null;default:0"`
+	BuyQuantity      int                   `json:"buyQuantity" gorm:"not null;default:0"`
+	GetQuantity      int                   `json:"getQuantity" gorm:"not null;default:0"`
+	BundleItems      []PromotionBundleItem `json:"bundleItems" gorm:"type:json;serializer:json"`
+	BundlePrice      int64                 `json:"bundlePrice" gorm:"not null;default:0"`
+	ProductIDs       []uint                `json:"productIds" gorm:"type:json;serializer:json"`
+	CategoryIDs      []uint                `json:"categoryIds" gorm:"type:json;serializer:json"`
+	OutletIDs        []uint                `json:"outletIds" gorm:"type:json;serializer:json"`
+	StartsAt         *time.Time            `json:"startsAt"`
+	EndsAt           *time.Time            `json:"endsAt"`
+	DaysOfWeek       []int                 `json:"daysOfWeek" gorm:"type:json;serializer:json"` // 0 = Minggu
+	StartTime        string                `json:"startTime" gorm:"size:5"`                     // "HH:MM", kosong = sepanjang hari
+	EndTime          string                `json:"endTime" gorm:"size:5"`
+	UsageLimit       *int                  `json:"usageLimit"`
+	PerCustomerLimit *int                  `json:"perCustomerLimit"`
+	UsageCount       int                   `json:"usageCount" gorm:"not null;default:0"`
+	Priority         int                   `json:"priority" gorm:"not null;default:0"`
+	Stackable        bool                  `json:"stackable" gorm:"not null"`
+	Active           bool                  `json:"active" gorm:"not null"`
+	CreatedAt        time.Time             `json:"createdAt"`
+	UpdatedAt        time.Time             `json:"updatedAt"`
+	DeletedAt        gorm.DeletedAt        `json:"-" gorm:"index"`
+}
+
+type PromotionBundleItem struct {
+	ProductID uint `json:"productId" validate:"required"`
+	Quantity  int  `json:"quantity" validate:"required,min=1"`
+}
+
+// SalePromotion mencatat promo yang dipakai sebuah transaksi, sekaligus menjadi
+// dasar hitungan pemakaian per pelanggan.
+type SalePromotion struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	SaleID      uint   `json:"saleId" gorm:"not null;index"`
+	PromotionID uint   `json:"promotionId" gorm:"not null;index"`
+	Name        string `json:"name" gorm:"not null;size:255"`
+	Code        string `json:"code" gorm:"size:50"`
+	Discount    int64  `json:"discount" gorm:"not null"`
+}
+
+type PromotionRequest struct {
+	Name             string                `json:"name" validate:"required,max=255"`
+	Code             *string               `json:"code" validate:"omitempty,min=3,max=50,alphanum"`
+	Type             string                `json:"type" validate:"required,oneof=percentage fixed buy_x_get_y bundle"`
+	Value            float64               `json:"value" validate:"min=0"`
+	MaxDiscount      int64                 `json:"maxDiscount" validate:"min=0"`
+	MinSpend         int64                 `json:"minSpend" validate:"min=0"`
+	BuyQuantity      int                   `json:"buyQuantity" validate:"required_if=Type buy_x_get_y,min=0"`
+	GetQuantity      int                   `json:"getQuantity" validate:"required_if=Type buy_x_get_y,min=0"`
+	BundleItems      []PromotionBundleItem `json:"bundleItems" validate:"required_if=Type bundle,omitempty,max=20,dive"`
+	BundlePrice      int64                 `json:"bundlePrice" validate:"required_if=Type bundle,min=0"`
+	ProductIDs       []uint                `json:"productIds"`
+	CategoryIDs      []uint                `json:"categoryIds"`
+	OutletIDs        []uint                `json:"outletIds"`
+	StartsAt         *time.Time            `json:"startsAt"`
+	EndsAt           *time.Time            `json:"endsAt"`
+	DaysOfWeek       []int                 `json:"daysOfWeek" validate:"omitempty,max=7,dive,min=0,max=6"`
+	StartTime        string                `json:"startTime" validate:"required_with=EndTime,omitempty,datetime=15:04"`
+	EndTime          string                `json:"endTime" validate:"required_with=StartTime,omitempty,datetime=15:04"`
+	UsageLimit       *int                  `json:"usageLimit" validate:"omitempty,min=1"`
+	PerCustomerLimit *int                  `json:"perCustomerLimit" validate:"omitempty,min=1"`
+	Priority         int                   `json:"priority"`
+	Stackable        bool                  `json:"stackable"`
+	Active           *bool                 `json:"active"`
+}
+
+// Query string GET /v1/promotions
+type PromotionListQuery struct {
+	Search   string `form:"search"` // nama atau kode voucher
+	Type     string `form:"type" validate:"omitempty,oneof=percentage fixed buy_x_get_y bundle"`
+	Active   *bool  `form:"active"`
+	OutletID uint   `form:"outletId"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type PromotionListResult struct {
+	Promotions []Promotion `json:"promotions"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+// Body POST /v1/promotions/preview, sama dengan keranjang di SaleRequest
+type PromotionPreviewRequest struct {
+	OutletID   uint            `json:"outletId" validate:"required"`
+	CustomerID *uint           `json:"customerId"`
+	PromoCodes []string        `json:"promoCodes" validate:"omitempty,max=5,dive,max=50"`
+	At         *time.Time      `json:"at"` // default sekarang
+	Lines      []SaleLineInput `json:"lines" validate:"required,min=1,max=200,dive"`
+}
+
+// PromoCart adalah input evaluasi promo. Amount sudah dipotong diskon manual dan
+// belum termasuk pajak. At dalam waktu lokal outlet.
+type PromoCart struct {
+	OutletID      uint            `json:"outletId"`
+	CustomerID    *uint           `json:"customerId"`
+	Codes         []string        `json:"codes"`
+	At            time.Time       `json:"at"`
+	Items         []PromoCartItem `json:"items"`
+	CustomerUsage map[uint]int    `json:"-"` // pemakaian promo oleh pelanggan ini
+}
+
+type PromoCartItem struct {
+	ProductID  uint    `json:"productId"`
+	CategoryID *uint   `json:"categoryId"`
+	Quantity   float64 `json:"quantity"`
+	Amount     int64   `json:"amount"`
+}
+
+type PromoEvaluation struct {
+	Subtotal      int64          `json:"subtotal"`
+	TotalDiscount int64          `json:"totalDiscount"`
+	Applied       []AppliedPromo `json:"applied"`
+	Skipped       []SkippedPromo `json:"skipped"`
+	UnknownCodes  []string       `json:"unknownCodes"`
+}
+
+type AppliedPromo struct {
+	PromotionID uint    `json:"promotionId"`
+	Name        string  `json:"name"`
+	Code        string  `json:"code"`
+	Type        string  `json:"type"`
+	Discount    int64   `json:"discount"`
+	Lines       []int64 `json:"lines"` // potongan per item keranjang, urutan sama dengan Items
+}
+
+type SkippedPromo struct {
+	PromotionID uint   `json:"promotionId"`
+	Name        string `json:"name"`
+	Code        string `json:"code"`
+	Reason      string `json:"reason"`
+}
+
+// PromotionPreview adalah hasil preview: keranjang yang sudah dihargai dan promo
+// yang berlaku beserta alasan promo lain tidak berlaku.
+type PromotionPreview struct {
+	Lines      []SaleLine      `json:"lines"`
+	Evaluation PromoEvaluation `json:"evaluation"`
+}
+
+// InTimeWindow reports whether the local time is on one of the promo's days and
+// within its daily hours. EndTime <= StartTime means the window runs past midnight.
+func (p *Promotion) InTimeWindow(local time.Time) (bool, string) {
+	if len(p.DaysOfWeek) > 0 {
+		day := int(local.Weekday())
+		if p.StartTime != "" && clockMinutes(p.EndTime) <= clockMinutes(p.StartTime) &&
+			local.Hour()*60+local.Minute() < clockMinutes(p.EndTime) {
+			// Bagian setelah tengah malam milik hari sebelumnya
+			day = int(local.AddDate(0, 0, -1).Weekday())
+		}
+		found := false
+		for _, d := range p.DaysOfWeek {
+			if d == day {
+				found = true
+			}
+		}
+		if !found {
+			return false, PromoSkipDay
+		}
+	}
+
+	if p.StartTime == "" {
+		return true, ""
+	}
+	minutes := local.Hour()*60 + local.Minute()
+	start, end := clockMinutes(p.StartTime), clockMinutes(p.EndTime)
+	if end > start {
+		if minutes >= start && minutes < end {
+			return true, ""
+		}
+	} else if minutes >= start || minutes < end {
+		return true, ""
+	}
+	return false, PromoSkipTime
+}
@@ -0,0 +1,151 @@
+package model
+
+import "time"
+
+// Status piutang
+const (
+	ReceivableOpen          = "open"
+	ReceivablePartiallyPaid = "partially_paid"
+	ReceivablePaid          = "paid"
+	ReceivableCancelled     = "cancelled" // transaksi di-void sebelum ada pembayaran
+)
+
+// Metode pembayaran piutang
+const (
+	ReceivablePaymentCash     = "cash"
+	ReceivablePaymentTransfer = "transfer"
+	ReceivablePaymentCard     = "card"
+	ReceivablePaymentQRIS     = "qris"
+	ReceivablePaymentGiro     = "giro"
+
+	// Potongan piutang dari void/retur; dicatat sistem, tidak bisa diinput manual
+	ReceivablePaymentReturn = "return"
+)
+
+// Receivable (piutang) dibuat dari tender kredit sebuah transaksi. Balance =
+// Amount - Paid - Credited; Credited adalah potongan dari retur/void transaksi.
+type Receivable struct {
+	ID             uint                `json:"id" gorm:"primaryKey"`
+	Number         string              `json:"number" gorm:"not null;size:30;uniqueIndex"`
+	CustomerID     uint                `json:"customerId" gorm:"not null;index"`
+	SaleID         uint                `json:"saleId" gorm:"not null;uniqueIndex"`
+	OutletID       uint                `json:"outletId" gorm:"not null;index"`
+	ReceiptNumber  string              `json:"receiptNumber" gorm:"size:30"`
+	Amount         int64               `json:"amount" gorm:"not null"`
+	Paid           int64               `json:"paid" gorm:"not null;default:0"`
+	Credited       int64               `json:"credited" gorm:"not null;default:0"`
+	Balance        int64               `json:"balance" gorm:"not null;index"`
+	Status         string              `json:"status" gorm:"not null;size:20;index"`
+	IssuedAt       time.Time           `json:"issuedAt" gorm:"not null"`
+	DueDate        time.Time           `json:"dueDate" gorm:"type:date;not null;index"`
+	LastReminderAt *time.Time          `json:"lastReminderAt"`
+	Payments       []ReceivablePayment `json:"payments,omitempty" gorm:"foreignKey:ReceivableID"`
+	CreatedAt      time.Time           `json:"createdAt"`
+	UpdatedAt      time.Time           `json:"updatedAt"`
+}
+
+type ReceivablePayment struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ReceivableID uint      `json:"receivableId" gorm:"not null;index"`
+	CustomerID   uint      `json:"customerId" gorm:"not null;index"`
+	Amount       int64     `json:"amount" gorm:"not null"`
+	Method       string    `json:"method" gorm:"not null;size:20"`
+	Reference    string    `json:"reference" gorm:"size:100"`
+	Note         string    `json:"note" gorm:"size:255"`
+	PaidAt       time.Time `json:"paidAt" gorm:"not null;index"`
+	ReceivedBy   string    `json:"receivedBy" gorm:"size:100"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type ReceivablePaymentRequest struct {
+	Amount    int64      `json:"amount" validate:"required,min=1"`
+	Method    string     `json:"method" validate:"required,oneof=cash transfer card qris giro"`
+	Reference string     `json:"reference" validate:"max=100"`
+	Note      string     `json:"note" validate:"max=255"`
+	PaidAt    *time.Time `json:"paidAt"` // default sekarang
+}
+
+// Query string GET /v1/receivables
+type ReceivableListQuery struct {
+	CustomerID uint   `form:"customerId"`
+	OutletID   uint   `form:"outletId"`
+	Status     string `form:"status" validate:"omitempty,oneof=open partially_paid paid cancelled"`
+	Overdue    bool   `form:"overdue"`
+	Page       int    `form:"page" validate:"omitempty,min=1"`
+	PageSize   int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type ReceivableListResult struct {
+	Receivables []Receivable `json:"receivables"`
+	Pagination  Pagination   `json:"pagination"`
+}
+
+// Query string GET /v1/receivables/aging
+type ReceivableAgingQuery struct {
+	AsOf     string `form:"asOf" validate:"omitempty,datetime=2006-01-02"` // default hari ini
+	OutletID uint   `form:"outletId"`
+}
+
+// ReceivableAgingBuckets membagi saldo menurut umur lewat jatuh tempo. Piutang
+// yang belum jatuh tempo masuk ke Current.
+type ReceivableAgingBuckets struct {
+	Current    int64 `json:"current"`
+	Days0To30  int64 `json:"days0To30"`
+	Days31To60 int64 `json:"days31To60"`
+	Days61To90 int64 `json:"days61To90"`
+	Over90     int64 `json:"over90"`
+	Total      int64 `json:"total"`
+}
+
+type ReceivableAgingCustomer struct {
+	CustomerID   uint   `json:"customerId"`
+	CustomerName string `json:"customerName"`
+	ReceivableAgingBuckets
+}
+
+type ReceivableAgingReport struct {
+	AsOf      string                    `json:"asOf"`
+	Customers []ReceivableAgingCustomer `json:"customers"`
+	Totals    ReceivableAgingBuckets    `json:"totals"`
+}
+
+// Query string GET /v1/customers/:id/statement
+type CustomerStatementQuery struct {
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"` // default awal bulan ini
+	To   string `form:"to" validate:"omitempty,datetime=2006-01-02"`   // default hari ini
+}
+
+// CustomerStatementEntry adalah satu baris mutasi piutang: Debit untuk transaksi
+// kredit, Credit untuk pembayaran dan potongan retur.
+type CustomerStatementEntry struct {
+	Date        time.Time `json:"date"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	Debit       int64     `json:"debit"`
+	Credit      int64     `json:"credit"`
+	Balance     int64     `json:"balance"`
+}
+
+// CustomerStatement adalah data rekening koran pelanggan yang dicetak ke PDF.
+type CustomerStatement struct {
+	CompanyName    string                   `json:"companyName"`
+	CompanyAddress string                   `json:"companyAddress"`
+	Customer       Customer                 `json:"customer"`
+	From           time.Time                `json:"from"`
+	To             time.Time                `json:"to"`
+	OpeningBalance int64                    `json:"openingBalance"`
+	Entries        []CustomerStatementEntry `json:"entries"`
+	TotalDebit     int64                    `json:"totalDebit"`
+	TotalCredit    int64                    `json:"totalCredit"`
+	ClosingBalance int64                    `json:"closingBalance"`
+	Open           []Receivable             `json:"open"` // piutang yang belum lunas per tanggal To
+	Aging          ReceivableAgingBuckets   `json:"aging"`
+	GeneratedAt    time.Time                `json:"generatedAt"`
+}
+
+type ReceivableReminderResult struct {
+	Customers   int `json:"customers"`   // pelanggan yang dikirimi email
+	Receivables int `json:"receivables"` // piutang jatuh tempo yang diingatkan
+	Skipped     int `json:"skipped"`     // tanpa email atau baru saja diingatkan
+	Failed      int `json:"failed"`      // email gagal terkirim, dicoba lagi di run berikutnya
+}
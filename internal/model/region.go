@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Brand adalah puncak hirarki: brand -> region (bisa bertingkat) -> outlet
+type Brand struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"not null;size:100;uniqueIndex"`
+	Code      string         `json:"code" gorm:"not null;size:20;uniqueIndex"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type Region struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	BrandID   uint           `json:"brandId" gorm:"not null;index"`
+	ParentID  *uint          `json:"parentId" gorm:"index"`
+	Name      string         `json:"name" gorm:"not null;size:100"`
+	ManagerID string         `json:"managerId" gorm:"size:100;index"` // username area manager
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type BrandRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+	Code string `json:"code" validate:"required,max=20"`
+}
+
+type RegionRequest struct {
+	BrandID   uint   `json:"brandId" validate:"required"`
+	ParentID  *uint  `json:"parentId"`
+	Name      string `json:"name" validate:"required,min=2,max=100"`
+	ManagerID string `json:"managerId" validate:"max=100"`
+}
+
+type RegionNode struct {
+	Region
+	Children []*RegionNode `json:"children"`
+}
+
+// Region yang boleh diakses user saat memanggil /v1/outlets.
+// Restricted false berarti tidak dibatasi (admin / user biasa).
+type OutletScope struct {
+	Restricted bool
+	RegionIDs  []uint
+}
+
+// Allows reports whether an outlet in the given region is visible in this scope.
+func (s OutletScope) Allows(regionID *uint) bool {
+	if !s.Restricted {
+		return true
+	}
+	if regionID == nil {
+		return false
+	}
+	for _, id := range s.RegionIDs {
+		if id == *regionID {
+			return true
+		}
+	}
+	return false
+}
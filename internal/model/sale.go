@@ -0,0 +1,123 @@
+package model
+
+import "time"
+
+const (
+	SaleStatusCompleted = "completed"
+)
+
+// Metode pembayaran (tender) yang diterima kasir
+const (
+	TenderCash     = "cash"
+	TenderCard     = "card"
+	TenderQRIS     = "qris"
+	TenderTransfer = "transfer"
+	TenderEWallet  = "ewallet"
+	TenderPoints   = "points" // penukaran poin loyalti, perlu customerId
+	TenderCredit   = "credit" // penjualan kredit, menjadi piutang pelanggan
+)
+
+// Reference type di ledger stok untuk movement dari transaksi penjualan
+const StockReferenceSale = "sale"
+
+// Sale adalah satu transaksi penjualan (struk). Semua nominal dalam rupiah utuh.
+// ClientTransactionID dibuat oleh POS sehingga kiriman ulang tidak dobel posting.
+type Sale struct {
+	ID                  uint            `json:"id" gorm:"primaryKey"`
+	ClientTransactionID string          `json:"clientTransactionId" gorm:"not null;size:64;uniqueIndex"`
+	OutletID            uint            `json:"outletId" gorm:"not null;index:idx_sale_outlet_sold,priority:1"`
+	ReceiptNumber       string          `json:"receiptNumber" gorm:"not null;size:40;uniqueIndex"`
+	Status              string          `json:"status" gorm:"not null;size:20;index"`
+	CashierID           string          `json:"cashierId" gorm:"size:100;index"`
+	CustomerID          *uint           `json:"customerId" gorm:"index"`
+	CashShiftID         *uint           `json:"cashShiftId" gorm:"index"`      // shift laci kasir saat transaksi
+	Subtotal            int64           `json:"subtotal" gorm:"not null"`      // sebelum diskon dan pajak
+	DiscountTotal       int64           `json:"discountTotal" gorm:"not null"` // jumlah diskon semua baris
+	TaxTotal            int64           `json:"taxTotal" gorm:"not null"`
+	Total               int64           `json:"total" gorm:"not null"`
+	Paid                int64           `json:"paid" gorm:"not null"`
+	Change              int64           `json:"change" gorm:"not null"`
+	PointsEarned        int64           `json:"pointsEarned" gorm:"not null;default:0"`
+	Note                string          `json:"note" gorm:"size:255"`
+	SoldAt              time.Time       `json:"soldAt" gorm:"not null;index:idx_sale_outlet_sold,priority:2"`
+	Lines               []SaleLine      `json:"lines" gorm:"foreignKey:SaleID"`
+	Tenders             []SaleTender    `json:"tenders" gorm:"foreignKey:SaleID"`
+	Promotions          []SalePromotion `json:"promotions" gorm:"foreignKey:SaleID"`
+	CreatedAt           time.Time       `json:"createdAt"`
+	UpdatedAt           time.Time       `json:"updatedAt"`
+}
+
+// Nama, SKU dan harga disalin saat transaksi supaya struk lama tidak berubah
+// ketika katalog diubah. Harga katalog belum termasuk pajak.
+type SaleLine struct {
+	ID               uint               `json:"id" gorm:"primaryKey"`
+	SaleID           uint               `json:"saleId" gorm:"not null;index"`
+	ProductID        uint               `json:"productId" gorm:"not null;index"`
+	VariantID        uint               `json:"variantId" gorm:"not null;default:0"`
+	Name             string             `json:"name" gorm:"not null;size:255"`
+	SKU              string             `json:"sku" gorm:"column:sku;size:64"`
+	Quantity         float64            `json:"quantity" gorm:"type:decimal(15,3);not null"`
+	UnitPrice        int64              `json:"unitPrice" gorm:"not null"` // harga + modifier per unit
+	Modifiers        []SaleLineModifier `json:"modifiers" gorm:"type:json;serializer:json"`
+	Discount         int64              `json:"discount" gorm:"not null;default:0"`
+	TaxRate          float64            `json:"taxRate" gorm:"type:decimal(5,2);not null;default:0"`
+	TaxAmount        int64              `json:"taxAmount" gorm:"not null;default:0"`
+	LineTotal        int64              `json:"lineTotal" gorm:"not null"` // (unitPrice x qty) - discount + tax
+	ReturnedQuantity float64            `json:"returnedQuantity" gorm:"type:decimal(15,3);not null;default:0"`
+}
+
+type SaleLineModifier struct {
+	ModifierID uint   `json:"modifierId"`
+	Name       string `json:"name"`
+	Price      int64  `json:"price"`
+}
+
+type SaleTender struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	SaleID    uint   `json:"saleId" gorm:"not null;index"`
+	Method    string `json:"method" gorm:"not null;size:20;index"`
+	Amount    int64  `json:"amount" gorm:"not null"`
+	Reference string `json:"reference" gorm:"size:100"` // no. approval EDC, ref QRIS, ...
+}
+
+type SaleLineInput struct {
+	ProductID   uint    `json:"productId" validate:"required"`
+	VariantID   uint    `json:"variantId"`
+	Quantity    float64 `json:"quantity" validate:"required,gt=0"`
+	ModifierIDs []uint  `json:"modifierIds"`
+	Discount    int64   `json:"discount" validate:"min=0"` // nominal rupiah untuk baris ini
+}
+
+type SaleTenderInput struct {
+	Method    string `json:"method" validate:"required,oneof=cash card qris transfer ewallet points credit"`
+	Amount    int64  `json:"amount" validate:"required,gt=0"`
+	Reference string `json:"reference" validate:"max=100"`
+}
+
+type SaleRequest struct {
+	ClientTransactionID string            `json:"clientTransactionId" validate:"required,max=64"`
+	OutletID            uint              `json:"outletId" validate:"required"`
+	CustomerID          *uint             `json:"customerId"`
+	SoldAt              *time.Time        `json:"soldAt"` // diisi POS untuk transaksi offline
+	Note                string            `json:"note" validate:"max=255"`
+	PromoCodes          []string          `json:"promoCodes" validate:"omitempty,max=5,dive,max=50"` // kode voucher
+	Lines               []SaleLineInput   `json:"lines" validate:"required,min=1,max=200,dive"`
+	Tenders             []SaleTenderInput `json:"tenders" validate:"required,min=1,max=10,dive"`
+}
+
+// Query string GET /v1/sales
+type SaleListQuery struct {
+	OutletID   uint   `form:"outletId"`
+	Status     string `form:"status"`
+	CashierID  string `form:"cashierId"`
+	CustomerID uint   `form:"customerId"`
+	From       string `form:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `form:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Page       int    `form:"page" validate:"omitempty,min=1"`
+	PageSize   int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type SaleListResult struct {
+	Sales      []Sale     `json:"sales"`
+	Pagination Pagination `json:"pagination"`
+}
@@ -0,0 +1,144 @@
+package model
+
+import "time"
+
+// Status transaksi setelah dibatalkan (void) atau diretur
+const (
+	SaleStatusVoided            = "voided"
+	SaleStatusPartiallyReturned = "partially_returned"
+	SaleStatusReturned          = "returned"
+)
+
+// Void membatalkan seluruh transaksi sebelum hari ditutup; retur mengembalikan
+// sebagian/seluruh barang setelahnya.
+const (
+	SaleReversalVoid   = "void"
+	SaleReversalReturn = "return"
+
+	SaleReversalPending  = "pending"
+	SaleReversalApproved = "approved"
+	SaleReversalRejected = "rejected"
+)
+
+// Reference type di ledger stok untuk barang yang kembali karena void/retur
+const (
+	StockReferenceSaleVoid   = "sale_void"
+	StockReferenceSaleReturn = "sale_return"
+)
+
+// Kode alasan pembatalan/retur
+const (
+	ReversalReasonWrongItem      = "wrong_item"
+	ReversalReasonCustomerCancel = "customer_cancel"
+	ReversalReasonPaymentFailed  = "payment_failed"
+	ReversalReasonDuplicate      = "duplicate"
+	ReversalReasonDefective      = "defective"
+	ReversalReasonExpired        = "expired"
+	ReversalReasonChangedMind    = "changed_mind"
+	ReversalReasonOther          = "other"
+)
+
+// SaleReversal adalah permintaan void/retur atas satu transaksi yang menunggu
+// persetujuan supervisor. Stok dan refund baru diposting saat disetujui.
+type SaleReversal struct {
+	ID          uint               `json:"id" gorm:"primaryKey"`
+	Number      string             `json:"number" gorm:"not null;size:30;uniqueIndex"`
+	Type        string             `json:"type" gorm:"not null;size:10;index"`
+	SaleID      uint               `json:"saleId" gorm:"not null;index"`
+	OutletID    uint               `json:"outletId" gorm:"not null;index"`
+	CashierID   string             `json:"cashierId" gorm:"size:100;index"` // kasir transaksi asal
+	Status      string             `json:"status" gorm:"not null;size:20;index"`
+	ReasonCode  string             `json:"reasonCode" gorm:"not null;size:30"`
+	ReasonNote  string             `json:"reasonNote" gorm:"size:255"`
+	RequestedBy string             `json:"requestedBy" gorm:"size:100"`
+	ReviewedBy  string             `json:"reviewedBy" gorm:"size:100"`
+	ReviewedAt  *time.Time         `json:"reviewedAt"`
+	ReviewNote  string             `json:"reviewNote" gorm:"size:255"`
+	RefundTotal int64              `json:"refundTotal" gorm:"not null"`
+	CashShiftID *uint              `json:"cashShiftId" gorm:"index"` // shift yang membayar refund tunai
+	Lines       []SaleReversalLine `json:"lines" gorm:"foreignKey:ReversalID"`
+	Refunds     []SaleRefund       `json:"refunds" gorm:"foreignKey:ReversalID"`
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+type SaleReversalLine struct {
+	ID         uint    `json:"id" gorm:"primaryKey"`
+	ReversalID uint    `json:"reversalId" gorm:"not null;index"`
+	SaleLineID uint    `json:"saleLineId" gorm:"not null;index"`
+	ProductID  uint    `json:"productId" gorm:"not null"`
+	VariantID  uint    `json:"variantId" gorm:"not null;default:0"`
+	Quantity   float64 `json:"quantity" gorm:"type:decimal(15,3);not null"`
+	Amount     int64   `json:"amount" gorm:"not null"` // porsi lineTotal yang dikembalikan
+}
+
+// Refund per metode pembayaran transaksi asal
+type SaleRefund struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	ReversalID uint   `json:"reversalId" gorm:"not null;index"`
+	SaleID     uint   `json:"saleId" gorm:"not null;index"`
+	Method     string `json:"method" gorm:"not null;size:20"`
+	Amount     int64  `json:"amount" gorm:"not null"`
+}
+
+type SaleVoidRequest struct {
+	ReasonCode string `json:"reasonCode" validate:"required,oneof=wrong_item customer_cancel payment_failed duplicate other"`
+	ReasonNote string `json:"reasonNote" validate:"required_if=ReasonCode other,max=255"`
+}
+
+type SaleReturnLineInput struct {
+	SaleLineID uint    `json:"saleLineId" validate:"required"`
+	Quantity   float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+type SaleReturnRequest struct {
+	ReasonCode string                `json:"reasonCode" validate:"required,oneof=wrong_item defective expired changed_mind other"`
+	ReasonNote string                `json:"reasonNote" validate:"required_if=ReasonCode other,max=255"`
+	Lines      []SaleReturnLineInput `json:"lines" validate:"required,min=1,max=200,dive"`
+}
+
+type SaleReversalReviewRequest struct {
+	Note string `json:"note" validate:"max=255"`
+}
+
+// Query string GET /v1/sales/reversals
+type SaleReversalListQuery struct {
+	OutletID uint   `form:"outletId"`
+	Type     string `form:"type" validate:"omitempty,oneof=void return"`
+	Status   string `form:"status" validate:"omitempty,oneof=pending approved rejected"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type SaleReversalListResult struct {
+	Reversals  []SaleReversal `json:"reversals"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// Query string GET /v1/sales/reversal-report (tanggal YYYY-MM-DD, inklusif)
+type SaleReversalReportQuery struct {
+	OutletID uint   `form:"outletId"`
+	From     string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// Rate = jumlah transaksi yang di-void/diretur dibagi jumlah transaksi pada periode
+type SaleReversalRate struct {
+	OutletID     uint    `json:"outletId"`
+	CashierID    string  `json:"cashierId,omitempty"`
+	SaleCount    int64   `json:"saleCount"`
+	SaleTotal    int64   `json:"saleTotal"`
+	VoidCount    int64   `json:"voidCount"`
+	VoidAmount   int64   `json:"voidAmount"`
+	VoidRate     float64 `json:"voidRate"`
+	ReturnCount  int64   `json:"returnCount"`
+	ReturnAmount int64   `json:"returnAmount"`
+	ReturnRate   float64 `json:"returnRate"`
+}
+
+type SaleReversalReport struct {
+	From      string             `json:"from"`
+	To        string             `json:"to"`
+	ByOutlet  []SaleReversalRate `json:"byOutlet"`
+	ByCashier []SaleReversalRate `json:"byCashier"`
+}
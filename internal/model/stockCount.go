@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+// Status sesi stock opname
+const (
+	StockCountCounting  = "counting"
+	StockCountApproved  = "approved"
+	StockCountCancelled = "cancelled"
+)
+
+// Reference type di ledger stok untuk adjustment hasil stock opname
+const StockReferenceCount = "stock_count"
+
+// StockCount adalah satu sesi stock opname di satu outlet. Expected quantity
+// dibekukan saat sesi dibuat (SnapshotAt).
+type StockCount struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	Number     string           `json:"number" gorm:"not null;size:30;uniqueIndex"`
+	OutletID   uint             `json:"outletId" gorm:"not null;index"`
+	Status     string           `json:"status" gorm:"not null;size:20;index"`
+	Note       string           `json:"note" gorm:"size:255"`
+	SnapshotAt time.Time        `json:"snapshotAt" gorm:"not null"`
+	CreatedBy  string           `json:"createdBy" gorm:"size:100"`
+	ApprovedBy string           `json:"approvedBy" gorm:"size:100"`
+	ApprovedAt *time.Time       `json:"approvedAt"`
+	ReportKey  string           `json:"reportKey" gorm:"size:255"` // key PDF selisih di bucket
+	Lines      []StockCountLine `json:"lines" gorm:"foreignKey:CountID"`
+	CreatedAt  time.Time        `json:"createdAt"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+}
+
+// CountedQuantity nil berarti item belum dihitung. MovedQuantity adalah stok yang
+// bergerak (penjualan, transfer, ...) antara snapshot dan saat item dihitung.
+// Variance = counted - (expected + moved).
+type StockCountLine struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	CountID          uint       `json:"countId" gorm:"not null;uniqueIndex:idx_stock_count_line,priority:1"`
+	ProductID        uint       `json:"productId" gorm:"not null;uniqueIndex:idx_stock_count_line,priority:2"`
+	VariantID        uint       `json:"variantId" gorm:"not null;default:0;uniqueIndex:idx_stock_count_line,priority:3"`
+	ExpectedQuantity float64    `json:"expectedQuantity" gorm:"type:decimal(15,3);not null"`
+	CountedQuantity  *float64   `json:"countedQuantity" gorm:"type:decimal(15,3)"`
+	MovedQuantity    float64    `json:"movedQuantity" gorm:"type:decimal(15,3);not null;default:0"`
+	Variance         *float64   `json:"variance" gorm:"type:decimal(15,3)"`
+	CountedBy        string     `json:"countedBy" gorm:"size:100"`
+	CountedAt        *time.Time `json:"countedAt"`
+}
+
+type StockCountRequest struct {
+	OutletID uint   `json:"outletId" validate:"required"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+type StockCountEntry struct {
+	ProductID uint    `json:"productId" validate:"required"`
+	VariantID uint    `json:"variantId"`
+	Quantity  float64 `json:"quantity" validate:"min=0"`
+}
+
+type StockCountEntryRequest struct {
+	Lines []StockCountEntry `json:"lines" validate:"required,min=1,max=5000,dive"`
+}
+
+// Hasil parsing upload Excel; baris yang gagal dilaporkan per nomor baris
+type StockCountUploadError struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku"`
+	Message string `json:"message"`
+}
+
+// Query string GET /v1/stock-counts
+type StockCountListQuery struct {
+	OutletID uint   `form:"outletId"`
+	Status   string `form:"status" validate:"omitempty,oneof=counting approved cancelled"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type StockCountListResult struct {
+	Counts     []StockCount `json:"counts"`
+	Pagination Pagination   `json:"pagination"`
+}
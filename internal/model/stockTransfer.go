@@ -0,0 +1,81 @@
+package model
+
+import "time"
+
+// Status dokumen transfer stok: draft -> sent -> partially_received -> received
+const (
+	StockTransferDraft             = "draft"
+	StockTransferSent              = "sent"
+	StockTransferPartiallyReceived = "partially_received"
+	StockTransferReceived          = "received"
+	StockTransferCancelled         = "cancelled"
+)
+
+// Reference type di ledger stok untuk movement yang berasal dari transfer
+const StockReferenceTransfer = "stock_transfer"
+
+type StockTransfer struct {
+	ID           uint                `json:"id" gorm:"primaryKey"`
+	Number       string              `json:"number" gorm:"not null;size:30;uniqueIndex"`
+	FromOutletID uint                `json:"fromOutletId" gorm:"not null;index"`
+	ToOutletID   uint                `json:"toOutletId" gorm:"not null;index"`
+	Status       string              `json:"status" gorm:"not null;size:20;index"`
+	Note         string              `json:"note" gorm:"size:255"`
+	CreatedBy    string              `json:"createdBy" gorm:"size:100"`
+	SentBy       string              `json:"sentBy" gorm:"size:100"`
+	SentAt       *time.Time          `json:"sentAt"`
+	ReceivedBy   string              `json:"receivedBy" gorm:"size:100"`
+	ReceivedAt   *time.Time          `json:"receivedAt"`
+	Lines        []StockTransferLine `json:"lines" gorm:"foreignKey:TransferID"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	UpdatedAt    time.Time           `json:"updatedAt"`
+}
+
+// Discrepancy = dikirim - diterima, diisi saat transfer ditutup.
+// Positif berarti barang hilang/rusak di jalan, negatif berarti lebih terima.
+type StockTransferLine struct {
+	ID               uint     `json:"id" gorm:"primaryKey"`
+	TransferID       uint     `json:"transferId" gorm:"not null;index"`
+	ProductID        uint     `json:"productId" gorm:"not null"`
+	VariantID        uint     `json:"variantId" gorm:"not null;default:0"`
+	QuantitySent     float64  `json:"quantitySent" gorm:"type:decimal(15,3);not null"`
+	QuantityReceived float64  `json:"quantityReceived" gorm:"type:decimal(15,3);not null;default:0"`
+	Discrepancy      *float64 `json:"discrepancy" gorm:"type:decimal(15,3)"`
+}
+
+type StockTransferLineInput struct {
+	ProductID uint    `json:"productId" validate:"required"`
+	VariantID uint    `json:"variantId"`
+	Quantity  float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+type StockTransferRequest struct {
+	FromOutletID uint                     `json:"fromOutletId" validate:"required"`
+	ToOutletID   uint                     `json:"toOutletId" validate:"required,nefield=FromOutletID"`
+	Note         string                   `json:"note" validate:"max=255"`
+	Lines        []StockTransferLineInput `json:"lines" validate:"required,min=1,max=500,dive"`
+}
+
+type StockTransferReceiptLine struct {
+	LineID   uint    `json:"lineId" validate:"required"`
+	Quantity float64 `json:"quantity" validate:"min=0"`
+}
+
+// Close menutup transfer walaupun belum semua barang diterima; sisanya dicatat sebagai selisih
+type StockTransferReceiptRequest struct {
+	Lines []StockTransferReceiptLine `json:"lines" validate:"omitempty,max=500,dive"`
+	Close bool                       `json:"close"`
+}
+
+// Query string GET /v1/stock-transfers
+type StockTransferListQuery struct {
+	OutletID uint   `form:"outletId"`
+	Status   string `form:"status" validate:"omitempty,oneof=draft sent partially_received received cancelled"`
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"pageSize" validate:"omitempty,min=1,max=100"`
+}
+
+type StockTransferListResult struct {
+	Transfers  []StockTransfer `json:"transfers"`
+	Pagination Pagination      `json:"pagination"`
+}
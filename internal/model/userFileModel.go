@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+)
+
+// File yang diupload user ke bucket, disimpan supaya bisa diekspor / dihapus per user
+type UserFile struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      string    `json:"userId" gorm:"index;not null;size:100"`
+	FileName    string    `json:"fileName" gorm:"not null;size:255"`
+	Description string    `json:"description" gorm:"type:text"`
+	ObjectKey   string    `json:"objectKey" gorm:"not null;size:255"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"autoCreateTime"`
+}
+
+// Bundle data pribadi seorang user (permintaan "give me all my data")
+type UserDataExport struct {
+	GeneratedAt time.Time                `json:"generatedAt"`
+	User        User                     `json:"user"`
+	Sessions    []map[string]interface{} `json:"sessions"`
+	Activities  []map[string]interface{} `json:"activities"`
+	Files       []UserFile               `json:"files"`
+}
+
+// Ringkasan hasil penghapusan data pribadi
+type UserErasureResult struct {
+	UserID             uint   `json:"userId"`
+	Pseudonym          string `json:"pseudonym"`
+	SessionsDeleted    int64  `json:"sessionsDeleted"`
+	ActivitiesScrubbed int64  `json:"activitiesScrubbed"`
+	FilesDeleted       int    `json:"filesDeleted"`
+}
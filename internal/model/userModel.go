@@ -14,6 +14,7 @@ type UserList struct {
 	IsAktif  string `json:"isAktif"`
 	Address  string `json:"address"` 
 	Password string `json:"password,omitempty"`
+	Version  uint   `json:"version"`
 }
 
 // Struktur untuk input user (misal tambah user via API Admin)
@@ -27,6 +28,20 @@ type UserInput struct {
 	Address   string `json:"address" validate:"required,min=8"`
 	Group     int    `json:"group" validate:"required"`
 	IsAktif   string `json:"isAktif,omitempty"`
+	OutletID  *uint  `json:"outletId"`
+}
+
+// Struktur untuk PATCH user, field nil tidak diubah
+type UserPatchInput struct {
+	FirstName *string `json:"firstName" validate:"omitempty,min=1"`
+	LastName  *string `json:"lastName" validate:"omitempty,min=1"`
+	Email     *string `json:"email" validate:"omitempty,email"`
+	Phone     *string `json:"phone" validate:"omitempty,min=10"`
+	Password  *string `json:"password" validate:"omitempty,min=8"`
+	Address   *string `json:"address" validate:"omitempty,min=8"`
+	Group     *int    `json:"group" validate:"omitempty,min=1"`
+	IsAktif   *string `json:"isAktif" validate:"omitempty"`
+	OutletID  *uint   `json:"outletId"`
 }
 
 // Struktur untuk input register dari user
@@ -39,7 +54,6 @@ type RegisterInput struct {
 	Address             string `json:"address" validate:"required,min=7"`
 	Password            string `json:"password" validate:"required,min=8"`
 	ConfirmPassword     string `json:"confirmPassword" validate:"required"`
-	Group               int    `json:"group" validate:"required"`
 	AgreeTerms          bool   `json:"agreeTerms" validate:"required"`
 	SubscribeNewsletter bool   `json:"subscribeNewsletter"`
 }
@@ -58,6 +72,9 @@ type User struct {
 	AgreeTerms          bool           `json:"agreeTerms" gorm:"default:false"`
 	SubscribeNewsletter bool           `json:"subscribeNewsletter" gorm:"default:false"`
 	Address             string         `json:"address" gorm:"type:text"` 
+	// Outlet tempat user bekerja, NULL untuk user kantor pusat
+	OutletID            *uint          `json:"outletId" gorm:"index"`
+	Version             uint           `json:"version" gorm:"not null;default:1"`
 	CreatedAt           time.Time      `json:"createdAt" gorm:"autoCreateTime"`
 	UpdatedAt           time.Time      `json:"updatedAt" gorm:"autoUpdateTime"`
 	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"BackendFramework/internal/model"
@@ -124,3 +125,84 @@ func (r *AnalyticsRepository) fillMissingMonths(data []model.MonthlyUserData) []
 
 	return result
 }
+
+
+// GetMonthlyCustomerRegistrations menghitung pelanggan baru per bulan selama 12
+// bulan terakhir termasuk bulan berjalan, urut dari bulan terlama. Pelanggan yang
+// dihapus atau digabung tidak dihitung. customerIDs (opsional) membatasi ke
+// pelanggan tertentu, mis. yang pernah belanja di outlet dalam scope.
+func (r *AnalyticsRepository) GetMonthlyCustomerRegistrations(customerIDs *gorm.DB) ([]model.MonthlyCustomerData, error) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -11, 0)
+
+	// Satu bulan sebelum jendela ikut diambil sebagai pembanding growth bulan pertama
+	var counts []struct {
+		Period    string
+		Customers int
+	}
+	err := r.customerQuery(customerIDs).
+		Select("DATE_FORMAT(created_at, '%Y-%m') AS period, COUNT(*) AS customers").
+		Where("created_at >= ?", start.AddDate(0, -1, 0)).
+		Group("period").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %v", err)
+	}
+	byPeriod := make(map[string]int, len(counts))
+	for _, count := range counts {
+		byPeriod[count.Period] = count.Customers
+	}
+
+	result := make([]model.MonthlyCustomerData, 0, 12)
+	prevCustomers := byPeriod[start.AddDate(0, -1, 0).Format("2006-01")]
+	for i := 0; i < 12; i++ {
+		month := start.AddDate(0, i, 0)
+		customers := byPeriod[month.Format("2006-01")]
+		result = append(result, model.MonthlyCustomerData{
+			Period:    month.Format("2006-01"),
+			Month:     month.Format("Jan"),
+			Customers: customers,
+			Growth:    monthlyGrowth(prevCustomers, customers),
+		})
+		prevCustomers = customers
+	}
+
+	return result, nil
+}
+
+// GetTotalCustomers returns total number of active customers
+func (r *AnalyticsRepository) GetTotalCustomers(customerIDs *gorm.DB) (int, error) {
+	var total int64
+	if err := r.customerQuery(customerIDs).Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to get total customers: %v", err)
+	}
+	return int(total), nil
+}
+
+func (r *AnalyticsRepository) GetCurrentMonthCustomers(customerIDs *gorm.DB) (int, error) {
+	var count int64
+	startOfMonth := time.Now().UTC().Truncate(24 * time.Hour).AddDate(0, 0, -time.Now().Day()+1)
+
+	if err := r.customerQuery(customerIDs).
+		Where("created_at >= ?", startOfMonth).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to get current month customers: %v", err)
+	}
+	return int(count), nil
+}
+
+func (r *AnalyticsRepository) customerQuery(customerIDs *gorm.DB) *gorm.DB {
+	query := r.db.Model(&model.Customer{})
+	if customerIDs != nil {
+		query = query.Where("id IN (?)", customerIDs)
+	}
+	return query
+}
+
+// monthlyGrowth adalah persentase kenaikan dari bulan sebelumnya, 1 desimal
+func monthlyGrowth(prev, current int) float64 {
+	if prev == 0 {
+		return 0.0
+	}
+	return math.Round(float64(current-prev)*1000/float64(prev)) / 10
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
     "github.com/gin-gonic/gin"
+    "BackendFramework/internal/config"
     "BackendFramework/internal/controller"
     "BackendFramework/internal/middleware"
     "BackendFramework/internal/model"
@@ -23,6 +24,11 @@ analytics := r.Group("/analytics")
     analytics.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity())
 
     analytics.GET("/user-registrations", controller.GetUserRegistrations)
+
+    // Omset - retur - pengeluaran approved dan pelanggan baru, dibatasi outlet yang boleh diakses
+    analyticsScopeCtrl := controller.NewOutletController()
+    analytics.GET("/new-customers", analyticsScopeCtrl.ResolveOutletScope(), controller.GetNewCustomers)
+    analytics.GET("/net-revenue", analyticsScopeCtrl.ResolveOutletScope(), controller.GetNetRevenue)
 }
 
 
@@ -30,29 +36,456 @@ analytics := r.Group("/analytics")
 outlet := r.Group("/outlets")
 {
     
-    outlet.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity())
-    
     outletInput := &model.Outlet{}
     outletCtrl := controller.NewOutletController()
+
+    // Region managers only see the outlets inside their region subtree
+    outlet.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), outletCtrl.ResolveOutletScope())
+    outletAccess := outletCtrl.RequireOutletAccess()
     
    
     outlet.GET("/", outletCtrl.GetOutlets)
     
     
     outlet.GET("/stats", outletCtrl.GetOutletStats)
+
+    // Bulk master list via Excel
+    outlet.GET("/export", outletCtrl.ExportOutlets)
+    outlet.POST("/import", outletCtrl.ImportOutlets)
+
+    // QR codes for table stickers / feedback forms
+    outlet.GET("/qr-sheet", outletCtrl.GetOutletQRSheet)
+
+    // Location based lookups
+    outlet.GET("/nearby", outletCtrl.GetNearbyOutlets)
+    outlet.GET("/serving", outletCtrl.GetServingOutlets)
     
   
-    outlet.GET("/:id", outletCtrl.GetOutlet)
+    outlet.GET("/:id", outletAccess, outletCtrl.GetOutlet)
+    outlet.GET("/:id/history", outletAccess, outletCtrl.GetOutletHistory)
+    outlet.GET("/:id/qr", outletAccess, outletCtrl.GetOutletQR)
+
+    // QR absensi bertanda tangan yang berganti berkala, ditampilkan supervisor di outlet (clock-in/out karyawan)
+    attendanceQRCtrl := controller.NewAttendanceController()
+    outlet.GET("/:id/attendance-qr", middleware.RequireGroup(config.GROUP_ADMIN, config.GROUP_REGION_MANAGER), outletAccess, attendanceQRCtrl.GetOutletAttendanceQR)
     
     
     outlet.POST("/", middleware.InputValidator(outletInput), outletCtrl.CreateOutlet)
     
 
-    outlet.PUT("/:id", middleware.InputValidator(outletInput), outletCtrl.UpdateOutlet)
-    outlet.PATCH("/:id", middleware.InputValidator(outletInput), outletCtrl.UpdateOutlet)
+    outlet.PUT("/:id", outletAccess, middleware.InputValidator(outletInput), outletCtrl.UpdateOutlet)
+    outlet.PATCH("/:id", outletAccess, outletCtrl.PatchOutlet)
     
   
-    outlet.DELETE("/:id", outletCtrl.DeleteOutlet)
+    outlet.DELETE("/:id", outletAccess, outletCtrl.DeleteOutlet)
+
+    // Holiday / special-day overrides of the opening hours
+    outlet.POST("/:id/special-days", outletAccess, outletCtrl.SaveSpecialDay)
+    outlet.DELETE("/:id/special-days/:dayId", outletAccess, outletCtrl.DeleteSpecialDay)
+
+    // Delivery-area polygons
+    outlet.GET("/:id/delivery-areas", outletAccess, outletCtrl.GetDeliveryAreas)
+    outlet.POST("/:id/delivery-areas", outletAccess, outletCtrl.CreateDeliveryArea)
+    outlet.DELETE("/:id/delivery-areas/:areaId", outletAccess, outletCtrl.DeleteDeliveryArea)
+}
+
+// ---------------- PUBLIC ----------------
+// Halaman outlet yang dibuka dari QR code, tanpa login
+public := r.Group("/public")
+{
+    publicOutletCtrl := controller.NewOutletController()
+
+    public.GET("/outlets/:id", publicOutletCtrl.GetPublicOutlet)
+}
+
+// ---------------- BRAND & REGION ----------------
+regionCtrl := controller.NewRegionController()
+adminOnly := middleware.RequireGroup(config.GROUP_ADMIN)
+
+brand := r.Group("/brands")
+{
+    brand.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity())
+
+    brand.GET("/", regionCtrl.GetBrands)
+    brand.POST("/", adminOnly, regionCtrl.CreateBrand)
+    brand.PUT("/:id", adminOnly, regionCtrl.UpdateBrand)
+    brand.DELETE("/:id", adminOnly, regionCtrl.DeleteBrand)
+}
+
+region := r.Group("/regions")
+{
+    region.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity())
+
+    region.GET("/", regionCtrl.GetRegions)
+    region.GET("/tree", regionCtrl.GetRegionTree)
+    region.POST("/", adminOnly, regionCtrl.CreateRegion)
+    region.PUT("/:id", adminOnly, regionCtrl.UpdateRegion)
+    region.DELETE("/:id", adminOnly, regionCtrl.DeleteRegion)
+}
+
+
+// ---------------- PRODUCT CATALOG ----------------
+productCtrl := controller.NewProductController()
+
+product := r.Group("/products")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+    productAdminOnly := middleware.RequireGroup(config.GROUP_ADMIN)
+    supervisorOnly := middleware.RequireGroup(config.GROUP_ADMIN, config.GROUP_REGION_MANAGER)
+
+    product.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity())
+
+    product.GET("/", productCtrl.GetProducts)
+    product.GET("/lookup/:code", productCtrl.GetProductByCode)
+    product.GET("/:id", productCtrl.GetProduct)
+    product.GET("/:id/price", productCtrl.ResolvePrice)
+    product.POST("/", productAdminOnly, productCtrl.CreateProduct)
+    product.PUT("/:id", productAdminOnly, productCtrl.UpdateProduct)
+    product.DELETE("/:id", productAdminOnly, productCtrl.DeleteProduct)
+
+    // Harga khusus per outlet, region manager hanya untuk outlet di region-nya
+    product.PUT("/:id/outlet-prices/:outletId", supervisorOnly, scopeOutletCtrl.ResolveOutletScope(), productCtrl.SetOutletPrice)
+    product.DELETE("/:id/outlet-prices/:outletId", supervisorOnly, scopeOutletCtrl.ResolveOutletScope(), productCtrl.DeleteOutletPrice)
+}
+
+productCategory := r.Group("/product-categories")
+{
+    productCategory.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity())
+
+    productCategory.GET("/", productCtrl.GetCategories)
+    productCategory.POST("/", middleware.RequireGroup(config.GROUP_ADMIN), productCtrl.CreateCategory)
+    productCategory.PUT("/:id", middleware.RequireGroup(config.GROUP_ADMIN), productCtrl.UpdateCategory)
+    productCategory.DELETE("/:id", middleware.RequireGroup(config.GROUP_ADMIN), productCtrl.DeleteCategory)
+}
+
+// ---------------- INVENTORY ----------------
+inventoryCtrl := controller.NewInventoryController()
+
+inventory := r.Group("/inventory")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+
+    inventory.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    // Stok saat ini (cache) atau per tanggal lampau (?asOf=)
+    inventory.GET("/stock", inventoryCtrl.GetStock)
+    inventory.GET("/stock/low", inventoryCtrl.GetLowStock)
+    inventory.PUT("/thresholds", inventoryCtrl.SetThreshold)
+
+    // Ledger pergerakan stok (append-only)
+    inventory.GET("/movements", inventoryCtrl.GetMovements)
+    inventory.POST("/movements", inventoryCtrl.RecordMovements)
+
+    // Hitung ulang cache stok dari ledger
+    inventory.POST("/rebuild", adminOnly, inventoryCtrl.RebuildStockLevels)
+}
+
+stockTransferCtrl := controller.NewStockTransferController()
+
+stockTransfer := r.Group("/stock-transfers")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+
+    stockTransfer.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    stockTransfer.GET("/", stockTransferCtrl.GetTransfers)
+    stockTransfer.GET("/:id", stockTransferCtrl.GetTransfer)
+    stockTransfer.POST("/", stockTransferCtrl.CreateTransfer)
+    stockTransfer.PUT("/:id", stockTransferCtrl.UpdateTransfer)
+
+    // draft -> sent -> partially_received -> received
+    stockTransfer.POST("/:id/send", stockTransferCtrl.SendTransfer)
+    stockTransfer.POST("/:id/receive", stockTransferCtrl.ReceiveTransfer)
+    stockTransfer.POST("/:id/cancel", stockTransferCtrl.CancelTransfer)
+}
+
+stockCountCtrl := controller.NewStockCountController()
+
+stockCount := r.Group("/stock-counts")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+    supervisorOnly := middleware.RequireGroup(config.GROUP_ADMIN, config.GROUP_REGION_MANAGER)
+
+    stockCount.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    stockCount.GET("/", stockCountCtrl.GetCounts)
+    stockCount.GET("/:id", stockCountCtrl.GetCount)
+    stockCount.GET("/:id/variance-report", stockCountCtrl.GetVarianceReport)
+    stockCount.POST("/", stockCountCtrl.CreateCount)
+
+    // Input hasil hitung manual atau upload Excel (kolom SKU, Counted Quantity)
+    stockCount.PUT("/:id/lines", stockCountCtrl.EnterCounts)
+    stockCount.POST("/:id/import", stockCountCtrl.ImportCounts)
+
+    // Approval memposting adjustment ke ledger stok
+    stockCount.POST("/:id/approve", supervisorOnly, stockCountCtrl.ApproveCount)
+    stockCount.POST("/:id/cancel", supervisorOnly, stockCountCtrl.CancelCount)
+}
+
+
+// ---------------- SALES (POS) ----------------
+saleCtrl := controller.NewSaleController()
+
+sale := r.Group("/sales")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+
+    sale.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    sale.GET("/", saleCtrl.GetSales)
+    sale.GET("/:id", saleCtrl.GetSale)
+
+    // POS mengecek transaksi yang responsnya hilang karena jaringan putus
+    sale.GET("/client/:clientTransactionId", saleCtrl.GetSaleByClientID)
+
+    // Idempotent berdasarkan clientTransactionId; pembayaran tunai butuh shift kasir yang terbuka
+    sale.POST("/", saleCtrl.CreateSale)
+
+    // Pembatalan (void, sebelum hari ditutup) & retur; diposting setelah disetujui supervisor
+    supervisorOnly := middleware.RequireGroup(config.GROUP_ADMIN, config.GROUP_REGION_MANAGER)
+    sale.POST("/:id/void", saleCtrl.RequestVoid)
+    sale.POST("/:id/returns", saleCtrl.RequestReturn)
+    sale.GET("/reversals", saleCtrl.GetReversals)
+    sale.GET("/reversals/:id", saleCtrl.GetReversal)
+    sale.POST("/reversals/:id/approve", supervisorOnly, saleCtrl.ApproveReversal)
+    sale.POST("/reversals/:id/reject", supervisorOnly, saleCtrl.RejectReversal)
+    sale.GET("/reversal-report", saleCtrl.GetReversalReport)
+}
+
+// ---------------- CASH SHIFTS ----------------
+cashShiftCtrl := controller.NewCashShiftController()
+
+cashShift := r.Group("/cash-shifts")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+    supervisorOnly := middleware.RequireGroup(config.GROUP_ADMIN, config.GROUP_REGION_MANAGER)
+
+    cashShift.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    // Tutup hari per outlet, ditolak selama masih ada shift yang belum direkonsiliasi
+    cashShift.GET("/daily-closings", cashShiftCtrl.GetDailyClosings)
+    cashShift.POST("/daily-closings", supervisorOnly, cashShiftCtrl.CloseDay)
+
+    cashShift.GET("/", cashShiftCtrl.GetShifts)
+    cashShift.GET("/current", cashShiftCtrl.GetCurrentShift)
+    cashShift.GET("/:id", cashShiftCtrl.GetShift)
+    cashShift.GET("/:id/report", cashShiftCtrl.GetShiftReport)
+
+    // open (dengan float) -> closed (hitung kas per pecahan) -> reconciled (sign-off manajer)
+    cashShift.POST("/", cashShiftCtrl.OpenShift)
+    cashShift.POST("/:id/movements", cashShiftCtrl.AddMovement)
+    cashShift.POST("/:id/close", cashShiftCtrl.CloseShift)
+    cashShift.POST("/:id/sign-off", supervisorOnly, cashShiftCtrl.SignOff)
+}
+
+invoiceCtrl := controller.NewInvoiceController()
+
+invoice := r.Group("/invoices")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+
+    invoice.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    invoice.GET("/", invoiceCtrl.GetInvoices)
+    invoice.GET("/:id", invoiceCtrl.GetInvoice)
+    invoice.GET("/:id/pdf", invoiceCtrl.GetInvoicePDF)
+
+    // Nomor invoice berurutan per outlet per tahun, PDF diarsipkan ke bucket
+    invoice.POST("/", invoiceCtrl.CreateInvoice)
+}
+
+// ---------------- CUSTOMERS ----------------
+customerCtrl := controller.NewCustomerController()
+loyaltyCtrl := controller.NewLoyaltyController()
+receivableCtrl := controller.NewReceivableController()
+
+customer := r.Group("/customers")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+
+    customer.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    customer.GET("/", customerCtrl.GetCustomers)
+    customer.GET("/:id", customerCtrl.GetCustomer)
+
+    // Riwayat belanja, hanya transaksi di outlet yang boleh diakses
+    customer.GET("/:id/sales", customerCtrl.GetCustomerSales)
+
+    // 409 dengan data pelanggan lama jika nomor HP / email sudah terdaftar
+    customer.POST("/", customerCtrl.CreateCustomer)
+    customer.PUT("/:id", customerCtrl.UpdateCustomer)
+    customer.DELETE("/:id", adminOnly, customerCtrl.DeleteCustomer)
+    customer.POST("/:id/merge", adminOnly, customerCtrl.MergeCustomer)
+
+    // Saldo poin, tier dan ledger poin pelanggan
+    customer.GET("/:id/loyalty", loyaltyCtrl.GetCustomerLoyalty)
+    customer.GET("/:id/loyalty/ledger", loyaltyCtrl.GetCustomerLedger)
+    customer.POST("/:id/loyalty/adjustments", adminOnly, loyaltyCtrl.AdjustPoints)
+
+    // Rekening koran piutang (PDF)
+    customer.GET("/:id/statement", receivableCtrl.GetCustomerStatement)
+}
+
+// ---------------- LOYALTY ----------------
+loyalty := r.Group("/loyalty")
+{
+    loyalty.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity())
+
+    loyalty.GET("/rules", loyaltyCtrl.GetRules)
+    loyalty.POST("/rules", adminOnly, loyaltyCtrl.CreateRule)
+    loyalty.PUT("/rules/:id", adminOnly, loyaltyCtrl.UpdateRule)
+    loyalty.DELETE("/rules/:id", adminOnly, loyaltyCtrl.DeleteRule)
+
+    loyalty.GET("/tiers", loyaltyCtrl.GetTiers)
+    loyalty.POST("/tiers", adminOnly, loyaltyCtrl.CreateTier)
+    loyalty.PUT("/tiers/:id", adminOnly, loyaltyCtrl.UpdateTier)
+    loyalty.DELETE("/tiers/:id", adminOnly, loyaltyCtrl.DeleteTier)
+
+    // Poin hangus & turun tier, dipanggil harian oleh scheduler
+    loyalty.POST("/maintenance", adminOnly, loyaltyCtrl.RunMaintenance)
+}
+
+// ---------------- RECEIVABLES ----------------
+receivable := r.Group("/receivables")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+
+    receivable.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    receivable.GET("/", receivableCtrl.GetReceivables)
+    // Umur piutang per pelanggan: belum jatuh tempo, 0-30, 31-60, 61-90, >90 hari
+    receivable.GET("/aging", receivableCtrl.GetAging)
+    receivable.GET("/:id", receivableCtrl.GetReceivable)
+    receivable.POST("/:id/payments", receivableCtrl.RecordPayment)
+
+    // Email pengingat piutang jatuh tempo, dipanggil harian oleh scheduler
+    receivable.POST("/reminders", adminOnly, receivableCtrl.SendReminders)
+}
+
+// ---------------- EXPENSES ----------------
+expenseCtrl := controller.NewExpenseController()
+
+expense := r.Group("/expenses")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+    supervisorOnly := middleware.RequireGroup(config.GROUP_ADMIN, config.GROUP_REGION_MANAGER)
+
+    expense.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    expense.GET("/categories", expenseCtrl.GetCategories)
+    expense.POST("/categories", adminOnly, expenseCtrl.CreateCategory)
+    expense.PUT("/categories/:id", adminOnly, expenseCtrl.UpdateCategory)
+    expense.DELETE("/categories/:id", adminOnly, expenseCtrl.DeleteCategory)
+
+    expense.GET("/", expenseCtrl.GetExpenses)
+    expense.GET("/:id", expenseCtrl.GetExpense)
+    // Di atas EXPENSE_APPROVAL_THRESHOLD berstatus pending sampai disetujui supervisor
+    expense.POST("/", expenseCtrl.CreateExpense)
+    expense.PUT("/:id", expenseCtrl.UpdateExpense)
+    expense.DELETE("/:id", expenseCtrl.DeleteExpense)
+
+    // Foto/scan nota (jpg, png, webp, pdf) disimpan di bucket
+    expense.POST("/:id/receipt", expenseCtrl.UploadReceipt)
+
+    expense.POST("/:id/approve", supervisorOnly, expenseCtrl.ApproveExpense)
+    expense.POST("/:id/reject", supervisorOnly, expenseCtrl.RejectExpense)
+}
+
+// ---------------- EMPLOYEES ----------------
+employeeCtrl := controller.NewEmployeeController()
+
+employee := r.Group("/employees")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+    supervisorOnly := middleware.RequireGroup(config.GROUP_ADMIN, config.GROUP_REGION_MANAGER)
+
+    // Data gaji hanya untuk admin dan region manager (dibatasi region-nya)
+    employee.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), supervisorOnly, scopeOutletCtrl.ResolveOutletScope())
+
+    employee.GET("/", employeeCtrl.GetEmployees)
+    employee.GET("/:id", employeeCtrl.GetEmployee)
+    employee.POST("/", employeeCtrl.CreateEmployee)
+    employee.PUT("/:id", employeeCtrl.UpdateEmployee)
+
+    // Tunjangan/potongan tetap bulanan, body menggantikan seluruh komponen
+    employee.PUT("/:id/components", employeeCtrl.SetComponents)
+
+    employee.GET("/:id/overtime", employeeCtrl.GetOvertime)
+    employee.POST("/:id/overtime", employeeCtrl.AddOvertime)
+    employee.DELETE("/:id/overtime/:overtimeId", employeeCtrl.DeleteOvertime)
+}
+
+// ---------------- ATTENDANCE ----------------
+attendanceCtrl := controller.NewAttendanceController()
+
+attendance := r.Group("/attendance")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+    supervisorOnly := middleware.RequireGroup(config.GROUP_ADMIN, config.GROUP_REGION_MANAGER)
+
+    attendance.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    // Karyawan (user yang terhubung ke employee): scan QR absensi outlet + GPS dalam geofence
+    attendance.POST("/clock-in", attendanceCtrl.ClockIn)
+    attendance.POST("/clock-out", attendanceCtrl.ClockOut)
+    attendance.GET("/me", attendanceCtrl.GetMyAttendance)
+
+    // Jadwal shift per outlet
+    attendance.GET("/schedules", attendanceCtrl.GetSchedules)
+    attendance.POST("/schedules", supervisorOnly, attendanceCtrl.CreateSchedules)
+    attendance.PUT("/schedules/:id", supervisorOnly, attendanceCtrl.UpdateSchedule)
+    attendance.DELETE("/schedules/:id", supervisorOnly, attendanceCtrl.DeleteSchedule)
+
+    attendance.GET("/", supervisorOnly, attendanceCtrl.GetAttendances)
+    // Tutup absensi yang lupa clock-out (tanpa lembur) dan cek lembur yang melewati batas
+    attendance.POST("/:id/close", supervisorOnly, attendanceCtrl.CloseAttendance)
+    attendance.POST("/:id/overtime-review", supervisorOnly, attendanceCtrl.ReviewOvertime)
+    // Terlambat, tidak hadir dan lembur per karyawan
+    attendance.GET("/reports", supervisorOnly, attendanceCtrl.GetReport)
+    // Teruskan lembur dari absensi ke data lembur payroll
+    attendance.POST("/overtime/sync", supervisorOnly, attendanceCtrl.SyncOvertime)
+}
+
+// ---------------- PAYROLL ----------------
+payrollCtrl := controller.NewPayrollController()
+
+payroll := r.Group("/payroll")
+{
+    payroll.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), adminOnly)
+
+    payroll.GET("/runs", payrollCtrl.GetRuns)
+    payroll.GET("/runs/:id", payrollCtrl.GetRun)
+    // Draft dihitung langsung; hitung ulang sebanyak perlu sebelum dikunci
+    payroll.POST("/runs", payrollCtrl.CreateRun)
+    payroll.POST("/runs/:id/recalculate", payrollCtrl.RecalculateRun)
+    payroll.POST("/runs/:id/lock", payrollCtrl.LockRun)
+    payroll.DELETE("/runs/:id", payrollCtrl.DeleteRun)
+
+    // Slip gaji PDF per karyawan, dikirim via email setelah run dikunci
+    payroll.GET("/runs/:id/payslips/:payslipId/pdf", payrollCtrl.GetPayslipPDF)
+    payroll.POST("/runs/:id/payslips/send", payrollCtrl.SendPayslips)
+
+    // Rekap payroll (xlsx) untuk akuntan
+    payroll.GET("/runs/:id/export", payrollCtrl.ExportRun)
+}
+
+// ---------------- PROMOTIONS ----------------
+promotionCtrl := controller.NewPromotionController()
+
+promotion := r.Group("/promotions")
+{
+    scopeOutletCtrl := controller.NewOutletController()
+
+    promotion.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), scopeOutletCtrl.ResolveOutletScope())
+
+    promotion.GET("/", promotionCtrl.GetPromotions)
+    promotion.GET("/:id", promotionCtrl.GetPromotion)
+    promotion.POST("/", adminOnly, promotionCtrl.CreatePromotion)
+    promotion.PUT("/:id", adminOnly, promotionCtrl.UpdatePromotion)
+    promotion.DELETE("/:id", adminOnly, promotionCtrl.DeletePromotion)
+
+    // Simulasi keranjang: promo yang berlaku dan alasan promo lain tidak berlaku
+    promotion.POST("/preview", promotionCtrl.PreviewPromotions)
 }
 
 
@@ -63,6 +496,8 @@ outlet := r.Group("/outlets")
         user.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity())
         
         userInput := &model.UserInput{}
+        // Grup dan status aktif user hanya boleh diatur admin
+        userAdminOnly := middleware.RequireGroup(config.GROUP_ADMIN)
         
         // READ - Get all users 
         user.GET("/", controller.GetUser)
@@ -70,18 +505,33 @@ outlet := r.Group("/outlets")
         // READ - Get specific user 
         user.GET("/:usrId", controller.GetUser)
         
+        // HISTORY - Field-level change history of a user
+        user.GET("/:usrId/history", controller.GetUserHistory)
+        
         // PROFILE - Get current user profile 
         user.GET("/profile", controller.GetUserProfile)
         
         // CREATE - Insert new user 
-        user.POST("/", middleware.InputValidator(userInput), controller.InsertUser)
+        user.POST("/", userAdminOnly, middleware.InputValidator(userInput), controller.InsertUser)
         
         // UPDATE - Update user 
-        user.PUT("/:usrId", middleware.InputValidator(userInput), controller.UpdateUser)
-        user.PATCH("/:usrId", middleware.InputValidator(userInput), controller.UpdateUser)
+        user.PUT("/:usrId", userAdminOnly, middleware.InputValidator(userInput), controller.UpdateUser)
+        user.PATCH("/:usrId", controller.PatchUser)
         
         // DELETE - Delete user 
-        user.DELETE("/:usrId", controller.DeleteUser)
+        user.DELETE("/:usrId", userAdminOnly, controller.DeleteUser)
+    }
+
+    // ---------------- ADMIN ----------------
+    admin := r.Group("/admin")
+    {
+        admin.Use(middleware.JWTAuthMiddleware(), middleware.LogUserActivity(), middleware.RequireGroup(config.GROUP_ADMIN))
+
+        privacyCtrl := controller.NewPrivacyController()
+
+        // PRIVACY - Export / erase personal data of a user
+        admin.GET("/users/:usrId/export", privacyCtrl.ExportUserData)
+        admin.DELETE("/users/:usrId/personal-data", privacyCtrl.EraseUserData)
     }
 
     // ---------------- MISC ----------------
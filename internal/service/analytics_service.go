@@ -2,6 +2,9 @@
 package service
 
 import (
+	"gorm.io/gorm"
+
+	"BackendFramework/internal/database"
 	"BackendFramework/internal/model"
 	"BackendFramework/internal/repository"
 )
@@ -51,4 +54,47 @@ func (s *AnalyticsService) GetUserRegistrationAnalytics() (*model.AnalyticsRespo
 	}
 
 	return response, nil
+}
+
+// GetCustomerAnalytics menghitung pelanggan baru. Pelanggan berlaku untuk semua
+// outlet, jadi untuk region manager atau filter outlet yang dihitung hanya
+// pelanggan yang pernah belanja di outlet tersebut.
+func (s *AnalyticsService) GetCustomerAnalytics(params model.CustomerAnalyticsQuery, scope model.OutletScope) (*model.CustomerAnalyticsResponse, error) {
+	var customerIDs *gorm.DB
+	if scope.Restricted || params.OutletID != 0 {
+		customerIDs = scopeOutletColumn(database.DbCore.Model(&model.Sale{}), "outlet_id", scope).
+			Select("DISTINCT customer_id").
+			Where("customer_id IS NOT NULL")
+		if params.OutletID != 0 {
+			customerIDs = customerIDs.Where("outlet_id = ?", params.OutletID)
+		}
+	}
+
+	monthlyData, err := s.repo.GetMonthlyCustomerRegistrations(customerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCustomers, err := s.repo.GetTotalCustomers(customerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	currentMonth, err := s.repo.GetCurrentMonthCustomers(customerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Data urut kronologis, entri terakhir adalah bulan berjalan
+	var growthRate float64 = 0.0
+	if len(monthlyData) >= 2 {
+		growthRate = monthlyData[len(monthlyData)-1].Growth
+	}
+
+	return &model.CustomerAnalyticsResponse{
+		MonthlyNewCustomers: monthlyData,
+		TotalCustomers:      totalCustomers,
+		CurrentMonth:        currentMonth,
+		GrowthRate:          growthRate,
+	}, nil
 }
\ No newline at end of file
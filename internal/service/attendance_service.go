@@ -0,0 +1,799 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var (
+	ErrShiftNotFound              = errors.New("shift schedule not found")
+	ErrShiftExists                = errors.New("employee already has a shift on this date")
+	ErrShiftAttended              = errors.New("shift already has an attendance and cannot be changed")
+	ErrAttendanceQRInvalid        = errors.New("invalid attendance QR code")
+	ErrAttendanceQRExpired        = errors.New("attendance QR code has expired; scan the current code at the outlet")
+	ErrAttendanceNoEmployee       = errors.New("user is not linked to an employee")
+	ErrAttendanceOutletLocation   = errors.New("outlet location is not set")
+	ErrAttendanceOutsideGeofence  = errors.New("you are too far from the outlet")
+	ErrAttendanceAlreadyClockedIn = errors.New("already clocked in")
+	ErrAttendanceNotClockedIn     = errors.New("not clocked in")
+	ErrAttendanceOutletMismatch   = errors.New("clock out must be done at the outlet of the clock in")
+	ErrAttendanceNotFound         = errors.New("attendance not found")
+	ErrAttendanceClosed           = errors.New("attendance is already clocked out")
+	ErrAttendanceClockOutTime     = errors.New("clock-out time must be between the clock-in and now")
+	ErrAttendanceNoReview         = errors.New("attendance overtime is not waiting for review")
+	ErrAttendanceOvertimeTooLong  = errors.New("overtime cannot exceed the time worked after the shift")
+)
+
+// Isi QR absensi: <PUBLIC_BASE_URL>/attendance/outlets/<id>?exp=<unix>&sig=<hmac>.
+// Berbeda dengan QR halaman publik outlet, tanda tangan mencegah QR dibuat
+// sendiri dan exp membuat QR kedaluwarsa, jadi QR harus ditampilkan ulang di
+// outlet (mis. tablet supervisor) dan foto QR lama tidak bisa dipakai.
+var attendanceQRPath = regexp.MustCompile(`/attendance/outlets/(\d+)$`)
+
+type AttendanceService struct{}
+
+func NewAttendanceService() *AttendanceService {
+	return &AttendanceService{}
+}
+
+// ---------------- QR ----------------
+
+// AttendanceQRContent is the signed content of an outlet's attendance QR code,
+// valid until expiresAt.
+func AttendanceQRContent(outletID uint, expiresAt time.Time) string {
+	exp := expiresAt.Unix()
+	return fmt.Sprintf("%s/attendance/outlets/%d?exp=%d&sig=%s", config.PUBLIC_BASE_URL, outletID, exp, attendanceQRSignature(outletID, exp))
+}
+
+// AttendanceQRFile writes a fresh attendance QR code of the outlet to a temporary
+// PNG and returns its path and expiry. The caller removes the file after sending it.
+func (s *AttendanceService) AttendanceQRFile(outletID uint) (string, time.Time, error) {
+	var outlet model.Outlet
+	if err := database.DbCore.Select("id").First(&outlet, outletID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", time.Time{}, ErrStockOutletScope
+		}
+		return "", time.Time{}, err
+	}
+
+	file, err := os.CreateTemp("", "attendance-qr-*.png")
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	file.Close()
+
+	expiresAt := time.Now().Add(time.Duration(config.ATTENDANCE_QR_TTL_MINUTES) * time.Minute)
+	if !thirdparty.GenerateQrFile(AttendanceQRContent(outlet.ID, expiresAt), file.Name()) {
+		os.Remove(file.Name())
+		return "", time.Time{}, errors.New("failed to generate attendance QR code")
+	}
+	return file.Name(), expiresAt, nil
+}
+
+func attendanceQRSignature(outletID uint, exp int64) string {
+	mac := hmac.New(sha256.New, []byte(config.ENCRYPTION_KEY))
+	mac.Write([]byte("attendance-outlet:" + strconv.FormatUint(uint64(outletID), 10) + ":" + strconv.FormatInt(exp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// parseAttendanceQR returns the outlet of a scanned attendance QR code that has
+// not expired yet.
+func parseAttendanceQR(content string, now time.Time) (uint, error) {
+	parsed, err := url.Parse(content)
+	if err != nil {
+		return 0, ErrAttendanceQRInvalid
+	}
+	match := attendanceQRPath.FindStringSubmatch(parsed.Path)
+	if match == nil {
+		return 0, ErrAttendanceQRInvalid
+	}
+	id, err := strconv.ParseUint(match[1], 10, 32)
+	if err != nil {
+		return 0, ErrAttendanceQRInvalid
+	}
+	exp, err := strconv.ParseInt(parsed.Query().Get("exp"), 10, 64)
+	if err != nil {
+		return 0, ErrAttendanceQRInvalid
+	}
+	expected := attendanceQRSignature(uint(id), exp)
+	if !hmac.Equal([]byte(parsed.Query().Get("sig")), []byte(expected)) {
+		return 0, ErrAttendanceQRInvalid
+	}
+	if now.Unix() > exp {
+		return 0, ErrAttendanceQRExpired
+	}
+	return uint(id), nil
+}
+
+// ---------------- SCHEDULE ----------------
+
+func (s *AttendanceService) GetSchedules(params model.ShiftScheduleQuery, scope model.OutletScope) ([]model.ShiftSchedule, error) {
+	query := scopeOutletColumn(preloadEmployeeSummary(database.DbCore), "outlet_id", scope).
+		Where("date >= ? AND date <= ?", params.From, params.To)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.EmployeeID != 0 {
+		query = query.Where("employee_id = ?", params.EmployeeID)
+	}
+	schedules := []model.ShiftSchedule{}
+	err := query.Order("date ASC").Order("scheduled_start ASC").Order("id ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// CreateSchedules saves a batch of shifts at once; one invalid shift rejects the
+// whole batch.
+func (s *AttendanceService) CreateSchedules(req model.ShiftScheduleBatchRequest, scope model.OutletScope, actorID string) ([]model.ShiftSchedule, error) {
+	schedules := make([]model.ShiftSchedule, len(req.Shifts))
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		for i, shift := range req.Shifts {
+			schedules[i].CreatedBy = actorID
+			if err := applyShiftRequest(tx, &schedules[i], shift, scope); err != nil {
+				return fmt.Errorf("shift %d: %w", i+1, err)
+			}
+			if err := tx.Create(&schedules[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (s *AttendanceService) UpdateSchedule(id uint, req model.ShiftScheduleRequest, scope model.OutletScope) (*model.ShiftSchedule, error) {
+	var schedule *model.ShiftSchedule
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var err error
+		schedule, err = lockUnattendedShift(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if err := applyShiftRequest(tx, schedule, req, scope); err != nil {
+			return err
+		}
+		return tx.Save(schedule).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *AttendanceService) DeleteSchedule(id uint, scope model.OutletScope) error {
+	return database.DbCore.Transaction(func(tx *gorm.DB) error {
+		schedule, err := lockUnattendedShift(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		return tx.Delete(schedule).Error
+	})
+}
+
+// applyShiftRequest checks the employee, the outlet and the one-shift-per-day rule,
+// then resolves the shift hours in the outlet's time zone.
+func applyShiftRequest(tx *gorm.DB, schedule *model.ShiftSchedule, req model.ShiftScheduleRequest, scope model.OutletScope) error {
+	employee, err := findEmployee(tx, req.EmployeeID, scope)
+	if err != nil {
+		return err
+	}
+	if !employee.Active {
+		return ErrEmployeeInactive
+	}
+	if err := checkOutletInScope(tx, req.OutletID, scope); err != nil {
+		return err
+	}
+	var outlet model.Outlet
+	if err := tx.Select("id", "time_zone").First(&outlet, req.OutletID).Error; err != nil {
+		return err
+	}
+
+	loc := outlet.Location()
+	date, err := time.ParseInLocation("2006-01-02", req.Date, loc)
+	if err != nil {
+		return err
+	}
+	start, err := time.ParseInLocation("2006-01-02 15:04", req.Date+" "+req.StartTime, loc)
+	if err != nil {
+		return err
+	}
+	end, err := time.ParseInLocation("2006-01-02 15:04", req.Date+" "+req.EndTime, loc)
+	if err != nil {
+		return err
+	}
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	var count int64
+	err = tx.Model(&model.ShiftSchedule{}).
+		Where("employee_id = ? AND date = ? AND id <> ?", employee.ID, req.Date, schedule.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrShiftExists
+	}
+
+	schedule.EmployeeID = employee.ID
+	schedule.Employee = nil
+	schedule.OutletID = req.OutletID
+	schedule.Date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	schedule.StartTime = req.StartTime
+	schedule.EndTime = req.EndTime
+	schedule.ScheduledStart = start
+	schedule.ScheduledEnd = end
+	schedule.Note = req.Note
+	return nil
+}
+
+func lockUnattendedShift(tx *gorm.DB, id uint, scope model.OutletScope) (*model.ShiftSchedule, error) {
+	var schedule model.ShiftSchedule
+	err := scopeOutletColumn(tx.Clauses(clause.Locking{Strength: "UPDATE"}), "outlet_id", scope).
+		First(&schedule, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShiftNotFound
+		}
+		return nil, err
+	}
+	var count int64
+	if err := tx.Model(&model.Attendance{}).Where("schedule_id = ?", schedule.ID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrShiftAttended
+	}
+	return &schedule, nil
+}
+
+// ---------------- CLOCK IN / OUT ----------------
+
+// ClockIn records the arrival of the employee linked to the user. The scanned QR
+// must be a valid, unexpired attendance QR and the device must be inside the outlet's
+// geofence. The arrival is matched to the employee's shift at that outlet that
+// starts within ATTENDANCE_EARLY_CLOCK_IN_MINUTES and has not ended yet.
+func (s *AttendanceService) ClockIn(userID string, req model.ClockRequest) (*model.Attendance, error) {
+	outletID, err := parseAttendanceQR(req.QRCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var attendance model.Attendance
+	err = database.DbCore.Transaction(func(tx *gorm.DB) error {
+		employee, err := lockAttendanceEmployee(tx, userID)
+		if err != nil {
+			return err
+		}
+		distance, err := checkAttendanceGeofence(tx, outletID, *req.Latitude, *req.Longitude)
+		if err != nil {
+			return err
+		}
+
+		var open int64
+		if err := tx.Model(&model.Attendance{}).Where("employee_id = ? AND clock_out_at IS NULL", employee.ID).Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return ErrAttendanceAlreadyClockedIn
+		}
+
+		now := time.Now()
+		attendance = model.Attendance{
+			EmployeeID:       employee.ID,
+			OutletID:         outletID,
+			ClockInAt:        now,
+			ClockInLatitude:  *req.Latitude,
+			ClockInLongitude: *req.Longitude,
+			ClockInDistance:  distance,
+		}
+
+		var schedule model.ShiftSchedule
+		attended := tx.Model(&model.Attendance{}).Select("schedule_id").Where("schedule_id IS NOT NULL")
+		err = tx.Where("employee_id = ? AND outlet_id = ?", employee.ID, outletID).
+			Where("scheduled_start <= ? AND scheduled_end > ?", now.Add(time.Duration(config.ATTENDANCE_EARLY_CLOCK_IN_MINUTES)*time.Minute), now).
+			Where("id NOT IN (?)", attended).
+			Order("scheduled_start ASC").
+			First(&schedule).Error
+		if err == nil {
+			attendance.ScheduleID = &schedule.ID
+			attendance.LateMinutes = lateMinutes(schedule.ScheduledStart, now)
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		return tx.Create(&attendance).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findAttendance(attendance.ID)
+}
+
+// ClockOut closes the employee's open attendance at the same outlet and works out
+// early leave and overtime against the shift. Overtime past the cap or into the
+// next shift is cut there and left for a supervisor to review.
+func (s *AttendanceService) ClockOut(userID string, req model.ClockRequest) (*model.Attendance, error) {
+	outletID, err := parseAttendanceQR(req.QRCode, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var attendance model.Attendance
+	err = database.DbCore.Transaction(func(tx *gorm.DB) error {
+		employee, err := lockAttendanceEmployee(tx, userID)
+		if err != nil {
+			return err
+		}
+		err = tx.Preload("Schedule").
+			Where("employee_id = ? AND clock_out_at IS NULL", employee.ID).
+			Order("clock_in_at DESC").
+			First(&attendance).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAttendanceNotClockedIn
+			}
+			return err
+		}
+		if attendance.OutletID != outletID {
+			return ErrAttendanceOutletMismatch
+		}
+		distance, err := checkAttendanceGeofence(tx, outletID, *req.Latitude, *req.Longitude)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		attendance.ClockOutAt = &now
+		attendance.ClockOutLatitude = req.Latitude
+		attendance.ClockOutLongitude = req.Longitude
+		attendance.ClockOutDistance = &distance
+		attendance.WorkedMinutes = int(now.Sub(attendance.ClockInAt).Minutes())
+		if attendance.Schedule != nil {
+			attendance.EarlyLeaveMinutes = max(int(attendance.Schedule.ScheduledEnd.Sub(now).Minutes()), 0)
+			nextStart, err := nextShiftStart(tx, attendance.EmployeeID, attendance.Schedule.ScheduledEnd)
+			if err != nil {
+				return err
+			}
+			attendance.OvertimeMinutes, attendance.OvertimeReview = overtimeMinutes(attendance.Schedule.ScheduledEnd, now, nextStart)
+		}
+		return tx.Omit(clause.Associations).Save(&attendance).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findAttendance(attendance.ID)
+}
+
+// CloseAttendance lets a supervisor close an attendance the employee forgot to
+// clock out of. No overtime is recorded; the employee can clock in again.
+func (s *AttendanceService) CloseAttendance(id uint, req model.AttendanceCloseRequest, scope model.OutletScope, actorID string) (*model.Attendance, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		attendance, err := lockAttendance(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if attendance.ClockOutAt != nil {
+			return ErrAttendanceClosed
+		}
+		clockOut := *req.ClockOutAt
+		if clockOut.Before(attendance.ClockInAt) || clockOut.After(time.Now()) {
+			return ErrAttendanceClockOutTime
+		}
+
+		attendance.ClockOutAt = &clockOut
+		attendance.WorkedMinutes = int(clockOut.Sub(attendance.ClockInAt).Minutes())
+		if attendance.Schedule != nil {
+			attendance.EarlyLeaveMinutes = max(int(attendance.Schedule.ScheduledEnd.Sub(clockOut).Minutes()), 0)
+		}
+		attendance.OvertimeMinutes = 0
+		attendance.OvertimeReview = false
+		attendance.ClosedBy = actorID
+		return tx.Omit(clause.Associations).Save(attendance).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findAttendance(id)
+}
+
+// ReviewOvertime settles the overtime of a clock-out that was flagged for review.
+// The approved minutes cannot exceed the time actually worked after the shift end.
+func (s *AttendanceService) ReviewOvertime(id uint, req model.AttendanceOvertimeReviewRequest, scope model.OutletScope, actorID string) (*model.Attendance, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		attendance, err := lockAttendance(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if !attendance.OvertimeReview || attendance.ClockOutAt == nil || attendance.Schedule == nil {
+			return ErrAttendanceNoReview
+		}
+		if *req.OvertimeMinutes > int(attendance.ClockOutAt.Sub(attendance.Schedule.ScheduledEnd).Minutes()) {
+			return ErrAttendanceOvertimeTooLong
+		}
+
+		return tx.Model(&model.Attendance{}).Where("id = ?", attendance.ID).Updates(map[string]interface{}{
+			"overtime_minutes":   *req.OvertimeMinutes,
+			"overtime_review":    false,
+			"overtime_review_by": actorID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findAttendance(id)
+}
+
+// lockAttendance loads an attendance in scope with its shift and locks it until tx ends.
+func lockAttendance(tx *gorm.DB, id uint, scope model.OutletScope) (*model.Attendance, error) {
+	var attendance model.Attendance
+	err := scopeOutletColumn(tx.Clauses(clause.Locking{Strength: "UPDATE"}), "outlet_id", scope).
+		Preload("Schedule").
+		First(&attendance, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttendanceNotFound
+		}
+		return nil, err
+	}
+	return &attendance, nil
+}
+
+// nextShiftStart returns the start of the employee's first shift after the given
+// moment, or nil when none is scheduled.
+func nextShiftStart(tx *gorm.DB, employeeID uint, after time.Time) (*time.Time, error) {
+	var schedule model.ShiftSchedule
+	err := tx.Select("id", "scheduled_start").
+		Where("employee_id = ? AND scheduled_start >= ?", employeeID, after).
+		Order("scheduled_start ASC").
+		First(&schedule).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule.ScheduledStart, nil
+}
+
+// lockAttendanceEmployee finds the active employee of the user and locks the row
+// so double taps on the clock button are serialised.
+func lockAttendanceEmployee(tx *gorm.DB, userID string) (*model.Employee, error) {
+	var employee model.Employee
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&employee).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAttendanceNoEmployee
+		}
+		return nil, err
+	}
+	if !employee.Active {
+		return nil, ErrEmployeeInactive
+	}
+	return &employee, nil
+}
+
+// checkAttendanceGeofence returns the distance in meters between the device and
+// the outlet, or ErrAttendanceOutsideGeofence when it exceeds the radius.
+func checkAttendanceGeofence(tx *gorm.DB, outletID uint, lat, lng float64) (int, error) {
+	var outlet model.Outlet
+	if err := tx.Select("id", "latitude", "longitude").First(&outlet, outletID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrAttendanceQRInvalid
+		}
+		return 0, err
+	}
+	if outlet.Latitude == nil || outlet.Longitude == nil {
+		return 0, ErrAttendanceOutletLocation
+	}
+	distance := haversineKm(lat, lng, *outlet.Latitude, *outlet.Longitude) * 1000
+	if distance > config.ATTENDANCE_GEOFENCE_METERS {
+		return 0, fmt.Errorf("%w (%.0f m, max %.0f m)", ErrAttendanceOutsideGeofence, distance, config.ATTENDANCE_GEOFENCE_METERS)
+	}
+	return int(distance), nil
+}
+
+// lateMinutes counts the minutes after the shift start; arrivals within the
+// grace period are on time.
+func lateMinutes(start, clockIn time.Time) int {
+	late := int(clockIn.Sub(start).Minutes())
+	if late <= config.ATTENDANCE_LATE_GRACE_MINUTES {
+		return 0
+	}
+	return late
+}
+
+// overtimeMinutes counts the minutes worked after the shift end once they reach
+// ATTENDANCE_OVERTIME_MIN_MINUTES, capped at ATTENDANCE_OVERTIME_MAX_MINUTES and
+// at the start of the next shift. It reports true when the cap was hit, which
+// usually means a forgotten clock-out.
+func overtimeMinutes(end, clockOut time.Time, nextStart *time.Time) (int, bool) {
+	extra := int(clockOut.Sub(end).Minutes())
+	if extra < config.ATTENDANCE_OVERTIME_MIN_MINUTES {
+		return 0, false
+	}
+	limit := config.ATTENDANCE_OVERTIME_MAX_MINUTES
+	if nextStart != nil {
+		limit = min(limit, max(int(nextStart.Sub(end).Minutes()), 0))
+	}
+	if extra > limit {
+		return limit, true
+	}
+	return extra, false
+}
+
+func findAttendance(id uint) (*model.Attendance, error) {
+	var attendance model.Attendance
+	err := preloadEmployeeSummary(database.DbCore).Preload("Schedule").First(&attendance, id).Error
+	return &attendance, err
+}
+
+// preloadEmployeeSummary hanya memuat identitas karyawan; gaji dan rekening
+// tidak ikut ke response jadwal/absensi yang bisa dilihat semua staf.
+func preloadEmployeeSummary(db *gorm.DB) *gorm.DB {
+	return db.Preload("Employee", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "code", "name", "outlet_id", "position")
+	})
+}
+
+// ---------------- LIST & REPORT ----------------
+
+func (s *AttendanceService) GetAttendances(params model.AttendanceQuery, scope model.OutletScope) ([]model.Attendance, error) {
+	query, err := attendanceQuery(preloadEmployeeSummary(database.DbCore).Preload("Schedule"), params, scope)
+	if err != nil {
+		return nil, err
+	}
+	attendances := []model.Attendance{}
+	err = query.Order("clock_in_at ASC").Order("id ASC").Find(&attendances).Error
+	return attendances, err
+}
+
+// MyAttendance returns the user's own attendance and shifts in the period.
+func (s *AttendanceService) MyAttendance(userID string, params model.AttendanceQuery) ([]model.Attendance, []model.ShiftSchedule, error) {
+	var employee model.Employee
+	if err := database.DbCore.Where("user_id = ?", userID).First(&employee).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttendanceNoEmployee
+		}
+		return nil, nil, err
+	}
+	params.EmployeeID = employee.ID
+	params.OutletID = 0
+
+	attendances, err := s.GetAttendances(params, model.OutletScope{})
+	if err != nil {
+		return nil, nil, err
+	}
+	schedules, err := s.GetSchedules(model.ShiftScheduleQuery{EmployeeID: employee.ID, From: params.From, To: params.To}, model.OutletScope{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return attendances, schedules, nil
+}
+
+// GetReport summarises late arrivals, absences and overtime per employee. Shifts
+// count as absent once they have ended without a clock-in.
+func (s *AttendanceService) GetReport(params model.AttendanceQuery, scope model.OutletScope) (*model.AttendanceReport, error) {
+	from, to, err := attendancePeriod(params)
+	if err != nil {
+		return nil, err
+	}
+	schedules, err := s.GetSchedules(model.ShiftScheduleQuery{
+		OutletID:   params.OutletID,
+		EmployeeID: params.EmployeeID,
+		From:       params.From,
+		To:         params.To,
+	}, scope)
+	if err != nil {
+		return nil, err
+	}
+	attendances, err := s.GetAttendances(params, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	report := model.AttendanceReport{
+		From:         from,
+		To:           to,
+		Employees:    []model.AttendanceEmployeeSummary{},
+		Absences:     []model.ShiftSchedule{},
+		LateArrivals: []model.Attendance{},
+		Overtime:     []model.Attendance{},
+	}
+	summaries := map[uint]*model.AttendanceEmployeeSummary{}
+	summaryFor := func(employee *model.Employee, employeeID, outletID uint) *model.AttendanceEmployeeSummary {
+		summary, ok := summaries[employeeID]
+		if !ok {
+			summary = &model.AttendanceEmployeeSummary{EmployeeID: employeeID, OutletID: outletID}
+			if employee != nil {
+				summary.EmployeeCode = employee.Code
+				summary.EmployeeName = employee.Name
+				summary.OutletID = employee.OutletID
+			}
+			summaries[employeeID] = summary
+		}
+		return summary
+	}
+
+	now := time.Now()
+	attended := map[uint]bool{}
+	for _, attendance := range attendances {
+		summary := summaryFor(attendance.Employee, attendance.EmployeeID, attendance.OutletID)
+		if attendance.ScheduleID != nil {
+			attended[*attendance.ScheduleID] = true
+			summary.AttendedShifts++
+		} else {
+			summary.UnscheduledDays++
+		}
+		if attendance.LateMinutes > 0 {
+			summary.LateCount++
+			summary.LateMinutes += attendance.LateMinutes
+			report.LateArrivals = append(report.LateArrivals, attendance)
+		}
+		summary.EarlyLeaveMinutes += attendance.EarlyLeaveMinutes
+		summary.WorkedMinutes += attendance.WorkedMinutes
+		if attendance.OvertimeMinutes > 0 {
+			summary.OvertimeMinutes += attendance.OvertimeMinutes
+			report.Overtime = append(report.Overtime, attendance)
+		}
+		if missingClockOut(attendance, now) {
+			summary.MissingClockOuts++
+		}
+	}
+	for _, schedule := range schedules {
+		summary := summaryFor(schedule.Employee, schedule.EmployeeID, schedule.OutletID)
+		summary.ScheduledShifts++
+		if !attended[schedule.ID] && schedule.ScheduledEnd.Before(now) {
+			summary.Absences++
+			report.Absences = append(report.Absences, schedule)
+		}
+	}
+
+	for _, summary := range summaries {
+		report.Employees = append(report.Employees, *summary)
+	}
+	sort.Slice(report.Employees, func(i, j int) bool {
+		if report.Employees[i].EmployeeName != report.Employees[j].EmployeeName {
+			return report.Employees[i].EmployeeName < report.Employees[j].EmployeeName
+		}
+		return report.Employees[i].EmployeeID < report.Employees[j].EmployeeID
+	})
+	return &report, nil
+}
+
+// SyncOvertime copies the overtime of finished attendances in the period into the
+// payroll overtime of each employee (source attendance). Every attendance is
+// copied once; an overtime entry deleted afterwards is treated as rejected.
+// Overtime falling in a month whose payroll is already locked is moved to the
+// next open month so it still gets paid. Overtime waiting for review is skipped.
+func (s *AttendanceService) SyncOvertime(params model.AttendanceQuery, scope model.OutletScope, actorID string) (*model.AttendanceOvertimeSyncResult, error) {
+	result := model.AttendanceOvertimeSyncResult{}
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		query, err := attendanceQuery(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Schedule"), params, scope)
+		if err != nil {
+			return err
+		}
+		var attendances []model.Attendance
+		err = query.Where("overtime_minutes > 0 AND clock_out_at IS NOT NULL AND overtime_synced_at IS NULL").
+			Where("overtime_review = ?", false).
+			Order("clock_in_at ASC").
+			Find(&attendances).Error
+		if err != nil {
+			return err
+		}
+
+		employeeOutlets := map[uint]uint{}
+		now := time.Now()
+		for _, attendance := range attendances {
+			date := attendance.ClockInAt
+			if attendance.Schedule != nil {
+				date = attendance.Schedule.Date
+			}
+			date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+			note := "Absensi #" + strconv.FormatUint(uint64(attendance.ID), 10)
+
+			// Payroll yang sudah dikunci tidak dihitung ulang, jadi lembur di
+			// periode itu dipindah ke awal periode berikutnya yang masih terbuka
+			outletID, ok := employeeOutlets[attendance.EmployeeID]
+			if !ok {
+				var employee model.Employee
+				if err := tx.Select("id", "outlet_id").First(&employee, attendance.EmployeeID).Error; err != nil {
+					return err
+				}
+				outletID = employee.OutletID
+				employeeOutlets[attendance.EmployeeID] = outletID
+			}
+			payDate, err := openPayrollDate(tx, outletID, date)
+			if err != nil {
+				return err
+			}
+			if !payDate.Equal(date) {
+				note += ", lembur " + date.Format("2006-01-02") + " (periode payroll sudah dikunci)"
+				result.Deferred++
+			}
+
+			entry := model.EmployeeOvertime{
+				EmployeeID: attendance.EmployeeID,
+				Date:       payDate,
+				Minutes:    attendance.OvertimeMinutes,
+				Source:     model.OvertimeAttendance,
+				Note:       note,
+				CreatedBy:  actorID,
+			}
+			if err := tx.Create(&entry).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.Attendance{}).Where("id = ?", attendance.ID).Update("overtime_synced_at", now).Error; err != nil {
+				return err
+			}
+			result.Synced++
+			result.Minutes += attendance.OvertimeMinutes
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// attendanceQuery filters attendances by clock-in date (server time) and scope.
+func attendanceQuery(db *gorm.DB, params model.AttendanceQuery, scope model.OutletScope) (*gorm.DB, error) {
+	from, to, err := attendancePeriod(params)
+	if err != nil {
+		return nil, err
+	}
+	query := scopeOutletColumn(db, "outlet_id", scope).
+		Where("clock_in_at >= ? AND clock_in_at < ?", from, to.AddDate(0, 0, 1))
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.EmployeeID != 0 {
+		query = query.Where("employee_id = ?", params.EmployeeID)
+	}
+	return query, nil
+}
+
+func attendancePeriod(params model.AttendanceQuery) (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation("2006-01-02", params.From, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := time.ParseInLocation("2006-01-02", params.To, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// missingClockOut reports an attendance that is still open after its shift ended,
+// or a day after the clock-in when there was no shift.
+func missingClockOut(attendance model.Attendance, now time.Time) bool {
+	if attendance.ClockOutAt != nil {
+		return false
+	}
+	if attendance.Schedule != nil {
+		return attendance.Schedule.ScheduledEnd.Before(now)
+	}
+	return attendance.ClockInAt.Add(24 * time.Hour).Before(now)
+}
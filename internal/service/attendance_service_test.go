@@ -0,0 +1,39 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"BackendFramework/internal/config"
+)
+
+func TestOvertimeMinutes(t *testing.T) {
+	config.ATTENDANCE_OVERTIME_MIN_MINUTES = 30
+	config.ATTENDANCE_OVERTIME_MAX_MINUTES = 240
+
+	end := time.Date(2026, 6, 1, 17, 0, 0, 0, time.Local)
+	nextDay := end.Add(15 * time.Hour) // shift besok jam 08:00
+
+	tests := []struct {
+		name       string
+		clockOut   time.Time
+		nextStart  *time.Time
+		wantMinute int
+		wantReview bool
+	}{
+		{name: "below the minimum is no overtime", clockOut: end.Add(29 * time.Minute)},
+		{name: "regular overtime", clockOut: end.Add(90 * time.Minute), nextStart: &nextDay, wantMinute: 90},
+		{name: "exactly the maximum", clockOut: end.Add(240 * time.Minute), wantMinute: 240},
+		{name: "past the maximum is capped and flagged", clockOut: end.Add(5 * time.Hour), wantMinute: 240, wantReview: true},
+		{name: "forgotten clock-out the next day stops at the next shift", clockOut: nextDay.Add(2 * time.Hour), nextStart: func() *time.Time { t := end.Add(time.Hour); return &t }(), wantMinute: 60, wantReview: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minutes, review := overtimeMinutes(end, tt.clockOut, tt.nextStart)
+			if minutes != tt.wantMinute || review != tt.wantReview {
+				t.Errorf("overtimeMinutes = (%d, %v), want (%d, %v)", minutes, review, tt.wantMinute, tt.wantReview)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+// Field yang selalu berubah dan tidak perlu dicatat di riwayat
+var auditIgnoredFields = map[string]bool{
+	"createdAt": true,
+	"updatedAt": true,
+}
+
+type AuditService struct{}
+
+func NewAuditService() *AuditService {
+	return &AuditService{}
+}
+
+// GetHistory returns the change history of one entity, newest first.
+func (s *AuditService) GetHistory(entityType, entityID string) ([]model.AuditLog, error) {
+	logs := []model.AuditLog{}
+	err := database.DbCore.
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at DESC, id DESC").
+		Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// RecordAudit stores the field-level diff between before and after. Pass nil as
+// before for a create and nil as after for a delete. It should be called with the
+// same transaction that applied the change so the history never drifts from the data.
+func RecordAudit(tx *gorm.DB, entityType, entityID, action, actorID string, before, after interface{}, extra ...model.AuditChange) error {
+	changes, err := diffAuditFields(before, after)
+	if err != nil {
+		return err
+	}
+	changes = append(changes, extra...)
+
+	// Update tanpa perubahan apa pun tidak perlu dicatat
+	if action == model.AuditActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	return tx.Create(&model.AuditLog{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ActorID:    actorID,
+		Changes:    changes,
+	}).Error
+}
+
+// diffAuditFields compares the JSON representation of two values, so fields
+// hidden from the API (json:"-", e.g. passwords) never end up in the audit trail.
+func diffAuditFields(before, after interface{}) ([]model.AuditChange, error) {
+	beforeMap, err := toAuditMap(before)
+	if err != nil {
+		return nil, err
+	}
+	afterMap, err := toAuditMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]bool{}
+	for field := range beforeMap {
+		fields[field] = true
+	}
+	for field := range afterMap {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		if !auditIgnoredFields[field] {
+			names = append(names, field)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []model.AuditChange{}
+	for _, field := range names {
+		oldValue, newValue := beforeMap[field], afterMap[field]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		changes = append(changes, model.AuditChange{Field: field, Before: oldValue, After: newValue})
+	}
+	return changes, nil
+}
+
+func toAuditMap(value interface{}) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return result, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
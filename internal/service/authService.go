@@ -63,7 +63,7 @@ func GetTokenData(whereParam bson.M) (*model.TokenData) {
 func TestPing() {
 	// Send a ping to confirm a successful connection
 	var result bson.M
-	if err := database.DbAuth.RunCommand(context.TODO(), bson.D{{"ping", 1}}).Decode(&result); err != nil {
+	if err := database.DbAuth.RunCommand(context.TODO(), bson.D{{Key: "ping", Value: 1}}).Decode(&result); err != nil {
 		panic(err)
 	}
 	fmt.Println("Pinged your deployment. You successfully connected to MongoDB!")
@@ -0,0 +1,641 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var (
+	ErrCashShiftNotFound        = errors.New("cash shift not found")
+	ErrCashShiftAlreadyOpen     = errors.New("cashier already has an open shift")
+	ErrCashShiftNotOpen         = errors.New("no open cash shift; open a shift with a float first")
+	ErrCashShiftClosed          = errors.New("cash shift is already closed")
+	ErrCashShiftNotClosed       = errors.New("cash shift must be closed and counted before sign-off")
+	ErrCashShiftSelfSignOff     = errors.New("a shift cannot be signed off by its own cashier")
+	ErrCashShiftNoteRequired    = errors.New("a note is required to sign off a flagged discrepancy")
+	ErrDailyClosingExists       = errors.New("business day is already closed for this outlet")
+	ErrDailyClosingUnreconciled = errors.New("every cash shift of the day must be reconciled before closing")
+	ErrDailyClosingFuture       = errors.New("business day has not started yet")
+)
+
+const (
+	cashShiftReportPath      = "./web/html/cash_shift_report.html"
+	defaultCashShiftPageSize = 20
+)
+
+type CashShiftService struct{}
+
+func NewCashShiftService() *CashShiftService {
+	return &CashShiftService{}
+}
+
+func (s *CashShiftService) GetShifts(params model.CashShiftListQuery, scope model.OutletScope) (*model.CashShiftListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.CashShift{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.CashierID != "" {
+		query = query.Where("cashier_id = ?", params.CashierID)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if params.Flagged != nil {
+		query = query.Where("discrepancy_flagged = ?", *params.Flagged)
+	}
+	if params.From != "" {
+		query = query.Where("business_date >= ?", params.From)
+	}
+	if params.To != "" {
+		query = query.Where("business_date <= ?", params.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultCashShiftPageSize
+	}
+
+	shifts := []model.CashShift{}
+	err := query.Order("opened_at DESC").Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&shifts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CashShiftListResult{
+		Shifts: shifts,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+// GetShift returns the shift with its count and cash movements. The figures of an
+// open shift are worked out live, so the cashier can see the expected cash so far.
+func (s *CashShiftService) GetShift(id uint, scope model.OutletScope) (*model.CashShift, error) {
+	shift, err := findCashShift(database.DbCore.Preload("Denominations", func(db *gorm.DB) *gorm.DB {
+		return db.Order("value DESC")
+	}).Preload("Movements", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}), id, scope)
+	if err != nil {
+		return nil, err
+	}
+	if shift.Status == model.CashShiftOpen {
+		if err := computeCashShiftTotals(database.DbCore, shift); err != nil {
+			return nil, err
+		}
+	}
+	return shift, nil
+}
+
+// CurrentShift returns the cashier's open shift, or ErrCashShiftNotOpen.
+func (s *CashShiftService) CurrentShift(cashierID string, scope model.OutletScope) (*model.CashShift, error) {
+	var shift model.CashShift
+	err := database.DbCore.Select("id").
+		Where("cashier_id = ? AND status = ?", cashierID, model.CashShiftOpen).
+		First(&shift).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCashShiftNotOpen
+		}
+		return nil, err
+	}
+	return s.GetShift(shift.ID, scope)
+}
+
+// OpenShift starts a cashier's drawer at the outlet with the opening float. A
+// cashier has at most one open shift, and no shift can be opened on a business
+// day that is already closed.
+func (s *CashShiftService) OpenShift(req model.CashShiftOpenRequest, scope model.OutletScope, cashierID string) (*model.CashShift, error) {
+	var shift model.CashShift
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := checkOutletInScope(tx, req.OutletID, scope); err != nil {
+			return err
+		}
+		// Baris outlet dikunci supaya buka shift dan tutup hari tidak berjalan bersamaan
+		var outlet model.Outlet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "time_zone").First(&outlet, req.OutletID).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		local := now.In(outlet.Location())
+		businessDate := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+
+		// Counter nomor shift per tahun dikunci sampai commit, sekaligus
+		// menyerialkan pengecekan shift terbuka di bawah ini
+		seq, err := nextDocumentNumber(tx, fmt.Sprintf("cash-shift:%d", businessDate.Year()))
+		if err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&model.CashShift{}).Where("cashier_id = ? AND status = ?", cashierID, model.CashShiftOpen).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCashShiftAlreadyOpen
+		}
+		closed, err := dailyClosingExists(tx, req.OutletID, businessDate, "UPDATE")
+		if err != nil {
+			return err
+		}
+		if closed {
+			return ErrDailyClosingExists
+		}
+
+		shift = model.CashShift{
+			Number:       fmt.Sprintf("SH-%d-%05d", businessDate.Year(), seq),
+			OutletID:     req.OutletID,
+			CashierID:    cashierID,
+			BusinessDate: businessDate,
+			Status:       model.CashShiftOpen,
+			OpeningFloat: req.OpeningFloat,
+			OpeningNote:  req.Note,
+			OpenedAt:     now,
+			ExpectedCash: req.OpeningFloat,
+		}
+		return tx.Create(&shift).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetShift(shift.ID, scope)
+}
+
+// AddMovement records cash put into or taken out of the drawer outside of sales.
+func (s *CashShiftService) AddMovement(id uint, req model.CashShiftMovementRequest, scope model.OutletScope, actorID string) (*model.CashShift, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		shift, err := lockOpenCashShift(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&model.CashShiftMovement{
+			ShiftID:   shift.ID,
+			Type:      req.Type,
+			Amount:    req.Amount,
+			Reason:    req.Reason,
+			CreatedBy: actorID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetShift(id, scope)
+}
+
+// CloseShift stores the counted cash per denomination and compares it with the
+// expected cash: float + cash sales - cash refunds + pay-ins - payouts. A
+// difference above CASH_SHIFT_DISCREPANCY_TOLERANCE is flagged for the manager.
+func (s *CashShiftService) CloseShift(id uint, req model.CashShiftCloseRequest, scope model.OutletScope) (*model.CashShift, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		shift, err := lockOpenCashShift(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if err := computeCashShiftTotals(tx, shift); err != nil {
+			return err
+		}
+
+		counts := map[int64]int{}
+		for _, input := range req.Denominations {
+			counts[input.Value] += input.Count
+		}
+		denominations := []model.CashShiftDenomination{}
+		shift.CountedCash = 0
+		for value, count := range counts {
+			if count == 0 {
+				continue
+			}
+			denominations = append(denominations, model.CashShiftDenomination{
+				ShiftID: shift.ID,
+				Value:   value,
+				Count:   count,
+				Amount:  value * int64(count),
+			})
+			shift.CountedCash += value * int64(count)
+		}
+		sort.Slice(denominations, func(i, j int) bool { return denominations[i].Value > denominations[j].Value })
+		if len(denominations) > 0 {
+			if err := tx.Create(&denominations).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		shift.Discrepancy = shift.CountedCash - shift.ExpectedCash
+		return tx.Model(shift).Updates(map[string]interface{}{
+			"status":              model.CashShiftClosed,
+			"closed_at":           now,
+			"closing_note":        req.Note,
+			"cash_sales":          shift.CashSales,
+			"cash_refunds":        shift.CashRefunds,
+			"pay_ins":             shift.PayIns,
+			"payouts":             shift.Payouts,
+			"expected_cash":       shift.ExpectedCash,
+			"counted_cash":        shift.CountedCash,
+			"discrepancy":         shift.Discrepancy,
+			"discrepancy_flagged": discrepancyFlagged(shift.Discrepancy),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetShift(id, scope)
+}
+
+// SignOff is the manager's approval of a counted shift. Another user than the
+// cashier must sign off, and a flagged discrepancy needs an explanation.
+func (s *CashShiftService) SignOff(id uint, req model.CashShiftSignOffRequest, scope model.OutletScope, reviewerID string) (*model.CashShift, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		shift, err := findCashShift(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, scope)
+		if err != nil {
+			return err
+		}
+		if shift.Status != model.CashShiftClosed {
+			return ErrCashShiftNotClosed
+		}
+		if shift.CashierID == reviewerID {
+			return ErrCashShiftSelfSignOff
+		}
+		if shift.DiscrepancyFlagged && req.Note == "" {
+			return ErrCashShiftNoteRequired
+		}
+		return tx.Model(shift).Updates(map[string]interface{}{
+			"status":        model.CashShiftReconciled,
+			"signed_off_by": reviewerID,
+			"signed_off_at": time.Now(),
+			"sign_off_note": req.Note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetShift(id, scope)
+}
+
+// ReportPDF renders the shift report: sales per tender, the cash reconciliation
+// and the denomination count.
+func (s *CashShiftService) ReportPDF(id uint, scope model.OutletScope) ([]byte, *model.CashShift, error) {
+	shift, err := s.GetShift(id, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := model.CashShiftReport{
+		CompanyName:  config.INVOICE_COMPANY_NAME,
+		Shift:        *shift,
+		TenderTotals: []model.CashShiftTenderTotal{},
+		Tolerance:    config.CASH_SHIFT_DISCREPANCY_TOLERANCE,
+		PrintedAt:    time.Now(),
+	}
+	var outlet model.Outlet
+	if err := database.DbCore.Select("id", "name").First(&outlet, shift.OutletID).Error; err == nil {
+		report.OutletName = outlet.Name
+	}
+
+	var sales struct {
+		Count  int
+		Total  int64
+		Change int64
+	}
+	err = database.DbCore.Model(&model.Sale{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(total), 0) AS total, COALESCE(SUM(`change`), 0) AS `change`").
+		Where("cash_shift_id = ?", shift.ID).
+		Scan(&sales).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	report.SaleCount = sales.Count
+	report.GrossSales = sales.Total
+
+	err = database.DbCore.Model(&model.SaleTender{}).
+		Joins("JOIN sales ON sales.id = sale_tenders.sale_id").
+		Where("sales.cash_shift_id = ?", shift.ID).
+		Select("sale_tenders.method AS method, COUNT(*) AS count, SUM(sale_tenders.amount) AS amount").
+		Group("sale_tenders.method").
+		Order("sale_tenders.method ASC").
+		Scan(&report.TenderTotals).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	// Kembalian diambil dari tunai, jadi tunai ditampilkan bersih
+	for i := range report.TenderTotals {
+		if report.TenderTotals[i].Method == model.TenderCash {
+			report.TenderTotals[i].Amount -= sales.Change
+		}
+	}
+
+	tmpl, err := template.New("cash_shift_report.html").Funcs(template.FuncMap{
+		"rupiah": formatRupiah,
+	}).ParseFiles(cashShiftReportPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	html := new(bytes.Buffer)
+	if err := tmpl.Execute(html, report); err != nil {
+		return nil, nil, err
+	}
+	pdf, err := thirdparty.GeneratePdfBytes(html.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return pdf, shift, nil
+}
+
+// ---------------- DAILY CLOSING ----------------
+
+func (s *CashShiftService) GetDailyClosings(params model.DailyClosingQuery, scope model.OutletScope) ([]model.DailyClosing, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.DailyClosing{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.From != "" {
+		query = query.Where("business_date >= ?", params.From)
+	}
+	if params.To != "" {
+		query = query.Where("business_date <= ?", params.To)
+	}
+	closings := []model.DailyClosing{}
+	err := query.Order("business_date DESC").Order("outlet_id ASC").Find(&closings).Error
+	return closings, err
+}
+
+// CloseDay closes the outlet's business day. It is refused for a day that has not
+// started yet and while any shift that ran during the day, including one opened
+// the evening before, is still open or waiting for sign-off.
+func (s *CashShiftService) CloseDay(req model.DailyClosingRequest, scope model.OutletScope, actorID string) (*model.DailyClosing, error) {
+	date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	var closing model.DailyClosing
+	err = database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := checkOutletInScope(tx, req.OutletID, scope); err != nil {
+			return err
+		}
+		// Baris outlet dikunci supaya buka shift dan tutup hari tidak berjalan bersamaan
+		var outlet model.Outlet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "time_zone").First(&outlet, req.OutletID).Error; err != nil {
+			return err
+		}
+		loc := outlet.Location()
+		local := time.Now().In(loc)
+		if date.After(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)) {
+			return ErrDailyClosingFuture
+		}
+		closed, err := dailyClosingExists(tx, req.OutletID, date, "UPDATE")
+		if err != nil {
+			return err
+		}
+		if closed {
+			return ErrDailyClosingExists
+		}
+
+		// Semua shift yang berjalan di hari itu (jam outlet) harus sudah direkonsiliasi
+		dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+		var unreconciled int64
+		err = tx.Model(&model.CashShift{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("outlet_id = ? AND status <> ?", req.OutletID, model.CashShiftReconciled).
+			Where("opened_at < ? AND (closed_at IS NULL OR closed_at >= ?)", dayStart.AddDate(0, 0, 1), dayStart).
+			Count(&unreconciled).Error
+		if err != nil {
+			return err
+		}
+		if unreconciled > 0 {
+			return fmt.Errorf("%w (%d pending)", ErrDailyClosingUnreconciled, unreconciled)
+		}
+
+		// Rekap kas hanya dari shift yang dibuka di tanggal itu, supaya shift lintas
+		// hari tidak terhitung dua kali
+		var shifts []model.CashShift
+		err = tx.Where("outlet_id = ? AND business_date = ?", req.OutletID, req.Date).Find(&shifts).Error
+		if err != nil {
+			return err
+		}
+		closing = model.DailyClosing{
+			OutletID:     req.OutletID,
+			BusinessDate: date,
+			Note:         req.Note,
+			ClosedBy:     actorID,
+			ClosedAt:     time.Now(),
+		}
+		for _, shift := range shifts {
+			closing.ShiftCount++
+			closing.ExpectedCash += shift.ExpectedCash
+			closing.CountedCash += shift.CountedCash
+			closing.Discrepancy += shift.Discrepancy
+		}
+		return tx.Create(&closing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &closing, nil
+}
+
+// ---------------- HELPERS ----------------
+
+// attachSaleCashShift links a new sale to the cashier's open shift at the outlet.
+// A sale paid (partly) in cash cannot be posted without an open shift, since the
+// cash would not belong to any drawer. The shift row is share-locked so it cannot
+// be closed before this sale commits.
+func attachSaleCashShift(tx *gorm.DB, sale *model.Sale) error {
+	var shift model.CashShift
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").
+		Where("outlet_id = ? AND cashier_id = ? AND status = ?", sale.OutletID, sale.CashierID, model.CashShiftOpen).
+		First(&shift).Error
+	if err == nil {
+		sale.CashShiftID = &shift.ID
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	for _, tender := range sale.Tenders {
+		if tender.Method == model.TenderCash {
+			return ErrCashShiftNotOpen
+		}
+	}
+	return nil
+}
+
+// attachRefundCashShift books the cash refund of an approved void/return on the
+// open shift of the cashier who requested it at the outlet, or else on the
+// outlet's latest open shift.
+func attachRefundCashShift(tx *gorm.DB, reversal *model.SaleReversal, sale *model.Sale) error {
+	var cash int64
+	for _, refund := range reversal.Refunds {
+		if refund.Method == model.TenderCash {
+			cash += refund.Amount
+		}
+	}
+	if cash == 0 {
+		return nil
+	}
+
+	var shift model.CashShift
+	open := func() *gorm.DB {
+		return tx.Clauses(clause.Locking{Strength: "SHARE"}).Select("id").
+			Where("outlet_id = ? AND status = ?", sale.OutletID, model.CashShiftOpen)
+	}
+	err := open().Where("cashier_id = ?", reversal.RequestedBy).First(&shift).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = open().Order("opened_at DESC").First(&shift).Error
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCashShiftNotOpen
+		}
+		return err
+	}
+	reversal.CashShiftID = &shift.ID
+	return tx.Model(reversal).Update("cash_shift_id", shift.ID).Error
+}
+
+// computeCashShiftTotals works out the cash that should be in the drawer from the
+// sales, refunds and movements booked on the shift.
+func computeCashShiftTotals(db *gorm.DB, shift *model.CashShift) error {
+	var tendered int64
+	err := db.Model(&model.SaleTender{}).
+		Joins("JOIN sales ON sales.id = sale_tenders.sale_id").
+		Where("sales.cash_shift_id = ? AND sale_tenders.method = ?", shift.ID, model.TenderCash).
+		Select("COALESCE(SUM(sale_tenders.amount), 0)").
+		Scan(&tendered).Error
+	if err != nil {
+		return err
+	}
+	var change int64
+	err = db.Model(&model.Sale{}).
+		Where("cash_shift_id = ?", shift.ID).
+		Select("COALESCE(SUM(`change`), 0)").
+		Scan(&change).Error
+	if err != nil {
+		return err
+	}
+	err = db.Model(&model.SaleRefund{}).
+		Joins("JOIN sale_reversals ON sale_reversals.id = sale_refunds.reversal_id").
+		Where("sale_reversals.cash_shift_id = ? AND sale_reversals.status = ? AND sale_refunds.method = ?",
+			shift.ID, model.SaleReversalApproved, model.TenderCash).
+		Select("COALESCE(SUM(sale_refunds.amount), 0)").
+		Scan(&shift.CashRefunds).Error
+	if err != nil {
+		return err
+	}
+
+	var movements []struct {
+		Type   string
+		Amount int64
+	}
+	err = db.Model(&model.CashShiftMovement{}).
+		Where("shift_id = ?", shift.ID).
+		Select("type, SUM(amount) AS amount").
+		Group("type").
+		Scan(&movements).Error
+	if err != nil {
+		return err
+	}
+	shift.PayIns, shift.Payouts = 0, 0
+	for _, movement := range movements {
+		switch movement.Type {
+		case model.CashMovementPayIn:
+			shift.PayIns = movement.Amount
+		case model.CashMovementPayout:
+			shift.Payouts = movement.Amount
+		}
+	}
+
+	shift.CashSales = tendered - change
+	shift.ExpectedCash = shift.OpeningFloat + shift.CashSales - shift.CashRefunds + shift.PayIns - shift.Payouts
+	return nil
+}
+
+func discrepancyFlagged(discrepancy int64) bool {
+	if discrepancy < 0 {
+		discrepancy = -discrepancy
+	}
+	return discrepancy > config.CASH_SHIFT_DISCREPANCY_TOLERANCE
+}
+
+// dailyClosingExists reads the closing row with the given lock, so a closing
+// cannot be inserted for that outlet and date until the caller commits.
+func dailyClosingExists(tx *gorm.DB, outletID uint, date time.Time, strength string) (bool, error) {
+	var count int64
+	err := tx.Model(&model.DailyClosing{}).Clauses(clause.Locking{Strength: strength}).
+		Where("outlet_id = ? AND business_date = ?", outletID, date.Format("2006-01-02")).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// outletBusinessDate returns the calendar date of at in the outlet's time zone.
+func outletBusinessDate(tx *gorm.DB, outletID uint, at time.Time) (time.Time, error) {
+	var outlet model.Outlet
+	if err := tx.Select("id", "time_zone").First(&outlet, outletID).Error; err != nil {
+		return time.Time{}, err
+	}
+	local := at.In(outlet.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local), nil
+}
+
+// checkBusinessDayOpen refuses postings (sales, voids, returns) on a business
+// day that is already closed for the outlet.
+func checkBusinessDayOpen(tx *gorm.DB, outletID uint, at time.Time) error {
+	date, err := outletBusinessDate(tx, outletID, at)
+	if err != nil {
+		return err
+	}
+	closed, err := dailyClosingExists(tx, outletID, date, "SHARE")
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrDailyClosingExists
+	}
+	return nil
+}
+
+func findCashShift(db *gorm.DB, id uint, scope model.OutletScope) (*model.CashShift, error) {
+	var shift model.CashShift
+	if err := scopeOutletColumn(db, "outlet_id", scope).First(&shift, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCashShiftNotFound
+		}
+		return nil, err
+	}
+	return &shift, nil
+}
+
+func lockOpenCashShift(tx *gorm.DB, id uint, scope model.OutletScope) (*model.CashShift, error) {
+	shift, err := findCashShift(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, scope)
+	if err != nil {
+		return nil, err
+	}
+	if shift.Status != model.CashShiftOpen {
+		return nil, ErrCashShiftClosed
+	}
+	return shift, nil
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrCustomerNotFound  = errors.New("customer not found")
+	ErrCustomerDuplicate = errors.New("a customer with this phone or email already exists")
+	ErrCustomerMergeSelf = errors.New("a customer cannot be merged into itself")
+)
+
+const defaultCustomerPageSize = 20
+
+type CustomerService struct {
+	saleService *SaleService
+}
+
+func NewCustomerService() *CustomerService {
+	return &CustomerService{
+		saleService: NewSaleService(),
+	}
+}
+
+// CreateCustomer registers a customer. When the phone or email already belongs to
+// someone, the existing customer is returned together with ErrCustomerDuplicate
+// so the cashier can pick that record instead.
+func (s *CustomerService) CreateCustomer(req model.CustomerRequest, actorID string) (*model.Customer, error) {
+	customer := model.Customer{CreatedBy: actorID}
+	if err := applyCustomerRequest(&customer, req); err != nil {
+		return nil, err
+	}
+
+	duplicate, err := findDuplicateCustomer(database.DbCore, 0, customer.Phone, customer.Email)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate != nil {
+		return duplicate, ErrCustomerDuplicate
+	}
+
+	if err := database.DbCore.Create(&customer).Error; err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (s *CustomerService) UpdateCustomer(id uint, req model.CustomerRequest) (*model.Customer, error) {
+	customer, err := findCustomer(database.DbCore, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCustomerRequest(customer, req); err != nil {
+		return nil, err
+	}
+
+	duplicate, err := findDuplicateCustomer(database.DbCore, customer.ID, customer.Phone, customer.Email)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate != nil {
+		return duplicate, ErrCustomerDuplicate
+	}
+
+	if err := database.DbCore.Save(customer).Error; err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// DeleteCustomer soft deletes the customer and frees its phone and email for a
+// new registration. Past sales keep pointing to the deleted record.
+func (s *CustomerService) DeleteCustomer(id uint) error {
+	customer, err := findCustomer(database.DbCore, id)
+	if err != nil {
+		return err
+	}
+	return database.DbCore.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Customer{}).Where("id = ?", customer.ID).
+			Updates(map[string]interface{}{"phone": nil, "email": nil}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(customer).Error
+	})
+}
+
+func (s *CustomerService) GetCustomers(params model.CustomerListQuery) (*model.CustomerListResult, error) {
+	query := database.DbCore.Model(&model.Customer{})
+	if search := strings.TrimSpace(params.Search); search != "" {
+		like := "%" + strings.ToLower(search) + "%"
+		if phone := normalizePhone(search); phone != "" {
+			query = query.Where("name LIKE ? OR email LIKE ? OR phone LIKE ?", like, like, "%"+phone+"%")
+		} else {
+			query = query.Where("name LIKE ? OR email LIKE ?", like, like)
+		}
+	}
+	if params.Tag != "" {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", params.Tag)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultCustomerPageSize
+	}
+
+	customers := []model.Customer{}
+	err := query.Order("name ASC").Order("id ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&customers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CustomerListResult{
+		Customers: customers,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+// GetCustomer returns the customer with a summary of their purchases. Voided sales
+// are left out and approved returns are taken off the amount spent.
+func (s *CustomerService) GetCustomer(id uint) (*model.CustomerDetail, error) {
+	customer, err := findCustomer(database.DbCore, id)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := model.CustomerDetail{Customer: *customer}
+	var sales struct {
+		Transactions  int64
+		Total         int64
+		FirstPurchase *time.Time
+		LastPurchase  *time.Time
+	}
+	err = database.DbCore.Model(&model.Sale{}).
+		Select("COUNT(*) AS transactions, COALESCE(SUM(total), 0) AS total, MIN(sold_at) AS first_purchase, MAX(sold_at) AS last_purchase").
+		Where("customer_id = ? AND status <> ?", id, model.SaleStatusVoided).
+		Scan(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+
+	refunded, err := customerReturnRefunds(database.DbCore, id, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	detail.Purchases = model.CustomerPurchaseSummary{
+		Transactions:  sales.Transactions,
+		TotalSpent:    sales.Total - refunded,
+		FirstPurchase: sales.FirstPurchase,
+		LastPurchase:  sales.LastPurchase,
+	}
+	return &detail, nil
+}
+
+// GetCustomerSales lists the customer's purchase history within the caller's outlets.
+func (s *CustomerService) GetCustomerSales(id uint, params model.CustomerSaleQuery, scope model.OutletScope) (*model.SaleListResult, error) {
+	if _, err := findCustomer(database.DbCore, id); err != nil {
+		return nil, err
+	}
+	return s.saleService.GetSales(model.SaleListQuery{
+		CustomerID: id,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+	}, scope)
+}
+
+// MergeCustomers folds a duplicate record into the target: its sales move to the
+// target, empty contact fields on the target are filled from the source, tags are
+// combined, notes appended and loyalty points carried over. The source is soft
+// deleted with MergedIntoID set.
+func (s *CustomerService) MergeCustomers(targetID, sourceID uint) (*model.Customer, error) {
+	if targetID == sourceID {
+		return nil, ErrCustomerMergeSelf
+	}
+
+	var target model.Customer
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var customers []model.Customer
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []uint{targetID, sourceID}).
+			Find(&customers).Error
+		if err != nil {
+			return err
+		}
+		if len(customers) != 2 {
+			return ErrCustomerNotFound
+		}
+		source := customers[0]
+		target = customers[1]
+		if target.ID != targetID {
+			source, target = target, source
+		}
+
+		if err := tx.Model(&model.Sale{}).Where("customer_id = ?", source.ID).Update("customer_id", target.ID).Error; err != nil {
+			return err
+		}
+		// Ledger poin ikut pindah; tier dievaluasi ulang pada transaksi atau maintenance berikutnya
+		if err := tx.Model(&model.LoyaltyTransaction{}).Where("customer_id = ?", source.ID).Update("customer_id", target.ID).Error; err != nil {
+			return err
+		}
+		target.Points += source.Points
+		// Piutang dan pembayarannya juga menjadi milik pelanggan tujuan
+		if err := tx.Model(&model.Receivable{}).Where("customer_id = ?", source.ID).Update("customer_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.ReceivablePayment{}).Where("customer_id = ?", source.ID).Update("customer_id", target.ID).Error; err != nil {
+			return err
+		}
+
+		if target.Phone == nil {
+			target.Phone = source.Phone
+		}
+		if target.Email == nil {
+			target.Email = source.Email
+		}
+		if target.Birthday == nil {
+			target.Birthday = source.Birthday
+		}
+		target.Tags = mergeTags(target.Tags, source.Tags)
+		if source.Notes != "" {
+			if target.Notes != "" {
+				target.Notes += "\n"
+			}
+			target.Notes += source.Notes
+		}
+
+		// Kontak sumber dilepas dulu supaya unique index tidak bentrok
+		err = tx.Model(&model.Customer{}).Where("id = ?", source.ID).Updates(map[string]interface{}{
+			"phone":          nil,
+			"email":          nil,
+			"merged_into_id": target.ID,
+			"points":         0,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.Customer{}, source.ID).Error; err != nil {
+			return err
+		}
+		return tx.Save(&target).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &target, nil
+}
+
+func findCustomer(db *gorm.DB, id uint) (*model.Customer, error) {
+	var customer model.Customer
+	if err := db.First(&customer, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// findDuplicateCustomer returns another customer that already uses the phone or
+// email, or nil when both are free.
+func findDuplicateCustomer(db *gorm.DB, excludeID uint, phone, email *string) (*model.Customer, error) {
+	if phone == nil && email == nil {
+		return nil, nil
+	}
+
+	query := db.Where("id <> ?", excludeID)
+	switch {
+	case phone != nil && email != nil:
+		query = query.Where("phone = ? OR email = ?", *phone, *email)
+	case phone != nil:
+		query = query.Where("phone = ?", *phone)
+	default:
+		query = query.Where("email = ?", *email)
+	}
+
+	var customer model.Customer
+	if err := query.First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func applyCustomerRequest(customer *model.Customer, req model.CustomerRequest) error {
+	customer.Name = strings.TrimSpace(req.Name)
+	customer.Phone = nil
+	if phone := normalizePhone(req.Phone); phone != "" {
+		customer.Phone = &phone
+	}
+	customer.Email = nil
+	if email := strings.ToLower(strings.TrimSpace(req.Email)); email != "" {
+		customer.Email = &email
+	}
+	customer.Birthday = nil
+	if req.Birthday != "" {
+		birthday, err := time.Parse("2006-01-02", req.Birthday)
+		if err != nil {
+			return err
+		}
+		customer.Birthday = &birthday
+	}
+	customer.Tags = mergeTags(nil, req.Tags)
+	customer.Notes = req.Notes
+	customer.CreditLimit = req.CreditLimit
+	customer.PaymentTermDays = req.PaymentTermDays
+	return nil
+}
+
+// normalizePhone keeps only the digits and writes Indonesian numbers as 62xxx,
+// so "0812-3456 7890", "+62 812 3456 7890" and "8123456 7890" are one number.
+func normalizePhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	normalized := digits.String()
+	switch {
+	case strings.HasPrefix(normalized, "0"):
+		normalized = "62" + strings.TrimLeft(normalized, "0")
+	case strings.HasPrefix(normalized, "8"):
+		normalized = "62" + normalized
+	}
+	return normalized
+}
+
+// mergeTags combines tag lists without duplicates, ignoring case and blanks.
+func mergeTags(base, extra []string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, tag := range append(append([]string{}, base...), extra...) {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
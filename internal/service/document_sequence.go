@@ -0,0 +1,33 @@
+package service
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/model"
+)
+
+// nextDocumentNumber increments the named counter and returns the new value. The
+// row stays locked until tx commits, so concurrent documents never share or skip
+// a number; a rolled-back tx gives its number back.
+func nextDocumentNumber(tx *gorm.DB, name string) (uint, error) {
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.DocumentSequence{Name: name}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var sequence model.DocumentSequence
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("name = ?", name).
+		First(&sequence).Error
+	if err != nil {
+		return 0, err
+	}
+
+	sequence.LastValue++
+	if err := tx.Model(&sequence).Update("last_value", sequence.LastValue).Error; err != nil {
+		return 0, err
+	}
+	return sequence.LastValue, nil
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrEmployeeNotFound        = errors.New("employee not found")
+	ErrEmployeeCodeExists      = errors.New("employee code is already used")
+	ErrEmployeeUserLinked      = errors.New("user is already linked to another employee")
+	ErrEmployeeInactive        = errors.New("employee is not active")
+	ErrEmployeeEndDate         = errors.New("endDate is before joinDate")
+	ErrEmployeeOvertimeMissing = errors.New("overtime entry not found")
+	ErrEmployeeOvertimePaid    = errors.New("overtime is already paid in a locked payroll")
+)
+
+const defaultEmployeePageSize = 20
+
+type EmployeeService struct{}
+
+func NewEmployeeService() *EmployeeService {
+	return &EmployeeService{}
+}
+
+func (s *EmployeeService) GetEmployees(params model.EmployeeListQuery, scope model.OutletScope) (*model.EmployeeListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.Employee{}), "outlet_id", scope)
+	if params.Search != "" {
+		search := "%" + params.Search + "%"
+		query = query.Where("code LIKE ? OR name LIKE ?", search, search)
+	}
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.Active != nil {
+		query = query.Where("active = ?", *params.Active)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultEmployeePageSize
+	}
+
+	employees := []model.Employee{}
+	err := query.Order("name ASC").Order("id ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&employees).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.EmployeeListResult{
+		Employees: employees,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *EmployeeService) GetEmployee(id uint, scope model.OutletScope) (*model.Employee, error) {
+	return findEmployee(database.DbCore.Preload("Components"), id, scope)
+}
+
+func (s *EmployeeService) CreateEmployee(req model.EmployeeRequest, scope model.OutletScope) (*model.Employee, error) {
+	var employee model.Employee
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := applyEmployeeRequest(tx, &employee, req, scope); err != nil {
+			return err
+		}
+		return tx.Create(&employee).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetEmployee(employee.ID, scope)
+}
+
+func (s *EmployeeService) UpdateEmployee(id uint, req model.EmployeeRequest, scope model.OutletScope) (*model.Employee, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		employee, err := findEmployee(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, scope)
+		if err != nil {
+			return err
+		}
+		if err := applyEmployeeRequest(tx, employee, req, scope); err != nil {
+			return err
+		}
+		return tx.Save(employee).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetEmployee(id, scope)
+}
+
+// SetComponents replaces the employee's fixed monthly allowances and deductions.
+// Locked payrolls keep their own copy and are not affected.
+func (s *EmployeeService) SetComponents(id uint, req model.EmployeeComponentsRequest, scope model.OutletScope) (*model.Employee, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		employee, err := findEmployee(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, scope)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("employee_id = ?", employee.ID).Delete(&model.EmployeeComponent{}).Error; err != nil {
+			return err
+		}
+		components := []model.EmployeeComponent{}
+		for _, input := range req.Components {
+			components = append(components, model.EmployeeComponent{
+				EmployeeID: employee.ID,
+				Name:       strings.TrimSpace(input.Name),
+				Type:       input.Type,
+				Amount:     input.Amount,
+			})
+		}
+		if len(components) == 0 {
+			return nil
+		}
+		return tx.Create(&components).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetEmployee(id, scope)
+}
+
+// ---------------- OVERTIME ----------------
+
+func (s *EmployeeService) GetOvertime(id uint, params model.EmployeeOvertimeQuery, scope model.OutletScope) ([]model.EmployeeOvertime, error) {
+	if _, err := findEmployee(database.DbCore, id, scope); err != nil {
+		return nil, err
+	}
+	query := database.DbCore.Where("employee_id = ?", id)
+	if params.From != "" {
+		query = query.Where("date >= ?", params.From)
+	}
+	if params.To != "" {
+		query = query.Where("date <= ?", params.To)
+	}
+	entries := []model.EmployeeOvertime{}
+	err := query.Order("date ASC").Order("id ASC").Find(&entries).Error
+	return entries, err
+}
+
+func (s *EmployeeService) AddOvertime(id uint, req model.EmployeeOvertimeRequest, scope model.OutletScope, actorID string) (*model.EmployeeOvertime, error) {
+	employee, err := findEmployee(database.DbCore, id, scope)
+	if err != nil {
+		return nil, err
+	}
+	if !employee.Active {
+		return nil, ErrEmployeeInactive
+	}
+	date, err := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := model.EmployeeOvertime{
+		EmployeeID: employee.ID,
+		Date:       date,
+		Minutes:    req.Minutes,
+		Source:     model.OvertimeManual,
+		Note:       req.Note,
+		CreatedBy:  actorID,
+	}
+	if err := database.DbCore.Create(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// DeleteOvertime removes an overtime entry that has not been paid yet.
+func (s *EmployeeService) DeleteOvertime(id, overtimeID uint, scope model.OutletScope) error {
+	if _, err := findEmployee(database.DbCore, id, scope); err != nil {
+		return err
+	}
+	return database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var entry model.EmployeeOvertime
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("employee_id = ?", id).
+			First(&entry, overtimeID).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEmployeeOvertimeMissing
+			}
+			return err
+		}
+		if entry.PayrollRunID != nil {
+			return ErrEmployeeOvertimePaid
+		}
+		return tx.Delete(&entry).Error
+	})
+}
+
+func findEmployee(db *gorm.DB, id uint, scope model.OutletScope) (*model.Employee, error) {
+	var employee model.Employee
+	if err := scopeOutletColumn(db, "outlet_id", scope).First(&employee, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEmployeeNotFound
+		}
+		return nil, err
+	}
+	return &employee, nil
+}
+
+// applyEmployeeRequest checks the outlet, the unique code and the linked user
+// before copying the request onto the employee.
+func applyEmployeeRequest(tx *gorm.DB, employee *model.Employee, req model.EmployeeRequest, scope model.OutletScope) error {
+	if err := checkOutletInScope(tx, req.OutletID, scope); err != nil {
+		return err
+	}
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	var count int64
+	if err := tx.Model(&model.Employee{}).Where("code = ? AND id <> ?", code, employee.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrEmployeeCodeExists
+	}
+
+	var userID *string
+	if req.UserID != nil && strings.TrimSpace(*req.UserID) != "" {
+		trimmed := strings.TrimSpace(*req.UserID)
+		userID = &trimmed
+		if err := tx.Model(&model.Employee{}).Where("user_id = ? AND id <> ?", trimmed, employee.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrEmployeeUserLinked
+		}
+	}
+	joinDate, err := time.ParseInLocation("2006-01-02", req.JoinDate, time.Local)
+	if err != nil {
+		return err
+	}
+	active := req.Active == nil || *req.Active
+	var endDate *time.Time
+	if req.EndDate != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+		if err != nil {
+			return err
+		}
+		endDate = &date
+	} else if !active {
+		// Dinonaktifkan tanpa tanggal keluar: hari ini hari kerja terakhirnya,
+		// kecuali sudah tercatat sebelumnya
+		endDate = employee.EndDate
+		if endDate == nil {
+			now := time.Now()
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+			endDate = &today
+		}
+	}
+	if endDate != nil && endDate.Before(joinDate) {
+		return ErrEmployeeEndDate
+	}
+
+	employee.Code = code
+	employee.Name = strings.TrimSpace(req.Name)
+	employee.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	employee.Phone = req.Phone
+	employee.OutletID = req.OutletID
+	employee.Position = req.Position
+	employee.UserID = userID
+	employee.JoinDate = joinDate
+	employee.EndDate = endDate
+	employee.Active = active
+	employee.BaseSalary = req.BaseSalary
+	employee.OvertimeRate = req.OvertimeRate
+	employee.BankName = req.BankName
+	employee.BankAccount = req.BankAccount
+	employee.Components = nil
+	return nil
+}
@@ -0,0 +1,455 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var (
+	ErrExpenseNotFound            = errors.New("expense not found")
+	ErrExpenseCategoryNotFound    = errors.New("expense category not found")
+	ErrExpenseCategoryInactive    = errors.New("expense category is not active")
+	ErrExpenseCategoryExists      = errors.New("expense category name is already used")
+	ErrExpenseCategoryInUse       = errors.New("expense category is used by expenses, deactivate it instead")
+	ErrExpenseReviewed            = errors.New("expense has already been reviewed")
+	ErrExpenseNotPending          = errors.New("expense is not waiting for approval")
+	ErrExpenseSelfReview          = errors.New("an expense cannot be reviewed by the user who submitted it")
+	ErrInvalidExpensePeriod       = errors.New("from must not be after to")
+	ErrExpenseReceiptTypeInvalid  = errors.New("receipt must be a jpg, png, webp or pdf file")
+	ErrExpenseReceiptUploadFailed = errors.New("failed to upload receipt")
+)
+
+const defaultExpensePageSize = 20
+
+// Content type nota berdasarkan ekstensi file
+var expenseReceiptTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+	".pdf":  "application/pdf",
+}
+
+type ExpenseService struct{}
+
+func NewExpenseService() *ExpenseService {
+	return &ExpenseService{}
+}
+
+// ---------------- CATEGORY ----------------
+
+func (s *ExpenseService) GetCategories(activeOnly bool) ([]model.ExpenseCategory, error) {
+	query := database.DbCore.Model(&model.ExpenseCategory{})
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	categories := []model.ExpenseCategory{}
+	err := query.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (s *ExpenseService) CreateCategory(req model.ExpenseCategoryRequest) (*model.ExpenseCategory, error) {
+	category := model.ExpenseCategory{}
+	applyExpenseCategoryRequest(&category, req)
+	if err := checkExpenseCategoryName(database.DbCore, &category); err != nil {
+		return nil, err
+	}
+	if err := database.DbCore.Create(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (s *ExpenseService) UpdateCategory(id uint, req model.ExpenseCategoryRequest) (*model.ExpenseCategory, error) {
+	category, err := findExpenseCategory(database.DbCore, id)
+	if err != nil {
+		return nil, err
+	}
+	applyExpenseCategoryRequest(category, req)
+	if err := checkExpenseCategoryName(database.DbCore, category); err != nil {
+		return nil, err
+	}
+	if err := database.DbCore.Save(category).Error; err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// DeleteCategory removes a category that no expense uses. Used categories can only
+// be deactivated so old expenses keep their category.
+func (s *ExpenseService) DeleteCategory(id uint) error {
+	category, err := findExpenseCategory(database.DbCore, id)
+	if err != nil {
+		return err
+	}
+	var used int64
+	if err := database.DbCore.Unscoped().Model(&model.Expense{}).Where("category_id = ?", id).Count(&used).Error; err != nil {
+		return err
+	}
+	if used > 0 {
+		return ErrExpenseCategoryInUse
+	}
+	return database.DbCore.Delete(category).Error
+}
+
+// ---------------- EXPENSE ----------------
+
+func (s *ExpenseService) GetExpenses(params model.ExpenseListQuery, scope model.OutletScope) (*model.ExpenseListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.Expense{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.CategoryID != 0 {
+		query = query.Where("category_id = ?", params.CategoryID)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if params.From != "" {
+		query = query.Where("expense_date >= ?", params.From)
+	}
+	if params.To != "" {
+		query = query.Where("expense_date <= ?", params.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+	var totalAmount int64
+	if err := query.Session(&gorm.Session{}).Select("COALESCE(SUM(amount), 0)").Scan(&totalAmount).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultExpensePageSize
+	}
+
+	expenses := []model.Expense{}
+	err := query.Preload("Category").
+		Order("expense_date DESC").Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ExpenseListResult{
+		Expenses:    expenses,
+		TotalAmount: totalAmount,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+// GetExpense returns the expense with a temporary download link for the receipt.
+func (s *ExpenseService) GetExpense(id uint, scope model.OutletScope) (*model.Expense, error) {
+	expense, err := findExpense(database.DbCore.Preload("Category"), id, scope)
+	if err != nil {
+		return nil, err
+	}
+	if expense.ReceiptKey != "" {
+		url, err := thirdparty.GetFileBucket(expense.ReceiptKey)
+		if err != nil {
+			middleware.LogError(err, "Failed to sign expense receipt URL")
+		}
+		expense.ReceiptURL = url
+	}
+	return expense, nil
+}
+
+// CreateExpense records an expense. Amounts up to EXPENSE_APPROVAL_THRESHOLD are
+// approved right away; larger ones wait for a supervisor and do not count towards
+// net revenue until approved.
+func (s *ExpenseService) CreateExpense(req model.ExpenseRequest, scope model.OutletScope, actorID string) (*model.Expense, error) {
+	var expense model.Expense
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := applyExpenseRequest(tx, &expense, req, scope); err != nil {
+			return err
+		}
+		seq, err := nextDocumentNumber(tx, fmt.Sprintf("expense:%d", expense.ExpenseDate.Year()))
+		if err != nil {
+			return err
+		}
+		expense.Number = fmt.Sprintf("EXP-%d-%05d", expense.ExpenseDate.Year(), seq)
+		expense.SubmittedBy = actorID
+		return tx.Create(&expense).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetExpense(expense.ID, scope)
+}
+
+// UpdateExpense changes an expense that no supervisor has reviewed yet. The
+// approval threshold is applied again to the new amount.
+func (s *ExpenseService) UpdateExpense(id uint, req model.ExpenseRequest, scope model.OutletScope) (*model.Expense, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		expense, err := findExpense(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, scope)
+		if err != nil {
+			return err
+		}
+		if expense.ReviewedAt != nil {
+			return ErrExpenseReviewed
+		}
+		if err := applyExpenseRequest(tx, expense, req, scope); err != nil {
+			return err
+		}
+		return tx.Save(expense).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetExpense(id, scope)
+}
+
+// DeleteExpense removes an expense that no supervisor has reviewed yet.
+func (s *ExpenseService) DeleteExpense(id uint, scope model.OutletScope) error {
+	return database.DbCore.Transaction(func(tx *gorm.DB) error {
+		expense, err := findExpense(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, scope)
+		if err != nil {
+			return err
+		}
+		if expense.ReviewedAt != nil {
+			return ErrExpenseReviewed
+		}
+		return tx.Delete(expense).Error
+	})
+}
+
+// ReviewExpense approves or rejects a pending expense. The submitter cannot
+// review their own expense, otherwise the approval threshold means nothing.
+func (s *ExpenseService) ReviewExpense(id uint, approve bool, req model.ExpenseReviewRequest, scope model.OutletScope, reviewerID string) (*model.Expense, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		expense, err := findExpense(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id, scope)
+		if err != nil {
+			return err
+		}
+		if expense.Status != model.ExpensePending {
+			return ErrExpenseNotPending
+		}
+		if expense.SubmittedBy == reviewerID {
+			return ErrExpenseSelfReview
+		}
+
+		status := model.ExpenseRejected
+		if approve {
+			status = model.ExpenseApproved
+		}
+		return tx.Model(expense).Updates(map[string]interface{}{
+			"status":      status,
+			"reviewed_by": reviewerID,
+			"reviewed_at": time.Now(),
+			"review_note": req.Note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetExpense(id, scope)
+}
+
+// UploadReceipt stores the receipt photo/scan in the bucket and links it to the
+// expense, replacing the previous one. The old file is removed after the new key
+// is saved; a failed delete is only logged.
+func (s *ExpenseService) UploadReceipt(id uint, data []byte, ext string, scope model.OutletScope) (*model.Expense, error) {
+	contentType, ok := expenseReceiptTypes[strings.ToLower(ext)]
+	if !ok {
+		return nil, ErrExpenseReceiptTypeInvalid
+	}
+	expense, err := findExpense(database.DbCore, id, scope)
+	if err != nil {
+		return nil, err
+	}
+	if expense.ReviewedAt != nil {
+		return nil, ErrExpenseReviewed
+	}
+
+	key := fmt.Sprintf("expenses/%d/%d/%s-%d%s", expense.OutletID, expense.ExpenseDate.Year(), expense.Number, time.Now().UnixNano(), strings.ToLower(ext))
+	if err := thirdparty.UploadBytesBucket(data, key, contentType); err != nil {
+		middleware.LogError(err, "Failed to upload expense receipt")
+		return nil, ErrExpenseReceiptUploadFailed
+	}
+	if err := database.DbCore.Model(expense).Update("receipt_key", key).Error; err != nil {
+		return nil, err
+	}
+	if expense.ReceiptKey != "" {
+		if err := thirdparty.DeleteFileBucket(expense.ReceiptKey); err != nil {
+			middleware.LogError(err, "Failed to delete old expense receipt")
+		}
+	}
+	return s.GetExpense(id, scope)
+}
+
+// GetNetRevenue computes the dashboard's net revenue between from and to
+// (inclusive dates, default this month so far): sales that were not voided, minus
+// return refunds approved in the period, minus approved expenses.
+func (s *ExpenseService) GetNetRevenue(params model.NetRevenueQuery, scope model.OutletScope) (*model.NetRevenueSummary, error) {
+	to := startOfDay(time.Now())
+	if params.To != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", params.To, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		to = parsed
+	}
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.Local)
+	if params.From != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", params.From, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return nil, ErrInvalidExpensePeriod
+	}
+	end := to.AddDate(0, 0, 1)
+
+	scoped := func(db *gorm.DB) *gorm.DB {
+		db = scopeOutletColumn(db, "outlet_id", scope)
+		if params.OutletID != 0 {
+			db = db.Where("outlet_id = ?", params.OutletID)
+		}
+		return db
+	}
+	summary := model.NetRevenueSummary{
+		From:               from.Format("2006-01-02"),
+		To:                 to.Format("2006-01-02"),
+		ExpensesByCategory: []model.ExpenseCategoryTotal{},
+	}
+
+	err := scoped(database.DbCore.Model(&model.Sale{})).
+		Where("status <> ? AND sold_at >= ? AND sold_at < ?", model.SaleStatusVoided, from, end).
+		Select("COALESCE(SUM(total), 0)").Scan(&summary.GrossSales).Error
+	if err != nil {
+		return nil, err
+	}
+	err = scoped(database.DbCore.Model(&model.SaleReversal{})).
+		Where("type = ? AND status = ? AND reviewed_at >= ? AND reviewed_at < ?", model.SaleReversalReturn, model.SaleReversalApproved, from, end).
+		Select("COALESCE(SUM(refund_total), 0)").Scan(&summary.Returns).Error
+	if err != nil {
+		return nil, err
+	}
+
+	fromDate, toDate := from.Format("2006-01-02"), to.Format("2006-01-02")
+	err = scoped(database.DbCore.Model(&model.Expense{})).
+		Where("expenses.status = ? AND expense_date >= ? AND expense_date <= ?", model.ExpenseApproved, fromDate, toDate).
+		Joins("JOIN expense_categories ON expense_categories.id = expenses.category_id").
+		Select("expenses.category_id, expense_categories.name, SUM(expenses.amount) AS amount").
+		Group("expenses.category_id, expense_categories.name").
+		Order("amount DESC").
+		Scan(&summary.ExpensesByCategory).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range summary.ExpensesByCategory {
+		summary.Expenses += category.Amount
+	}
+	err = scoped(database.DbCore.Model(&model.Expense{})).
+		Where("status = ? AND expense_date >= ? AND expense_date <= ?", model.ExpensePending, fromDate, toDate).
+		Select("COALESCE(SUM(amount), 0)").Scan(&summary.PendingExpenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary.NetSales = summary.GrossSales - summary.Returns
+	summary.NetRevenue = summary.NetSales - summary.Expenses
+	return &summary, nil
+}
+
+func findExpense(db *gorm.DB, id uint, scope model.OutletScope) (*model.Expense, error) {
+	var expense model.Expense
+	if err := scopeOutletColumn(db, "outlet_id", scope).First(&expense, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExpenseNotFound
+		}
+		return nil, err
+	}
+	return &expense, nil
+}
+
+func findExpenseCategory(db *gorm.DB, id uint) (*model.ExpenseCategory, error) {
+	var category model.ExpenseCategory
+	if err := db.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExpenseCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func applyExpenseCategoryRequest(category *model.ExpenseCategory, req model.ExpenseCategoryRequest) {
+	category.Name = strings.TrimSpace(req.Name)
+	category.Description = req.Description
+	category.Active = req.Active == nil || *req.Active
+}
+
+func checkExpenseCategoryName(db *gorm.DB, category *model.ExpenseCategory) error {
+	var count int64
+	err := db.Model(&model.ExpenseCategory{}).
+		Where("name = ? AND id <> ?", category.Name, category.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrExpenseCategoryExists
+	}
+	return nil
+}
+
+// applyExpenseRequest validates the outlet and category and sets the status from
+// the approval threshold.
+func applyExpenseRequest(tx *gorm.DB, expense *model.Expense, req model.ExpenseRequest, scope model.OutletScope) error {
+	if err := checkOutletInScope(tx, req.OutletID, scope); err != nil {
+		return err
+	}
+	category, err := findExpenseCategory(tx, req.CategoryID)
+	if err != nil {
+		return err
+	}
+	// Kategori nonaktif masih boleh dipakai pengeluaran lama yang diedit
+	if !category.Active && category.ID != expense.CategoryID {
+		return ErrExpenseCategoryInactive
+	}
+	expenseDate, err := time.ParseInLocation("2006-01-02", req.ExpenseDate, time.Local)
+	if err != nil {
+		return err
+	}
+
+	expense.OutletID = req.OutletID
+	expense.CategoryID = req.CategoryID
+	expense.Category = nil
+	expense.Amount = req.Amount
+	expense.ExpenseDate = expenseDate
+	expense.PaymentMethod = req.PaymentMethod
+	expense.Vendor = req.Vendor
+	expense.Description = req.Description
+	expense.RequiresApproval = req.Amount > config.EXPENSE_APPROVAL_THRESHOLD
+	expense.Status = model.ExpenseApproved
+	if expense.RequiresApproval {
+		expense.Status = model.ExpensePending
+	}
+	return nil
+}
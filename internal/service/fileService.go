@@ -0,0 +1,16 @@
+package service
+
+import (
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+)
+
+func InsertUserFile(file *model.UserFile) (bool, error) {
+	result := database.DbCore.Create(file)
+	if result.Error != nil {
+		middleware.LogError(result.Error, "Insert User File Failed")
+		return false, result.Error
+	}
+	return true, nil
+}
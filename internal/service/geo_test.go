@@ -0,0 +1,62 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"BackendFramework/internal/model"
+)
+
+func TestHaversineKm(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lng1, lat2, lng2 float64
+		want                   float64
+	}{
+		{name: "same point", lat1: -6.2088, lng1: 106.8456, lat2: -6.2088, lng2: 106.8456, want: 0},
+		{name: "one degree along a meridian", lat1: 0, lng1: 106, lat2: 1, lng2: 106, want: 111.195},
+		{name: "one degree along the equator", lat1: 0, lng1: 106, lat2: 0, lng2: 107, want: 111.195},
+		{name: "Jakarta to Surabaya", lat1: -6.2088, lng1: 106.8456, lat2: -7.2575, lng2: 112.7521, want: 663.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := haversineKm(tt.lat1, tt.lng1, tt.lat2, tt.lng2)
+			if math.Abs(got-tt.want) > 1 {
+				t.Errorf("haversineKm = %.3f, want %.3f", got, tt.want)
+			}
+			if back := haversineKm(tt.lat2, tt.lng2, tt.lat1, tt.lng1); math.Abs(back-got) > 1e-9 {
+				t.Errorf("distance is not symmetric: %.6f vs %.6f", got, back)
+			}
+		})
+	}
+}
+
+func TestPointInPolygon(t *testing.T) {
+	square := []model.GeoPoint{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 10}, {Lat: 10, Lng: 10}, {Lat: 10, Lng: 0}}
+	// Bentuk L: kotak 10x10 tanpa kuadran kanan atas
+	lShape := []model.GeoPoint{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 10}, {Lat: 5, Lng: 10}, {Lat: 5, Lng: 5}, {Lat: 10, Lng: 5}, {Lat: 10, Lng: 0}}
+
+	tests := []struct {
+		name    string
+		point   model.GeoPoint
+		polygon []model.GeoPoint
+		want    bool
+	}{
+		{name: "inside a square", point: model.GeoPoint{Lat: 5, Lng: 5}, polygon: square, want: true},
+		{name: "outside a square", point: model.GeoPoint{Lat: 11, Lng: 5}, polygon: square, want: false},
+		{name: "level with a vertex but outside", point: model.GeoPoint{Lat: 10, Lng: 12}, polygon: square, want: false},
+		{name: "inside the arm of an L", point: model.GeoPoint{Lat: 8, Lng: 2}, polygon: lShape, want: true},
+		{name: "in the notch of an L", point: model.GeoPoint{Lat: 8, Lng: 8}, polygon: lShape, want: false},
+		{name: "open ring is closed implicitly", point: model.GeoPoint{Lat: 1, Lng: 1}, polygon: []model.GeoPoint{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 4}, {Lat: 4, Lng: 0}}, want: true},
+		{name: "empty polygon", point: model.GeoPoint{Lat: 1, Lng: 1}, polygon: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pointInPolygon(tt.point, tt.polygon); got != tt.want {
+				t.Errorf("pointInPolygon = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrStockQuantitySign        = errors.New("quantity sign does not match the movement type")
+	ErrStockOutletScope         = errors.New("outlet not found")
+	ErrStockMovementFuture      = errors.New("occurredAt cannot be in the future")
+	ErrStockMovementBeforeCount = errors.New("occurredAt cannot be before the last approved stock count of the outlet")
+	ErrStockAdjustmentForbidden = errors.New("only supervisors can record stock adjustments")
+)
+
+const defaultStockMovementPageSize = 50
+
+type InventoryService struct{}
+
+func NewInventoryService() *InventoryService {
+	return &InventoryService{}
+}
+
+// RecordMovements validates manual ledger entries (purchase, waste, adjustment)
+// and posts them in one transaction. Adjustments need a supervisor, and a
+// movement cannot be dated in the future or before the last approved stock
+// count of its outlet.
+func (s *InventoryService) RecordMovements(req model.StockMovementRequest, scope model.OutletScope, actorID string) ([]model.StockMovement, error) {
+	movements := []model.StockMovement{}
+	now := time.Now()
+	adjusts := false
+	for _, input := range req.Movements {
+		occurredAt := now
+		if input.OccurredAt != nil {
+			occurredAt = *input.OccurredAt
+		}
+		if occurredAt.After(now) {
+			return nil, ErrStockMovementFuture
+		}
+		if input.Type == model.StockMovementAdjustment {
+			adjusts = true
+		}
+		movements = append(movements, model.StockMovement{
+			OutletID:      input.OutletID,
+			ProductID:     input.ProductID,
+			VariantID:     input.VariantID,
+			Type:          input.Type,
+			Quantity:      input.Quantity,
+			ReferenceType: model.StockReferenceManual,
+			Note:          input.Note,
+			ActorID:       actorID,
+			OccurredAt:    occurredAt,
+		})
+	}
+
+	if adjusts {
+		var actor model.User
+		if err := database.DbCore.Select("username", "group").Where("username = ?", actorID).First(&actor).Error; err != nil {
+			return nil, ErrStockAdjustmentForbidden
+		}
+		if actor.Group != config.GROUP_ADMIN && actor.Group != config.GROUP_REGION_MANAGER {
+			return nil, ErrStockAdjustmentForbidden
+		}
+	}
+
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		lastCounts := map[uint]time.Time{}
+		for _, movement := range movements {
+			if err := checkStockItem(tx, movement.OutletID, movement.ProductID, movement.VariantID, scope); err != nil {
+				return err
+			}
+
+			// Riwayat sebelum stock opname terakhir sudah dikunci oleh hasil hitungnya
+			approvedAt, ok := lastCounts[movement.OutletID]
+			if !ok {
+				var count model.StockCount
+				err := tx.Select("id", "approved_at").
+					Where("outlet_id = ? AND status = ?", movement.OutletID, model.StockCountApproved).
+					Order("approved_at DESC").
+					Limit(1).Find(&count).Error
+				if err != nil {
+					return err
+				}
+				if count.ApprovedAt != nil {
+					approvedAt = *count.ApprovedAt
+				}
+				lastCounts[movement.OutletID] = approvedAt
+			}
+			if movement.OccurredAt.Before(approvedAt) {
+				return ErrStockMovementBeforeCount
+			}
+		}
+		return PostMovements(tx, movements)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// PostMovements appends movements to the ledger and moves the cached stock level
+// in the same transaction. Sales, transfers and stock counts post through here.
+func PostMovements(tx *gorm.DB, movements []model.StockMovement) error {
+	for i := range movements {
+		movement := &movements[i]
+		if err := checkMovementSign(*movement); err != nil {
+			return err
+		}
+		if movement.OccurredAt.IsZero() {
+			movement.OccurredAt = time.Now()
+		}
+		if err := tx.Create(movement).Error; err != nil {
+			return err
+		}
+
+		level := model.StockLevel{
+			OutletID:  movement.OutletID,
+			ProductID: movement.ProductID,
+			VariantID: movement.VariantID,
+			Quantity:  movement.Quantity,
+		}
+		err := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"quantity":   gorm.Expr("quantity + ?", movement.Quantity),
+				"updated_at": time.Now(),
+			}),
+		}).Create(&level).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStock returns stock on hand. Without asOf it reads the cache, with asOf it
+// sums the ledger up to that moment.
+func (s *InventoryService) GetStock(params model.StockQuery, scope model.OutletScope) ([]model.StockOnHand, error) {
+	levels := []model.StockLevel{}
+	query := scopeOutletColumn(database.DbCore.Model(&model.StockLevel{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.ProductID != 0 {
+		query = query.Where("product_id = ?", params.ProductID)
+	}
+	if err := query.Order("outlet_id, product_id, variant_id").Find(&levels).Error; err != nil {
+		return nil, err
+	}
+
+	if params.AsOf != "" {
+		asOf, err := time.Parse(time.RFC3339, params.AsOf)
+		if err != nil {
+			return nil, err
+		}
+		balances, err := ledgerBalances(params, scope, asOf)
+		if err != nil {
+			return nil, err
+		}
+		for i := range levels {
+			levels[i].Quantity = balances[stockKey(levels[i].OutletID, levels[i].ProductID, levels[i].VariantID)]
+		}
+	}
+
+	result := []model.StockOnHand{}
+	for _, level := range levels {
+		isLow := level.LowStockThreshold != nil && level.Quantity <= *level.LowStockThreshold
+		if params.LowOnly && !isLow {
+			continue
+		}
+		result = append(result, model.StockOnHand{
+			OutletID:          level.OutletID,
+			ProductID:         level.ProductID,
+			VariantID:         level.VariantID,
+			Quantity:          level.Quantity,
+			LowStockThreshold: level.LowStockThreshold,
+			IsLow:             isLow,
+		})
+	}
+	return result, nil
+}
+
+func (s *InventoryService) GetMovements(params model.StockMovementQuery, scope model.OutletScope) (*model.StockMovementListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.StockMovement{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.ProductID != 0 {
+		query = query.Where("product_id = ?", params.ProductID)
+	}
+	if params.VariantID != nil {
+		query = query.Where("variant_id = ?", *params.VariantID)
+	}
+	if params.Type != "" {
+		query = query.Where("type = ?", params.Type)
+	}
+	if params.From != "" {
+		from, err := time.Parse(time.RFC3339, params.From)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("occurred_at >= ?", from)
+	}
+	if params.To != "" {
+		to, err := time.Parse(time.RFC3339, params.To)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("occurred_at <= ?", to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultStockMovementPageSize
+	}
+
+	movements := []model.StockMovement{}
+	err := query.Order("occurred_at DESC").Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StockMovementListResult{
+		Movements: movements,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+// SetThreshold sets (or with nil clears) the low-stock threshold of one item.
+func (s *InventoryService) SetThreshold(req model.StockThresholdRequest, scope model.OutletScope) (*model.StockLevel, error) {
+	if err := checkStockItem(database.DbCore, req.OutletID, req.ProductID, req.VariantID, scope); err != nil {
+		return nil, err
+	}
+
+	level := model.StockLevel{
+		OutletID:          req.OutletID,
+		ProductID:         req.ProductID,
+		VariantID:         req.VariantID,
+		LowStockThreshold: req.LowStockThreshold,
+	}
+	err := database.DbCore.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"low_stock_threshold", "updated_at"}),
+	}).Create(&level).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.DbCore.
+		Where("outlet_id = ? AND product_id = ? AND variant_id = ?", req.OutletID, req.ProductID, req.VariantID).
+		First(&level).Error
+	if err != nil {
+		return nil, err
+	}
+	return &level, nil
+}
+
+// RebuildStockLevels recomputes the cached quantities of an outlet (0 = all
+// outlets) from the ledger, e.g. after restoring a backup.
+func (s *InventoryService) RebuildStockLevels(outletID uint) (int, error) {
+	rebuilt := 0
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var balances []model.StockLevel
+		query := tx.Model(&model.StockMovement{}).
+			Select("outlet_id, product_id, variant_id, SUM(quantity) AS quantity").
+			Group("outlet_id, product_id, variant_id")
+		if outletID != 0 {
+			query = query.Where("outlet_id = ?", outletID)
+		}
+		if err := query.Scan(&balances).Error; err != nil {
+			return err
+		}
+
+		// Item yang tidak punya movement sama sekali kembali ke 0
+		reset := tx.Model(&model.StockLevel{}).Where("1 = 1")
+		if outletID != 0 {
+			reset = reset.Where("outlet_id = ?", outletID)
+		}
+		if err := reset.Updates(map[string]interface{}{"quantity": 0, "updated_at": time.Now()}).Error; err != nil {
+			return err
+		}
+
+		for _, balance := range balances {
+			level := model.StockLevel{
+				OutletID:  balance.OutletID,
+				ProductID: balance.ProductID,
+				VariantID: balance.VariantID,
+				Quantity:  balance.Quantity,
+			}
+			err := tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+			}).Create(&level).Error
+			if err != nil {
+				return err
+			}
+		}
+		rebuilt = len(balances)
+		return nil
+	})
+	return rebuilt, err
+}
+
+// ledgerBalances sums the ledger per item up to asOf.
+func ledgerBalances(params model.StockQuery, scope model.OutletScope, asOf time.Time) (map[string]float64, error) {
+	var rows []struct {
+		OutletID  uint
+		ProductID uint
+		VariantID uint
+		Quantity  float64
+	}
+	query := scopeOutletColumn(database.DbCore.Model(&model.StockMovement{}), "outlet_id", scope).
+		Select("outlet_id, product_id, variant_id, SUM(quantity) AS quantity").
+		Where("occurred_at <= ?", asOf).
+		Group("outlet_id, product_id, variant_id")
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.ProductID != 0 {
+		query = query.Where("product_id = ?", params.ProductID)
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	balances := map[string]float64{}
+	for _, row := range rows {
+		balances[stockKey(row.OutletID, row.ProductID, row.VariantID)] = row.Quantity
+	}
+	return balances, nil
+}
+
+func stockKey(outletID, productID, variantID uint) string {
+	return fmt.Sprintf("%d/%d/%d", outletID, productID, variantID)
+}
+
+// checkMovementSign enforces the direction of each movement type; adjustments
+// and transfers can go both ways.
+func checkMovementSign(movement model.StockMovement) error {
+	if movement.Quantity == 0 {
+		return ErrStockQuantitySign
+	}
+	switch movement.Type {
+	case model.StockMovementPurchase, model.StockMovementReturn:
+		if movement.Quantity < 0 {
+			return ErrStockQuantitySign
+		}
+	case model.StockMovementSale, model.StockMovementWaste:
+		if movement.Quantity > 0 {
+			return ErrStockQuantitySign
+		}
+	case model.StockMovementAdjustment, model.StockMovementTransfer:
+	default:
+		return fmt.Errorf("unknown stock movement type %q", movement.Type)
+	}
+	return nil
+}
+
+// checkStockItem verifies the outlet is visible to the caller and the product /
+// variant exists.
+func checkStockItem(db *gorm.DB, outletID, productID, variantID uint, scope model.OutletScope) error {
+	if err := checkOutletInScope(db, outletID, scope); err != nil {
+		return err
+	}
+	_, err := findProductVariant(db, productID, variantID)
+	return err
+}
+
+// checkOutletInScope returns ErrStockOutletScope for a missing outlet as well as
+// one outside the caller's regions, so neither leaks its existence.
+func checkOutletInScope(db *gorm.DB, outletID uint, scope model.OutletScope) error {
+	var outlet model.Outlet
+	if err := db.Select("id", "region_id").First(&outlet, outletID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrStockOutletScope
+		}
+		return err
+	}
+	if !scope.Allows(outlet.RegionID) {
+		return ErrStockOutletScope
+	}
+	return nil
+}
+
+// scopeOutletColumn limits rows with an outlet column to the outlets in scope.
+func scopeOutletColumn(db *gorm.DB, column string, scope model.OutletScope) *gorm.DB {
+	if !scope.Restricted {
+		return db
+	}
+	outlets := scopeOutlets(database.DbCore.Model(&model.Outlet{}).Select("id"), scope)
+	return db.Where(column+" IN (?)", outlets)
+}
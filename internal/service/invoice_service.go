@@ -0,0 +1,256 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var (
+	ErrInvoiceNotFound     = errors.New("invoice not found")
+	ErrInvoiceExists       = errors.New("sale already has an invoice")
+	ErrInvoiceSaleVoided   = errors.New("voided sales cannot be invoiced")
+	ErrInvoiceDueDateEarly = errors.New("due date must not be before the issue date")
+)
+
+const (
+	invoiceTemplatePath    = "./web/html/invoice.html"
+	defaultInvoicePageSize = 20
+)
+
+type InvoiceService struct{}
+
+func NewInvoiceService() *InvoiceService {
+	return &InvoiceService{}
+}
+
+// CreateInvoice issues the invoice of a sale. The number comes from the outlet's
+// counter for the current year inside the same transaction as the insert, so a
+// failed insert never burns a number. The PDF is archived to the bucket after
+// the commit; a failed upload is logged and does not undo the invoice.
+func (s *InvoiceService) CreateInvoice(req model.InvoiceRequest, scope model.OutletScope, actorID string) (*model.Invoice, error) {
+	var invoice model.Invoice
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		sale, err := lockSale(tx, req.SaleID, scope)
+		if err != nil {
+			return err
+		}
+		if sale.Status == model.SaleStatusVoided {
+			return ErrInvoiceSaleVoided
+		}
+		var existing int64
+		if err := tx.Model(&model.Invoice{}).Where("sale_id = ?", sale.ID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrInvoiceExists
+		}
+
+		var outlet model.Outlet
+		if err := tx.First(&outlet, sale.OutletID).Error; err != nil {
+			return err
+		}
+
+		issuedAt := time.Now().In(outlet.Location())
+		var dueDate *time.Time
+		if req.DueDate != "" {
+			due, err := time.ParseInLocation("2006-01-02", req.DueDate, outlet.Location())
+			if err != nil {
+				return err
+			}
+			if due.Before(time.Date(issuedAt.Year(), issuedAt.Month(), issuedAt.Day(), 0, 0, 0, 0, issuedAt.Location())) {
+				return ErrInvoiceDueDateEarly
+			}
+			dueDate = &due
+		}
+
+		year := issuedAt.Year()
+		seq, err := nextDocumentNumber(tx, fmt.Sprintf("invoice:%d:%d", outlet.ID, year))
+		if err != nil {
+			return err
+		}
+		outletCode := strconv.FormatUint(uint64(outlet.ID), 10)
+		if outlet.Code != nil && *outlet.Code != "" {
+			outletCode = *outlet.Code
+		}
+
+		invoice = model.Invoice{
+			Number:          fmt.Sprintf("INV/%s/%d/%05d", outletCode, year, seq),
+			OutletID:        outlet.ID,
+			Year:            year,
+			Sequence:        seq,
+			SaleID:          sale.ID,
+			IssuedAt:        issuedAt,
+			DueDate:         dueDate,
+			SellerName:      config.INVOICE_COMPANY_NAME,
+			SellerAddress:   config.INVOICE_COMPANY_ADDRESS,
+			SellerTaxID:     config.INVOICE_COMPANY_TAX_ID,
+			OutletName:      outlet.Name,
+			OutletAddress:   outlet.Address,
+			CustomerName:    req.CustomerName,
+			CustomerAddress: req.CustomerAddress,
+			CustomerTaxID:   req.CustomerTaxID,
+			CustomerEmail:   req.CustomerEmail,
+			CustomerPhone:   req.CustomerPhone,
+			Subtotal:        sale.Subtotal,
+			DiscountTotal:   sale.DiscountTotal,
+			TaxTotal:        sale.TaxTotal,
+			Total:           sale.Total,
+			Note:            req.Note,
+			CreatedBy:       actorID,
+		}
+		for _, line := range sale.Lines {
+			invoice.Lines = append(invoice.Lines, model.InvoiceLine{
+				Description: line.Name,
+				SKU:         line.SKU,
+				Quantity:    line.Quantity,
+				UnitPrice:   line.UnitPrice,
+				Discount:    line.Discount,
+				TaxRate:     line.TaxRate,
+				TaxAmount:   line.TaxAmount,
+				LineTotal:   line.LineTotal,
+			})
+		}
+		return tx.Create(&invoice).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ArchiveInvoice(&invoice); err != nil {
+		middleware.LogError(err, "Failed to archive invoice PDF")
+	}
+	return &invoice, nil
+}
+
+// ArchiveInvoice renders the invoice and stores the PDF in the bucket.
+func (s *InvoiceService) ArchiveInvoice(invoice *model.Invoice) error {
+	pdf, err := renderInvoicePDF(invoice)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("invoices/%d/%d/%s.pdf", invoice.OutletID, invoice.Year, strings.ReplaceAll(invoice.Number, "/", "-"))
+	if err := thirdparty.UploadBytesBucket(pdf, key, "application/pdf"); err != nil {
+		return err
+	}
+	invoice.ArchiveKey = key
+	return database.DbCore.Model(invoice).Update("archive_key", key).Error
+}
+
+func (s *InvoiceService) GetInvoices(params model.InvoiceListQuery, scope model.OutletScope) (*model.InvoiceListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.Invoice{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.Year != 0 {
+		query = query.Where("year = ?", params.Year)
+	}
+	if params.Search != "" {
+		search := "%" + params.Search + "%"
+		query = query.Where("number LIKE ? OR customer_name LIKE ?", search, search)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultInvoicePageSize
+	}
+
+	invoices := []model.Invoice{}
+	err := query.Order("issued_at DESC").Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&invoices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.InvoiceListResult{
+		Invoices: invoices,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *InvoiceService) GetInvoice(id uint, scope model.OutletScope) (*model.Invoice, error) {
+	var invoice model.Invoice
+	err := scopeOutletColumn(database.DbCore.Model(&model.Invoice{}), "outlet_id", scope).
+		Preload("Lines").
+		First(&invoice, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// InvoicePDF renders the invoice in memory for download.
+func (s *InvoiceService) InvoicePDF(id uint, scope model.OutletScope) ([]byte, *model.Invoice, error) {
+	invoice, err := s.GetInvoice(id, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	pdf, err := renderInvoicePDF(invoice)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pdf, invoice, nil
+}
+
+func renderInvoicePDF(invoice *model.Invoice) ([]byte, error) {
+	tmpl, err := template.New("invoice.html").Funcs(template.FuncMap{
+		"rupiah":   formatRupiah,
+		"quantity": formatQuantity,
+	}).ParseFiles(invoiceTemplatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	html := new(bytes.Buffer)
+	if err := tmpl.Execute(html, invoice); err != nil {
+		return nil, err
+	}
+	return thirdparty.GeneratePdfBytes(html.String())
+}
+
+// formatRupiah formats an amount as "Rp 1.234.567".
+func formatRupiah(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return sign + "Rp " + grouped.String()
+}
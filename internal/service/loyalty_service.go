@@ -0,0 +1,774 @@
+package service
+
+import (
+	"errors"
+	"html"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var (
+	ErrLoyaltyRuleNotFound       = errors.New("earn rule not found")
+	ErrLoyaltyRuleConflict       = errors.New("another active earn rule already covers this category")
+	ErrLoyaltyTierNotFound       = errors.New("tier not found")
+	ErrLoyaltyNoCustomer         = errors.New("points can only be redeemed for a registered customer")
+	ErrLoyaltyPointsAmount       = errors.New("points tender must be a multiple of the point value")
+	ErrLoyaltyInsufficientPoints = errors.New("customer does not have enough points")
+)
+
+const (
+	defaultLoyaltyPageSize  = 20
+	loyaltyEmailTemplate    = "./web/html/email_template.html"
+	loyaltyExpiringSoonDays = 30
+)
+
+type LoyaltyService struct{}
+
+func NewLoyaltyService() *LoyaltyService {
+	return &LoyaltyService{}
+}
+
+// tierChange dikirim sebagai email setelah transaksi DB selesai
+type tierChange struct {
+	Customer model.Customer
+	From     *model.LoyaltyTier
+	To       *model.LoyaltyTier
+}
+
+func (s *LoyaltyService) GetRules() ([]model.LoyaltyEarnRule, error) {
+	rules := []model.LoyaltyEarnRule{}
+	if err := database.DbCore.Order("category_id IS NULL").Order("name ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (s *LoyaltyService) CreateRule(req model.LoyaltyEarnRuleRequest) (*model.LoyaltyEarnRule, error) {
+	rule := model.LoyaltyEarnRule{}
+	applyEarnRuleRequest(&rule, req)
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := checkEarnRule(tx, &rule); err != nil {
+			return err
+		}
+		return tx.Create(&rule).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *LoyaltyService) UpdateRule(id uint, req model.LoyaltyEarnRuleRequest) (*model.LoyaltyEarnRule, error) {
+	var rule model.LoyaltyEarnRule
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&rule, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrLoyaltyRuleNotFound
+			}
+			return err
+		}
+		applyEarnRuleRequest(&rule, req)
+		if err := checkEarnRule(tx, &rule); err != nil {
+			return err
+		}
+		return tx.Save(&rule).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteRule removes the rule; points already earned stay in the ledger.
+func (s *LoyaltyService) DeleteRule(id uint) error {
+	result := database.DbCore.Delete(&model.LoyaltyEarnRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLoyaltyRuleNotFound
+	}
+	return nil
+}
+
+func (s *LoyaltyService) GetTiers() ([]model.LoyaltyTier, error) {
+	tiers := []model.LoyaltyTier{}
+	if err := database.DbCore.Order("min_spend ASC").Find(&tiers).Error; err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+func (s *LoyaltyService) CreateTier(req model.LoyaltyTierRequest) (*model.LoyaltyTier, error) {
+	tier := model.LoyaltyTier{Name: req.Name, MinSpend: req.MinSpend, Multiplier: req.Multiplier}
+	if err := database.DbCore.Create(&tier).Error; err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// UpdateTier changes the tier; customers move to their new tier on their next
+// sale or the next maintenance run.
+func (s *LoyaltyService) UpdateTier(id uint, req model.LoyaltyTierRequest) (*model.LoyaltyTier, error) {
+	var tier model.LoyaltyTier
+	if err := database.DbCore.First(&tier, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLoyaltyTierNotFound
+		}
+		return nil, err
+	}
+	tier.Name = req.Name
+	tier.MinSpend = req.MinSpend
+	tier.Multiplier = req.Multiplier
+	if err := database.DbCore.Save(&tier).Error; err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// DeleteTier removes the tier and clears it from its members until they are
+// evaluated again.
+func (s *LoyaltyService) DeleteTier(id uint) error {
+	return database.DbCore.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.LoyaltyTier{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrLoyaltyTierNotFound
+		}
+		return tx.Model(&model.Customer{}).Where("tier_id = ?", id).Update("tier_id", nil).Error
+	})
+}
+
+// GetSummary returns the customer's balance, tier, rolling spend and the next
+// tier to reach.
+func (s *LoyaltyService) GetSummary(customerID uint) (*model.LoyaltySummary, error) {
+	customer, err := findCustomer(database.DbCore, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	spend, err := customerNetSpend(database.DbCore, customer.ID, now.AddDate(0, -config.LOYALTY_TIER_WINDOW_MONTHS, 0))
+	if err != nil {
+		return nil, err
+	}
+	tiers, err := s.GetTiers()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := model.LoyaltySummary{
+		CustomerID:   customer.ID,
+		Points:       customer.Points,
+		PointValue:   config.LOYALTY_POINT_VALUE,
+		RollingSpend: spend,
+	}
+	for i := range tiers {
+		if customer.TierID != nil && tiers[i].ID == *customer.TierID {
+			summary.Tier = &tiers[i]
+		}
+		if summary.NextTier == nil && tiers[i].MinSpend > spend {
+			summary.NextTier = &tiers[i]
+		}
+	}
+
+	err = database.DbCore.Model(&model.LoyaltyTransaction{}).
+		Where("customer_id = ? AND remaining > 0 AND expires_at <= ?", customer.ID, now.AddDate(0, 0, loyaltyExpiringSoonDays)).
+		Select("COALESCE(SUM(remaining), 0)").
+		Scan(&summary.ExpiringSoon).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (s *LoyaltyService) GetLedger(customerID uint, params model.LoyaltyLedgerQuery) (*model.LoyaltyLedgerResult, error) {
+	if _, err := findCustomer(database.DbCore, customerID); err != nil {
+		return nil, err
+	}
+
+	query := database.DbCore.Model(&model.LoyaltyTransaction{}).Where("customer_id = ?", customerID)
+	if params.Type != "" {
+		query = query.Where("type = ?", params.Type)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultLoyaltyPageSize
+	}
+
+	transactions := []model.LoyaltyTransaction{}
+	err := query.Order("occurred_at DESC").Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoyaltyLedgerResult{
+		Transactions: transactions,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+// Adjust posts a manual correction. Positive adjustments expire like earned points;
+// negative ones cannot take the balance below zero.
+func (s *LoyaltyService) Adjust(customerID uint, req model.LoyaltyAdjustmentRequest, actorID string) (*model.LoyaltyTransaction, error) {
+	var entry model.LoyaltyTransaction
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		customer, err := lockCustomer(tx, customerID)
+		if err != nil {
+			return err
+		}
+		if req.Points < 0 && customer.Points+req.Points < 0 {
+			return ErrLoyaltyInsufficientPoints
+		}
+
+		now := time.Now()
+		entry = model.LoyaltyTransaction{
+			Type:       model.LoyaltyAdjust,
+			Points:     req.Points,
+			Note:       req.Note,
+			ActorID:    actorID,
+			OccurredAt: now,
+		}
+		if req.Points > 0 {
+			expiresAt := now.AddDate(0, config.LOYALTY_POINT_EXPIRY_MONTHS, 0)
+			entry.ExpiresAt = &expiresAt
+		}
+		return postLoyalty(tx, customer, &entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RunMaintenance expires points that are past their date and re-evaluates the tier
+// of every customer who has a tier or bought something within the tier window, so
+// downgrades happen once old sales roll out. Meant to be called daily.
+func (s *LoyaltyService) RunMaintenance(now time.Time) (*model.LoyaltyMaintenanceResult, error) {
+	result := model.LoyaltyMaintenanceResult{}
+
+	var expiring []uint
+	err := database.DbCore.Model(&model.LoyaltyTransaction{}).
+		Where("remaining > 0 AND expires_at <= ?", now).
+		Distinct().Pluck("customer_id", &expiring).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, customerID := range expiring {
+		var expired int64
+		err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+			// Pelanggan yang sudah dihapus tetap diproses supaya tidak muncul lagi besok
+			customer, err := lockCustomer(tx.Unscoped(), customerID)
+			if err != nil {
+				return err
+			}
+			var entries []model.LoyaltyTransaction
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("customer_id = ? AND remaining > 0 AND expires_at <= ?", customerID, now).
+				Find(&entries).Error
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				expired += entry.Remaining
+				if err := tx.Model(&entry).Update("remaining", 0).Error; err != nil {
+					return err
+				}
+			}
+			if expired == 0 {
+				return nil
+			}
+			return postLoyalty(tx, customer, &model.LoyaltyTransaction{
+				Type:       model.LoyaltyExpire,
+				Points:     -expired,
+				Note:       "Poin hangus",
+				OccurredAt: now,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		if expired > 0 {
+			result.CustomersExpired++
+			result.PointsExpired += expired
+		}
+	}
+
+	var tiered, active []uint
+	if err := database.DbCore.Model(&model.Customer{}).Where("tier_id IS NOT NULL").Pluck("id", &tiered).Error; err != nil {
+		return nil, err
+	}
+	err = database.DbCore.Model(&model.Sale{}).
+		Where("customer_id IS NOT NULL AND sold_at >= ?", now.AddDate(0, -config.LOYALTY_TIER_WINDOW_MONTHS, 0)).
+		Distinct().Pluck("customer_id", &active).Error
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []*tierChange{}
+	for _, customerID := range uniqueIDs(append(tiered, active...)) {
+		var change *tierChange
+		err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+			customer, err := lockCustomer(tx, customerID)
+			if err != nil {
+				return err
+			}
+			change, err = evaluateTier(tx, customer, now)
+			return err
+		})
+		if errors.Is(err, ErrCustomerNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, change)
+		}
+	}
+	result.TierChanges = len(changes)
+	sendTierEmails(changes)
+
+	return &result, nil
+}
+
+// applySaleLoyalty redeems the points tender and posts the points earned by a
+// new sale, then re-evaluates the customer's tier. It runs inside the sale's
+// transaction after the sale row exists.
+func applySaleLoyalty(tx *gorm.DB, sale *model.Sale, actorID string) (*tierChange, error) {
+	var redeemAmount int64
+	for _, tender := range sale.Tenders {
+		if tender.Method == model.TenderPoints {
+			redeemAmount += tender.Amount
+		}
+	}
+	if sale.CustomerID == nil {
+		if redeemAmount > 0 {
+			return nil, ErrLoyaltyNoCustomer
+		}
+		return nil, nil
+	}
+
+	customer, err := lockCustomer(tx, *sale.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if redeemAmount > 0 {
+		if redeemAmount%config.LOYALTY_POINT_VALUE != 0 {
+			return nil, ErrLoyaltyPointsAmount
+		}
+		points := redeemAmount / config.LOYALTY_POINT_VALUE
+		if customer.Points < points {
+			return nil, ErrLoyaltyInsufficientPoints
+		}
+		err := postLoyalty(tx, customer, &model.LoyaltyTransaction{
+			Type:          model.LoyaltyRedeem,
+			Points:        -points,
+			ReferenceType: model.LoyaltyReferenceSale,
+			ReferenceID:   sale.ID,
+			Note:          sale.ReceiptNumber,
+			ActorID:       actorID,
+			OccurredAt:    sale.SoldAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	earned, err := salePointsEarned(tx, sale, customer, redeemAmount)
+	if err != nil {
+		return nil, err
+	}
+	if earned > 0 {
+		expiresAt := sale.SoldAt.AddDate(0, config.LOYALTY_POINT_EXPIRY_MONTHS, 0)
+		err := postLoyalty(tx, customer, &model.LoyaltyTransaction{
+			Type:          model.LoyaltyEarn,
+			Points:        earned,
+			ExpiresAt:     &expiresAt,
+			ReferenceType: model.LoyaltyReferenceSale,
+			ReferenceID:   sale.ID,
+			Note:          sale.ReceiptNumber,
+			ActorID:       actorID,
+			OccurredAt:    sale.SoldAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sale.PointsEarned = earned
+		if err := tx.Model(sale).Update("points_earned", earned).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return evaluateTier(tx, customer, time.Now())
+}
+
+// reverseSaleLoyalty takes back the points earned on the reversed part of a sale
+// and returns redeemed points refunded through the points tender. A void or the
+// last return takes back whatever is left, so rounding never leaves points behind.
+// The balance may go negative when the points were already spent.
+func reverseSaleLoyalty(tx *gorm.DB, sale *model.Sale, reversal *model.SaleReversal, final bool, actorID string) (*tierChange, error) {
+	if sale.CustomerID == nil {
+		return nil, nil
+	}
+	customer, err := lockCustomer(tx, *sale.CustomerID)
+	if errors.Is(err, ErrCustomerNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var reversed int64
+	err = tx.Model(&model.LoyaltyTransaction{}).
+		Where("reference_type = ? AND reference_id = ? AND type = ?", model.LoyaltyReferenceSale, sale.ID, model.LoyaltyReversal).
+		Select("COALESCE(SUM(-points), 0)").
+		Scan(&reversed).Error
+	if err != nil {
+		return nil, err
+	}
+	takeBack := sale.PointsEarned - reversed
+	if !final && sale.Total > 0 {
+		takeBack = min(takeBack, int64(math.Round(float64(sale.PointsEarned)*float64(reversal.RefundTotal)/float64(sale.Total))))
+	}
+	if takeBack > 0 {
+		err := postLoyalty(tx, customer, &model.LoyaltyTransaction{
+			Type:          model.LoyaltyReversal,
+			Points:        -takeBack,
+			ReferenceType: model.LoyaltyReferenceSale,
+			ReferenceID:   sale.ID,
+			Note:          reversal.Number,
+			ActorID:       actorID,
+			OccurredAt:    now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var refundAmount int64
+	for _, refund := range reversal.Refunds {
+		if refund.Method == model.TenderPoints {
+			refundAmount += refund.Amount
+		}
+	}
+	if points := refundAmount / config.LOYALTY_POINT_VALUE; points > 0 {
+		expiresAt := now.AddDate(0, config.LOYALTY_POINT_EXPIRY_MONTHS, 0)
+		err := postLoyalty(tx, customer, &model.LoyaltyTransaction{
+			Type:          model.LoyaltyRefund,
+			Points:        points,
+			ExpiresAt:     &expiresAt,
+			ReferenceType: model.LoyaltyReferenceSale,
+			ReferenceID:   sale.ID,
+			Note:          reversal.Number,
+			ActorID:       actorID,
+			OccurredAt:    now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return evaluateTier(tx, customer, now)
+}
+
+// salePointsEarned applies the earn rules per line: the category rule when there
+// is one, otherwise the default rule. Tax is not rewarded, the part paid with
+// points is not rewarded again, and the tier multiplier is applied last.
+func salePointsEarned(tx *gorm.DB, sale *model.Sale, customer *model.Customer, redeemAmount int64) (int64, error) {
+	var rules []model.LoyaltyEarnRule
+	if err := tx.Where("active = ?", true).Find(&rules).Error; err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 || sale.Total <= 0 {
+		return 0, nil
+	}
+	var fallback *model.LoyaltyEarnRule
+	byCategory := map[uint]*model.LoyaltyEarnRule{}
+	for i := range rules {
+		if rules[i].CategoryID == nil {
+			fallback = &rules[i]
+		} else {
+			byCategory[*rules[i].CategoryID] = &rules[i]
+		}
+	}
+
+	productIDs := []uint{}
+	for _, line := range sale.Lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	var products []model.Product
+	if err := tx.Unscoped().Select("id", "category_id").Where("id IN ?", uniqueIDs(productIDs)).Find(&products).Error; err != nil {
+		return 0, err
+	}
+	categoryOf := map[uint]*uint{}
+	for _, product := range products {
+		categoryOf[product.ID] = product.CategoryID
+	}
+
+	base := map[*model.LoyaltyEarnRule]int64{}
+	for _, line := range sale.Lines {
+		rule := fallback
+		if category := categoryOf[line.ProductID]; category != nil && byCategory[*category] != nil {
+			rule = byCategory[*category]
+		}
+		if rule != nil {
+			base[rule] += line.LineTotal - line.TaxAmount
+		}
+	}
+
+	paidRatio := float64(sale.Total-min(redeemAmount, sale.Total)) / float64(sale.Total)
+	var points int64
+	for rule, amount := range base {
+		points += int64(float64(amount)*paidRatio) / rule.SpendAmount * rule.Points
+	}
+
+	if customer.TierID != nil && points > 0 {
+		var tier model.LoyaltyTier
+		err := tx.First(&tier, *customer.TierID).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, err
+		}
+		if err == nil {
+			points = int64(math.Floor(float64(points) * tier.Multiplier))
+		}
+	}
+	return points, nil
+}
+
+// evaluateTier moves the customer to the highest tier their rolling spend reaches
+// and records the change. It returns nil when the tier stays the same.
+func evaluateTier(tx *gorm.DB, customer *model.Customer, now time.Time) (*tierChange, error) {
+	spend, err := customerNetSpend(tx, customer.ID, now.AddDate(0, -config.LOYALTY_TIER_WINDOW_MONTHS, 0))
+	if err != nil {
+		return nil, err
+	}
+	var tiers []model.LoyaltyTier
+	if err := tx.Order("min_spend DESC").Find(&tiers).Error; err != nil {
+		return nil, err
+	}
+
+	change := tierChange{}
+	for i := range tiers {
+		if change.To == nil && spend >= tiers[i].MinSpend {
+			change.To = &tiers[i]
+		}
+		if customer.TierID != nil && tiers[i].ID == *customer.TierID {
+			change.From = &tiers[i]
+		}
+	}
+	if change.From == change.To {
+		return nil, nil
+	}
+
+	var toID *uint
+	if change.To != nil {
+		toID = &change.To.ID
+	}
+	if err := tx.Model(&model.Customer{}).Where("id = ?", customer.ID).Update("tier_id", toID).Error; err != nil {
+		return nil, err
+	}
+	err = tx.Create(&model.LoyaltyTierChange{
+		CustomerID:   customer.ID,
+		FromTierID:   customer.TierID,
+		ToTierID:     toID,
+		RollingSpend: spend,
+		ChangedAt:    now,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	customer.TierID = toID
+	change.Customer = *customer
+	return &change, nil
+}
+
+// postLoyalty appends a ledger row and moves the cached balance. Negative rows
+// use up earlier points first-expiring-first; expiry rows have already zeroed the
+// rows they expire. While the balance is negative, new points pay that off first.
+func postLoyalty(tx *gorm.DB, customer *model.Customer, entry *model.LoyaltyTransaction) error {
+	entry.CustomerID = customer.ID
+	if entry.Points > 0 {
+		entry.Remaining = entry.Points
+		if customer.Points < 0 {
+			entry.Remaining = max(0, entry.Points+customer.Points)
+		}
+	} else if entry.Type != model.LoyaltyExpire {
+		if err := consumePoints(tx, customer.ID, -entry.Points); err != nil {
+			return err
+		}
+	}
+	if err := tx.Create(entry).Error; err != nil {
+		return err
+	}
+
+	customer.Points += entry.Points
+	return tx.Model(&model.Customer{}).Where("id = ?", customer.ID).
+		UpdateColumn("points", gorm.Expr("points + ?", entry.Points)).Error
+}
+
+func consumePoints(tx *gorm.DB, customerID uint, points int64) error {
+	var entries []model.LoyaltyTransaction
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("customer_id = ? AND remaining > 0", customerID).
+		Order("expires_at IS NULL").Order("expires_at ASC").Order("id ASC").
+		Find(&entries).Error
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if points <= 0 {
+			break
+		}
+		used := min(points, entry.Remaining)
+		if err := tx.Model(&entry).Update("remaining", entry.Remaining-used).Error; err != nil {
+			return err
+		}
+		points -= used
+	}
+	return nil
+}
+
+// customerNetSpend sums the customer's non-voided sales since the given time,
+// minus approved return refunds on those sales.
+func customerNetSpend(db *gorm.DB, customerID uint, since time.Time) (int64, error) {
+	var spent int64
+	err := db.Model(&model.Sale{}).
+		Where("customer_id = ? AND status <> ? AND sold_at >= ?", customerID, model.SaleStatusVoided, since).
+		Select("COALESCE(SUM(total), 0)").
+		Scan(&spent).Error
+	if err != nil {
+		return 0, err
+	}
+	refunded, err := customerReturnRefunds(db, customerID, since)
+	if err != nil {
+		return 0, err
+	}
+	return spent - refunded, nil
+}
+
+func customerReturnRefunds(db *gorm.DB, customerID uint, since time.Time) (int64, error) {
+	var refunded int64
+	err := db.Model(&model.SaleReversal{}).
+		Joins("JOIN sales ON sales.id = sale_reversals.sale_id").
+		Where("sales.customer_id = ? AND sales.sold_at >= ? AND sale_reversals.type = ? AND sale_reversals.status = ?",
+			customerID, since, model.SaleReversalReturn, model.SaleReversalApproved).
+		Select("COALESCE(SUM(sale_reversals.refund_total), 0)").
+		Scan(&refunded).Error
+	return refunded, err
+}
+
+func lockCustomer(tx *gorm.DB, id uint) (*model.Customer, error) {
+	return findCustomer(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func applyEarnRuleRequest(rule *model.LoyaltyEarnRule, req model.LoyaltyEarnRuleRequest) {
+	rule.Name = req.Name
+	rule.CategoryID = req.CategoryID
+	rule.SpendAmount = req.SpendAmount
+	rule.Points = req.Points
+	rule.Active = req.Active == nil || *req.Active
+}
+
+// checkEarnRule allows one active rule per category and one active default rule.
+func checkEarnRule(tx *gorm.DB, rule *model.LoyaltyEarnRule) error {
+	if err := checkProductCategory(tx, rule.CategoryID); err != nil {
+		return err
+	}
+	if !rule.Active {
+		return nil
+	}
+	query := tx.Model(&model.LoyaltyEarnRule{}).Where("active = ? AND id <> ?", true, rule.ID)
+	if rule.CategoryID == nil {
+		query = query.Where("category_id IS NULL")
+	} else {
+		query = query.Where("category_id = ?", *rule.CategoryID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrLoyaltyRuleConflict
+	}
+	return nil
+}
+
+// sendTierEmails memberi tahu pelanggan yang tier-nya berubah. Pelanggan tanpa
+// email dilewati; kegagalan kirim sudah dicatat oleh SendEmail.
+func sendTierEmails(changes []*tierChange) {
+	if len(changes) == 0 {
+		return
+	}
+	f, err := os.ReadFile(loyaltyEmailTemplate)
+	if err != nil {
+		middleware.LogError(err, "Failed open HTML")
+		return
+	}
+
+	year := strconv.Itoa(time.Now().Year())
+	for _, change := range changes {
+		if change.Customer.Email == nil {
+			continue
+		}
+
+		subject, opening, detail := "Perubahan tier member Anda", "", ""
+		switch {
+		case change.To == nil:
+			opening = "Tier member Anda telah berakhir."
+			detail = "Belanja kembali untuk mendapatkan tier member dan keuntungannya."
+		case change.From == nil || change.To.MinSpend > change.From.MinSpend:
+			subject = "Selamat, tier member Anda naik ke " + change.To.Name
+			opening = "Selamat! Tier member Anda sekarang " + change.To.Name + "."
+			detail = "Setiap transaksi kini mendapat poin " + strconv.FormatFloat(change.To.Multiplier, 'f', -1, 64) + "x."
+		default:
+			opening = "Tier member Anda berubah menjadi " + change.To.Name + "."
+			detail = "Tier dihitung dari total belanja " + strconv.Itoa(config.LOYALTY_TIER_WINDOW_MONTHS) + " bulan terakhir."
+		}
+
+		templateString := string(f)
+		templateString = strings.Replace(templateString, "{{nama}}", html.EscapeString(change.Customer.Name), 1)
+		templateString = strings.Replace(templateString, "{{Opening_text}}", opening, 1)
+		templateString = strings.Replace(templateString, "{{keterangan}}", detail, 1)
+		templateString = strings.Replace(templateString, "{{Year}}", year, 1)
+		templateString = strings.Replace(templateString, "{{Link}}", config.PUBLIC_BASE_URL, 1)
+		templateString = strings.Replace(templateString, "{{Nama Sistem}}", config.INVOICE_COMPANY_NAME, 1)
+
+		thirdparty.SendEmail(templateString, subject, []thirdparty.RecipientStruct{{
+			Name:  change.Customer.Name,
+			Email: *change.Customer.Email,
+		}})
+	}
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+const OutletSheetName = "Outlets"
+
+// Kolom spreadsheet master outlet, dipakai untuk export maupun import
+var outletExcelHeaders = []thirdparty.Header{
+	{Text: "Code", Width: 12},
+	{Text: "Name", Width: 30},
+	{Text: "Address", Width: 45},
+	{Text: "Phone", Width: 16},
+	{Text: "Manager", Width: 20},
+	{Text: "Status", Width: 10},
+	{Text: "Region ID", Width: 10},
+	{Text: "Time Zone", Width: 16},
+	{Text: "Latitude", Width: 12},
+	{Text: "Longitude", Width: 12},
+	{Text: "Open Hours", Width: 20},
+	{Text: "Weekly Schedule", Width: 50},
+}
+
+// outletImportPlan is one valid row waiting to be written.
+type outletImportPlan struct {
+	row    int
+	req    model.OutletRequest
+	target *model.Outlet
+}
+
+// ExportOutlets writes the outlets matching the list filters to an xlsx file and
+// returns its path. The caller removes the file after sending it.
+func (s *OutletService) ExportOutlets(params model.OutletListQuery, scope model.OutletScope) (string, error) {
+	query, err := filterOutlets(params, scope)
+	if err != nil {
+		return "", err
+	}
+
+	var outlets []model.Outlet
+	if err := query.Preload("Hours").Order("name ASC").Order("id ASC").Find(&outlets).Error; err != nil {
+		return "", err
+	}
+
+	rows := []map[string]interface{}{}
+	for _, outlet := range outlets {
+		row := map[string]interface{}{
+			"Code":            "",
+			"Name":            outlet.Name,
+			"Address":         outlet.Address,
+			"Phone":           outlet.Phone,
+			"Manager":         outlet.Manager,
+			"Status":          outlet.Status,
+			"Region ID":       "",
+			"Time Zone":       outlet.TimeZone,
+			"Latitude":        "",
+			"Longitude":       "",
+			"Open Hours":      outlet.OpenHours,
+			"Weekly Schedule": model.FormatWeeklyHours(outlet.Hours),
+		}
+		if outlet.Code != nil {
+			row["Code"] = *outlet.Code
+		}
+		if outlet.RegionID != nil {
+			row["Region ID"] = *outlet.RegionID
+		}
+		if outlet.Latitude != nil && outlet.Longitude != nil {
+			row["Latitude"] = *outlet.Latitude
+			row["Longitude"] = *outlet.Longitude
+		}
+		rows = append(rows, row)
+	}
+
+	file, err := os.CreateTemp("", "outlets-*.xlsx")
+	if err != nil {
+		return "", err
+	}
+	file.Close()
+
+	if !thirdparty.GenerateExcelFile(outletExcelHeaders, rows, OutletSheetName, file.Name()) {
+		os.Remove(file.Name())
+		return "", errors.New("failed to generate outlet excel file")
+	}
+	return file.Name(), nil
+}
+
+// ImportOutlets validates spreadsheet rows and upserts them by code, or by name
+// when the row has no code or the code is still unknown. The file is applied
+// all-or-nothing: with dryRun or any invalid row nothing is written.
+func (s *OutletService) ImportOutlets(rows []map[string]interface{}, dryRun bool, scope model.OutletScope, actorID string) (*model.OutletImportResult, error) {
+	var outlets []model.Outlet
+	if err := database.DbCore.Preload("Hours").Find(&outlets).Error; err != nil {
+		return nil, err
+	}
+	byCode := map[string]*model.Outlet{}
+	byName := map[string][]*model.Outlet{}
+	for i := range outlets {
+		outlet := &outlets[i]
+		if outlet.Code != nil {
+			byCode[*outlet.Code] = outlet
+		}
+		name := strings.ToLower(outlet.Name)
+		byName[name] = append(byName[name], outlet)
+	}
+
+	// Outlet yang sudah dihapus tetap memegang kodenya di unique index
+	var deletedCodes []string
+	if err := database.DbCore.Unscoped().Model(&model.Outlet{}).
+		Where("deleted_at IS NOT NULL AND code IS NOT NULL").Pluck("code", &deletedCodes).Error; err != nil {
+		return nil, err
+	}
+	deleted := map[string]bool{}
+	for _, code := range deletedCodes {
+		deleted[code] = true
+	}
+
+	var regionIDs []uint
+	if err := database.DbCore.Model(&model.Region{}).Pluck("id", &regionIDs).Error; err != nil {
+		return nil, err
+	}
+	regions := map[uint]bool{}
+	for _, id := range regionIDs {
+		regions[id] = true
+	}
+
+	result := &model.OutletImportResult{DryRun: dryRun, Total: len(rows), Rows: []model.OutletImportRow{}}
+	plans := []outletImportPlan{}
+	seen := map[string]int{}
+	targets := map[uint]int{}
+
+	for i, row := range rows {
+		rowNumber := i + 2
+		req, errs := outletRequestFromRow(row)
+		line := model.OutletImportRow{Row: rowNumber, Name: req.Name}
+		if req.Code != nil {
+			line.Code = *req.Code
+		}
+
+		if len(errs) == 0 {
+			errs = append(errs, validationMessages(middleware.Validator.Struct(req))...)
+		}
+		if req.Code != nil && deleted[*req.Code] {
+			errs = append(errs, fmt.Sprintf("code %q still belongs to a deleted outlet", *req.Code))
+		}
+		if req.RegionID != nil && !regions[*req.RegionID] {
+			errs = append(errs, fmt.Sprintf("region %d does not exist", *req.RegionID))
+		}
+		if !scope.Allows(req.RegionID) {
+			errs = append(errs, ErrOutOfScope.Error())
+		}
+
+		key := "name:" + strings.ToLower(req.Name)
+		if req.Code != nil {
+			key = "code:" + *req.Code
+		}
+		if previous, ok := seen[key]; ok {
+			errs = append(errs, fmt.Sprintf("duplicate of row %d", previous))
+		}
+		seen[key] = rowNumber
+
+		target, err := matchImportedOutlet(req, byCode, byName)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		if target != nil {
+			if !scope.Allows(target.RegionID) {
+				errs = append(errs, ErrOutOfScope.Error())
+			}
+			if previous, ok := targets[target.ID]; ok {
+				errs = append(errs, fmt.Sprintf("updates the same outlet as row %d", previous))
+			}
+			targets[target.ID] = rowNumber
+		}
+
+		if len(errs) > 0 {
+			line.Errors = errs
+			result.Failed++
+		} else if target != nil {
+			line.Action = model.OutletImportUpdate
+			line.OutletID = target.ID
+			result.Updated++
+			plans = append(plans, outletImportPlan{row: rowNumber, req: req, target: target})
+		} else {
+			line.Action = model.OutletImportCreate
+			result.Created++
+			plans = append(plans, outletImportPlan{row: rowNumber, req: req})
+		}
+		result.Rows = append(result.Rows, line)
+	}
+
+	if dryRun || result.Failed > 0 {
+		return result, nil
+	}
+
+	// Geocoding dikerjakan sebelum transaksi supaya transaksi tidak menunggu HTTP
+	created := map[int]*model.Outlet{}
+	befores := map[int]model.Outlet{}
+	for _, plan := range plans {
+		if plan.target == nil {
+			outlet := s.newOutlet(plan.req)
+			created[plan.row] = &outlet
+			continue
+		}
+		befores[plan.row] = *plan.target
+		s.applyOutletRequest(plan.target, plan.req)
+	}
+
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		for _, plan := range plans {
+			if outlet, ok := created[plan.row]; ok {
+				if err := tx.Create(outlet).Error; err != nil {
+					return fmt.Errorf("row %d: %w", plan.row, err)
+				}
+				if err := RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(outlet.ID), model.AuditActionCreate, actorID, nil, *outlet); err != nil {
+					return err
+				}
+				continue
+			}
+
+			outlet := plan.target
+			version := outlet.Version
+			outlet.Version = version + 1
+			updated := tx.Model(outlet).Where("version = ?", version).
+				Select("*").Omit(clause.Associations, "created_at", "deleted_at").Updates(outlet)
+			if updated.Error != nil {
+				return fmt.Errorf("row %d: %w", plan.row, updated.Error)
+			}
+			if updated.RowsAffected == 0 {
+				return fmt.Errorf("row %d: %w", plan.row, ErrVersionConflict)
+			}
+			if plan.req.Hours != nil {
+				if err := replaceOutletHours(tx, outlet.ID, outlet.Hours); err != nil {
+					return err
+				}
+			}
+			if err := RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(outlet.ID), model.AuditActionUpdate, actorID, befores[plan.row], *outlet); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		middleware.LogError(err, "Outlet import failed")
+		return nil, err
+	}
+
+	for i := range result.Rows {
+		if outlet, ok := created[result.Rows[i].Row]; ok {
+			result.Rows[i].OutletID = outlet.ID
+		}
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// matchImportedOutlet finds the outlet a row updates, nil means the row creates one.
+func matchImportedOutlet(req model.OutletRequest, byCode map[string]*model.Outlet, byName map[string][]*model.Outlet) (*model.Outlet, error) {
+	if req.Code != nil {
+		if outlet, ok := byCode[*req.Code]; ok {
+			return outlet, nil
+		}
+	}
+
+	// Kode baru boleh ditempelkan ke outlet lama yang namanya sama dan belum punya kode
+	var candidates []*model.Outlet
+	for _, outlet := range byName[strings.ToLower(req.Name)] {
+		if req.Code == nil || outlet.Code == nil {
+			candidates = append(candidates, outlet)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return candidates[0], nil
+	default:
+		return nil, fmt.Errorf("name %q matches %d outlets, add a code to pick one", req.Name, len(candidates))
+	}
+}
+
+// outletRequestFromRow converts a spreadsheet row into an OutletRequest. The
+// errors are about unparseable cells; the field rules are checked by the validator.
+func outletRequestFromRow(row map[string]interface{}) (model.OutletRequest, []string) {
+	cell := func(name string) string {
+		return excelCell(row, name)
+	}
+
+	req := model.OutletRequest{
+		Name:      cell("Name"),
+		Address:   cell("Address"),
+		Phone:     cell("Phone"),
+		Manager:   cell("Manager"),
+		Status:    strings.ToLower(cell("Status")),
+		OpenHours: cell("Open Hours"),
+		TimeZone:  cell("Time Zone"),
+	}
+	var errs []string
+
+	if code := cell("Code"); code != "" {
+		req.Code = &code
+	}
+	if region := cell("Region ID"); region != "" {
+		id, err := strconv.ParseUint(region, 10, 32)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Region ID %q is not a number", region))
+		} else {
+			regionID := uint(id)
+			req.RegionID = &regionID
+		}
+	}
+	for _, column := range []struct {
+		name  string
+		value **float64
+	}{
+		{"Latitude", &req.Latitude},
+		{"Longitude", &req.Longitude},
+	} {
+		text := cell(column.name)
+		if text == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s %q is not a number", column.name, text))
+			continue
+		}
+		*column.value = &value
+	}
+	if schedule := cell("Weekly Schedule"); schedule != "" {
+		hours, err := model.ParseWeeklyHours(schedule)
+		if err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			req.Hours = hours
+		}
+	}
+	return req, errs
+}
+
+func validationMessages(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return []string{err.Error()}
+	}
+	messages := []string{}
+	for _, fieldError := range fieldErrors {
+		messages = append(messages, fmt.Sprintf("%s failed on '%s'", fieldError.Namespace(), fieldError.Tag()))
+	}
+	return messages
+}
+
+// excelCell returns the trimmed text of a cell read by thirdparty.ReadExcelFile,
+// or "" when the column is missing.
+func excelCell(row map[string]interface{}, name string) string {
+	value, ok := row[name]
+	if !ok || value == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(value))
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+const (
+	defaultOutletQRSize  = 256
+	outletQRSheetColumns = 3
+	outletQRSheetSize    = 512
+	outletQRSheetPath    = "./web/html/outlet_qr_sheet.html"
+)
+
+var (
+	ErrOutletNotPublic     = errors.New("outlet not found")
+	ErrPublicBaseURLNotSet = errors.New("PUBLIC_BASE_URL is not configured, outlet QR codes need an absolute link")
+)
+
+// OutletPublicURL is the link encoded in an outlet's QR code. It fails when
+// PUBLIC_BASE_URL is not set, since a relative link cannot be opened from a phone.
+func OutletPublicURL(id uint) (string, error) {
+	if config.PUBLIC_BASE_URL == "" {
+		return "", ErrPublicBaseURLNotSet
+	}
+	return config.PUBLIC_BASE_URL + "/outlets/" + strconv.FormatUint(uint64(id), 10), nil
+}
+
+// GenerateOutletQR renders the QR code of one outlet in memory and returns the
+// image together with its content type.
+func (s *OutletService) GenerateOutletQR(id uint, params model.OutletQRQuery) ([]byte, string, error) {
+	var outlet model.Outlet
+	if err := database.DbCore.Select("id").First(&outlet, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("outlet not found")
+		}
+		return nil, "", err
+	}
+
+	size := params.Size
+	if size == 0 {
+		size = defaultOutletQRSize
+	}
+
+	url, err := OutletPublicURL(outlet.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if params.Format == "svg" {
+		image, err := thirdparty.GenerateQrSvg(url, size)
+		return image, "image/svg+xml", err
+	}
+	image, err := thirdparty.GenerateQrPng(url, size)
+	return image, "image/png", err
+}
+
+type outletQRCard struct {
+	Name  string
+	Code  string
+	URL   string
+	Image template.URL
+}
+
+// GenerateQRSheet renders a printable PDF with the QR code and name of every
+// active outlet matching the list filters. Inactive outlets are left out since
+// their public page is not found.
+func (s *OutletService) GenerateQRSheet(params model.OutletListQuery, scope model.OutletScope) ([]byte, error) {
+	if config.PUBLIC_BASE_URL == "" {
+		return nil, ErrPublicBaseURLNotSet
+	}
+	query, err := filterOutlets(params, scope)
+	if err != nil {
+		return nil, err
+	}
+	query = query.Where("status = ?", "active")
+
+	var outlets []model.Outlet
+	if err := query.Order("name ASC").Order("id ASC").Find(&outlets).Error; err != nil {
+		return nil, err
+	}
+
+	rows := [][]outletQRCard{}
+	for i, outlet := range outlets {
+		url, err := OutletPublicURL(outlet.ID)
+		if err != nil {
+			return nil, err
+		}
+		png, err := thirdparty.GenerateQrPng(url, outletQRSheetSize)
+		if err != nil {
+			return nil, err
+		}
+
+		card := outletQRCard{
+			Name: outlet.Name,
+			URL:  url,
+			// Gambar disisipkan sebagai data URI supaya wkhtmltopdf tidak perlu file sementara
+			Image: template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png)),
+		}
+		if outlet.Code != nil {
+			card.Code = *outlet.Code
+		}
+
+		if i%outletQRSheetColumns == 0 {
+			rows = append(rows, []outletQRCard{})
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], card)
+	}
+
+	tmpl, err := template.ParseFiles(outletQRSheetPath)
+	if err != nil {
+		return nil, err
+	}
+	html := new(bytes.Buffer)
+	err = tmpl.Execute(html, map[string]interface{}{
+		"Title": "Outlet QR Codes - " + time.Now().Format("02 Jan 2006"),
+		"Rows":  rows,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return thirdparty.GeneratePdfBytes(html.String())
+}
+
+// GetPublicOutlet returns the public page data of an active outlet.
+func (s *OutletService) GetPublicOutlet(id uint) (*model.PublicOutletResponse, error) {
+	var outlet model.Outlet
+	err := preloadOutletSchedule(database.DbCore, time.Now()).
+		Where("status = ?", "active").
+		First(&outlet, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOutletNotPublic
+		}
+		return nil, err
+	}
+
+	response := outlet.ToPublicResponse()
+	return &response, nil
+}
@@ -2,46 +2,190 @@ package service
 
 import (
 	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
 	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
 	"errors"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type OutletService struct{}
+type OutletService struct {
+    geocoder thirdparty.Geocoder
+}
 
 func NewOutletService() *OutletService {
-    return &OutletService{}
+    return &OutletService{
+        geocoder: thirdparty.NewGeocoder(),
+    }
 }
 
-func (s *OutletService) GetAllOutlets(search string) ([]model.OutletResponse, error) {
-    var outlets []model.Outlet
-    query := database.DbCore
+// NewOutletServiceWithGeocoder lets callers plug in their own geocoder (e.g. thirdparty.StubGeocoder).
+func NewOutletServiceWithGeocoder(geocoder thirdparty.Geocoder) *OutletService {
+    return &OutletService{
+        geocoder: geocoder,
+    }
+}
+
+const (
+    defaultNearbyRadiusKm = 5.0
+)
+
+// Kolom yang boleh dipakai untuk sorting, dipetakan dari nama field di API
+var outletSortColumns = map[string]string{
+    "name":      "name",
+    "createdAt": "created_at",
+    "status":    "status",
+}
 
-    if search != "" {
-        searchTerm := "%" + strings.ToLower(search) + "%"
-        query = query.Where(
-            "LOWER(name) LIKE ? OR LOWER(address) LIKE ? OR LOWER(manager) LIKE ?",
-            searchTerm, searchTerm, searchTerm,
-        )
+const (
+    defaultOutletPageSize = 20
+    // innodb_ft_min_token_size bawaan MySQL, kata yang lebih pendek tidak masuk index FULLTEXT
+    fullTextMinTokenSize = 3
+)
+
+func (s *OutletService) GetAllOutlets(params model.OutletListQuery, scope model.OutletScope) (*model.OutletListResult, error) {
+    query, err := filterOutlets(params, scope)
+    if err != nil {
+        return nil, err
+    }
+
+    // Jam buka dihitung di Go per zona waktu outlet, jadi filter openAt
+    // dilakukan setelah data diambil dan paginasinya ikut dikerjakan di memori.
+    // Hari khusus dimuat untuk openAt dan juga sekarang (isOpenNow di response).
+    now := time.Now()
+    referenceTime := now
+    if params.OpenAt != "" {
+        openAt, err := time.Parse(time.RFC3339, params.OpenAt)
+        if err != nil {
+            return nil, err
+        }
+        referenceTime = openAt
+    }
+    query = preloadOutletSchedule(query, referenceTime, now)
+
+    var total int64
+    if params.OpenAt == "" {
+        if err := query.Count(&total).Error; err != nil {
+            return nil, err
+        }
     }
 
+    page, pageSize := params.Page, params.PageSize
+    if page < 1 {
+        page = 1
+    }
+    if pageSize < 1 {
+        pageSize = defaultOutletPageSize
+    }
+
+    sortColumn, ok := outletSortColumns[params.SortBy]
+    if !ok {
+        sortColumn = "created_at"
+    }
+    sortOrder := "ASC"
+    if params.SortOrder == "desc" {
+        sortOrder = "DESC"
+    }
+
+    query = query.Order(sortColumn + " " + sortOrder).Order("id " + sortOrder)
+    if params.OpenAt == "" {
+        query = query.Offset((page - 1) * pageSize).Limit(pageSize)
+    }
+
+    var outlets []model.Outlet
     if err := query.Find(&outlets).Error; err != nil {
         return nil, err
     }
 
-    var responses []model.OutletResponse
+    if params.OpenAt != "" {
+        outlets = filterOpenOutlets(outlets, referenceTime)
+        total = int64(len(outlets))
+        outlets = paginateOutlets(outlets, page, pageSize)
+    }
+
+    responses := []model.OutletResponse{}
     for _, outlet := range outlets {
         responses = append(responses, outlet.ToResponse())
     }
 
-    return responses, nil
+    return &model.OutletListResult{
+        Outlets: responses,
+        Pagination: model.Pagination{
+            Page:       page,
+            PageSize:   pageSize,
+            Total:      total,
+            TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+        },
+    }, nil
+}
+
+// filterOutlets applies the search/status/manager/region filters shared by the
+// listing and the Excel export.
+func filterOutlets(params model.OutletListQuery, scope model.OutletScope) (*gorm.DB, error) {
+    query := scopeOutlets(database.DbCore.Model(&model.Outlet{}), scope)
+
+    if params.Search != "" {
+        if against, ok := outletFullTextQuery(params.Search); ok {
+            query = query.Where("MATCH(name, address, manager) AGAINST (? IN BOOLEAN MODE)", against)
+        } else {
+            searchTerm := "%" + strings.ToLower(params.Search) + "%"
+            query = query.Where(
+                "LOWER(name) LIKE ? OR LOWER(address) LIKE ? OR LOWER(manager) LIKE ?",
+                searchTerm, searchTerm, searchTerm,
+            )
+        }
+    }
+    if params.Status != "" {
+        query = query.Where("status = ?", params.Status)
+    }
+    if params.Manager != "" {
+        query = query.Where("manager = ?", params.Manager)
+    }
+    if params.RegionID != 0 {
+        regionIDs, err := NewRegionService().SubtreeIDs([]uint{params.RegionID})
+        if err != nil {
+            return nil, err
+        }
+        query = query.Where("region_id IN ?", regionIDs)
+    }
+    return query, nil
+}
+
+// outletFullTextQuery turns free text into a boolean-mode query where every word
+// must match as a prefix. It reports false when a word is too short for the
+// FULLTEXT index, in which case the caller falls back to LIKE.
+func outletFullTextQuery(search string) (string, bool) {
+    var terms []string
+    for _, word := range strings.Fields(search) {
+        word = strings.Map(func(r rune) rune {
+            if strings.ContainsRune(`+-<>()~*"@`, r) {
+                return -1
+            }
+            return r
+        }, word)
+        if word == "" {
+            continue
+        }
+        if len([]rune(word)) < fullTextMinTokenSize {
+            return "", false
+        }
+        terms = append(terms, "+"+word+"*")
+    }
+    if len(terms) == 0 {
+        return "", false
+    }
+    return strings.Join(terms, " "), true
 }
 
 func (s *OutletService) GetOutletByID(id uint) (*model.OutletResponse, error) {
     var outlet model.Outlet
-    if err := database.DbCore.First(&outlet, id).Error; err != nil {
+    if err := preloadOutletSchedule(database.DbCore, time.Now()).First(&outlet, id).Error; err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
             return nil, errors.New("outlet not found")
         }
@@ -52,17 +196,16 @@ func (s *OutletService) GetOutletByID(id uint) (*model.OutletResponse, error) {
     return &response, nil
 }
 
-func (s *OutletService) CreateOutlet(req model.OutletRequest) (*model.OutletResponse, error) {
-    outlet := model.Outlet{
-        Name:      req.Name,
-        Address:   req.Address,
-        Phone:     req.Phone,
-        Manager:   req.Manager,
-        Status:    req.Status,
-        OpenHours: req.OpenHours,
-    }
+func (s *OutletService) CreateOutlet(req model.OutletRequest, actorID string) (*model.OutletResponse, error) {
+    outlet := s.newOutlet(req)
 
-    if err := database.DbCore.Create(&outlet).Error; err != nil {
+    err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&outlet).Error; err != nil {
+            return err
+        }
+        return RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(outlet.ID), model.AuditActionCreate, actorID, nil, outlet)
+    })
+    if err != nil {
         return nil, err
     }
 
@@ -70,31 +213,154 @@ func (s *OutletService) CreateOutlet(req model.OutletRequest) (*model.OutletResp
     return &response, nil
 }
 
-func (s *OutletService) UpdateOutlet(id uint, req model.OutletRequest) (*model.OutletResponse, error) {
+// UpdateOutlet replaces an outlet when it is still at expectedVersion.
+func (s *OutletService) UpdateOutlet(id uint, req model.OutletRequest, expectedVersion uint, actorID string) (*model.OutletResponse, error) {
     var outlet model.Outlet
-    if err := database.DbCore.First(&outlet, id).Error; err != nil {
+    if err := preloadOutletSchedule(database.DbCore, time.Now()).First(&outlet, id).Error; err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
             return nil, errors.New("outlet not found")
         }
         return nil, err
     }
+    if outlet.Version != expectedVersion {
+        return nil, ErrVersionConflict
+    }
+    before := outlet
+
+    s.applyOutletRequest(&outlet, req)
+    outlet.Version = expectedVersion + 1
+
+    err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+        // Versi dicek lagi di WHERE supaya dua update yang berbarengan tidak saling menimpa
+        result := tx.Model(&outlet).Where("version = ?", expectedVersion).
+            Select("*").Omit(clause.Associations, "created_at", "deleted_at").Updates(&outlet)
+        if result.Error != nil {
+            return result.Error
+        }
+        if result.RowsAffected == 0 {
+            return ErrVersionConflict
+        }
+        if req.Hours != nil {
+            if err := replaceOutletHours(tx, outlet.ID, outlet.Hours); err != nil {
+                return err
+            }
+        }
+        return RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(outlet.ID), model.AuditActionUpdate, actorID, before, outlet)
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    response := outlet.ToResponse()
+    return &response, nil
+}
+
+// newOutlet builds a new outlet from a request, geocoding the address when
+// no coordinates were given.
+func (s *OutletService) newOutlet(req model.OutletRequest) model.Outlet {
+    outlet := model.Outlet{
+        Code:      req.Code,
+        Name:      req.Name,
+        Address:   req.Address,
+        Phone:     req.Phone,
+        Manager:   req.Manager,
+        Status:    req.Status,
+        RegionID:  req.RegionID,
+        OpenHours: req.OpenHours,
+        TimeZone:  outletTimeZone(req.TimeZone),
+        Hours:     model.ToOutletHours(req.Hours),
+        Latitude:  req.Latitude,
+        Longitude: req.Longitude,
+        Version:   1,
+    }
+    if outlet.Latitude == nil {
+        s.geocodeOutlet(&outlet)
+    }
+    return outlet
+}
+
+// applyOutletRequest copies a full request onto an existing outlet.
+func (s *OutletService) applyOutletRequest(outlet *model.Outlet, req model.OutletRequest) {
+    // Koordinat ikut dihitung ulang kalau alamat berubah dan tidak dikirim manual
+    if req.Latitude != nil {
+        outlet.Latitude, outlet.Longitude = req.Latitude, req.Longitude
+    } else if req.Address != outlet.Address {
+        outlet.Latitude, outlet.Longitude = nil, nil
+        outlet.Address = req.Address
+        s.geocodeOutlet(outlet)
+    }
 
+    // Code yang tidak dikirim tidak menghapus kode lama
+    if req.Code != nil {
+        outlet.Code = req.Code
+    }
     outlet.Name = req.Name
     outlet.Address = req.Address
     outlet.Phone = req.Phone
     outlet.Manager = req.Manager
     outlet.Status = req.Status
+    outlet.RegionID = req.RegionID
     outlet.OpenHours = req.OpenHours
+    outlet.TimeZone = outletTimeZone(req.TimeZone)
+    // Hours yang tidak dikirim berarti jadwal lama tetap dipakai
+    if req.Hours != nil {
+        outlet.Hours = model.ToOutletHours(req.Hours)
+    }
+}
 
-    if err := database.DbCore.Save(&outlet).Error; err != nil {
+// PatchOutlet applies only the fields present in req on top of the stored outlet.
+func (s *OutletService) PatchOutlet(id uint, req model.OutletPatchRequest, expectedVersion uint, actorID string) (*model.OutletResponse, error) {
+    var outlet model.Outlet
+    if err := database.DbCore.First(&outlet, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("outlet not found")
+        }
         return nil, err
     }
 
-    response := outlet.ToResponse()
-    return &response, nil
+    merged := model.OutletRequest{
+        Code:      req.Code,
+        Name:      outlet.Name,
+        Address:   outlet.Address,
+        Latitude:  req.Latitude,
+        Longitude: req.Longitude,
+        Phone:     outlet.Phone,
+        Manager:   outlet.Manager,
+        Status:    outlet.Status,
+        RegionID:  outlet.RegionID,
+        OpenHours: outlet.OpenHours,
+        TimeZone:  outlet.TimeZone,
+        Hours:     req.Hours,
+    }
+    if req.Name != nil {
+        merged.Name = *req.Name
+    }
+    if req.Address != nil {
+        merged.Address = *req.Address
+    }
+    if req.Phone != nil {
+        merged.Phone = *req.Phone
+    }
+    if req.Manager != nil {
+        merged.Manager = *req.Manager
+    }
+    if req.Status != nil {
+        merged.Status = *req.Status
+    }
+    if req.RegionID != nil {
+        merged.RegionID = req.RegionID
+    }
+    if req.OpenHours != nil {
+        merged.OpenHours = *req.OpenHours
+    }
+    if req.TimeZone != nil {
+        merged.TimeZone = *req.TimeZone
+    }
+
+    return s.UpdateOutlet(id, merged, expectedVersion, actorID)
 }
 
-func (s *OutletService) DeleteOutlet(id uint) error {
+func (s *OutletService) DeleteOutlet(id uint, actorID string) error {
     var outlet model.Outlet
     if err := database.DbCore.First(&outlet, id).Error; err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -103,34 +369,540 @@ func (s *OutletService) DeleteOutlet(id uint) error {
         return err
     }
 
-    return database.DbCore.Delete(&outlet).Error
+    return database.DbCore.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Delete(&outlet).Error; err != nil {
+            return err
+        }
+        return RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(outlet.ID), model.AuditActionDelete, actorID, outlet, nil)
+    })
+}
+
+// SaveSpecialDay creates or replaces the schedule override of one date.
+func (s *OutletService) SaveSpecialDay(id uint, req model.OutletSpecialDayRequest, actorID string) (*model.OutletSpecialDay, error) {
+    var outlet model.Outlet
+    if err := database.DbCore.First(&outlet, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("outlet not found")
+        }
+        return nil, err
+    }
+
+    intervals := req.Intervals
+    if req.Closed {
+        intervals = []model.OpeningInterval{}
+    }
+
+    var specialDay model.OutletSpecialDay
+    err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+        result := tx.Where("outlet_id = ? AND date = ?", id, req.Date).Limit(1).Find(&specialDay)
+        if result.Error != nil {
+            return result.Error
+        }
+        before := map[string]interface{}{}
+        if result.RowsAffected > 0 {
+            before = auditSpecialDay(specialDay)
+        }
+
+        specialDay.OutletID = id
+        specialDay.Date = req.Date
+        specialDay.Closed = req.Closed
+        specialDay.Description = req.Description
+        specialDay.Intervals = intervals
+        if err := tx.Save(&specialDay).Error; err != nil {
+            return err
+        }
+
+        return RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(id), model.AuditActionUpdate, actorID,
+            before, auditSpecialDay(specialDay))
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &specialDay, nil
+}
+
+func (s *OutletService) DeleteSpecialDay(id uint, dayID uint, actorID string) error {
+    var specialDay model.OutletSpecialDay
+    if err := database.DbCore.Where("outlet_id = ?", id).First(&specialDay, dayID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return errors.New("special day not found")
+        }
+        return err
+    }
+
+    return database.DbCore.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Delete(&specialDay).Error; err != nil {
+            return err
+        }
+        return RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(id), model.AuditActionUpdate, actorID,
+            auditSpecialDay(specialDay), nil)
+    })
+}
+
+// GetNearbyOutlets returns active outlets within radiusKm, closest first.
+func (s *OutletService) GetNearbyOutlets(lat, lng, radiusKm float64, scope model.OutletScope) ([]model.NearbyOutlet, error) {
+    if radiusKm <= 0 {
+        radiusKm = defaultNearbyRadiusKm
+    }
+    minLat, maxLat, minLng, maxLng := boundingBox(lat, lng, radiusKm)
+
+    var outlets []model.Outlet
+    err := preloadOutletSchedule(scopeOutlets(database.DbCore, scope), time.Now()).
+        Where("status = ?", "active").
+        Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?", minLat, maxLat, minLng, maxLng).
+        Find(&outlets).Error
+    if err != nil {
+        return nil, err
+    }
+
+    nearby := []model.NearbyOutlet{}
+    for _, outlet := range outlets {
+        distance := haversineKm(lat, lng, *outlet.Latitude, *outlet.Longitude)
+        if distance <= radiusKm {
+            nearby = append(nearby, model.NearbyOutlet{OutletResponse: outlet.ToResponse(), DistanceKm: distance})
+        }
+    }
+    sortNearbyOutlets(nearby)
+    return nearby, nil
+}
+
+// GetServingOutlets returns the active outlets whose delivery area contains the point, closest first.
+func (s *OutletService) GetServingOutlets(lat, lng float64, scope model.OutletScope) ([]model.NearbyOutlet, error) {
+    var areas []model.OutletDeliveryArea
+    if err := database.DbCore.Where("active = ?", true).Find(&areas).Error; err != nil {
+        return nil, err
+    }
+
+    point := model.GeoPoint{Lat: lat, Lng: lng}
+    outletIDs := []uint{}
+    seen := map[uint]bool{}
+    for _, area := range areas {
+        if !seen[area.OutletID] && pointInPolygon(point, area.Polygon) {
+            seen[area.OutletID] = true
+            outletIDs = append(outletIDs, area.OutletID)
+        }
+    }
+
+    serving := []model.NearbyOutlet{}
+    if len(outletIDs) == 0 {
+        return serving, nil
+    }
+
+    var outlets []model.Outlet
+    err := preloadOutletSchedule(scopeOutlets(database.DbCore, scope), time.Now()).
+        Where("id IN ? AND status = ?", outletIDs, "active").
+        Find(&outlets).Error
+    if err != nil {
+        return nil, err
+    }
+
+    for _, outlet := range outlets {
+        item := model.NearbyOutlet{OutletResponse: outlet.ToResponse()}
+        if outlet.Latitude != nil && outlet.Longitude != nil {
+            item.DistanceKm = haversineKm(lat, lng, *outlet.Latitude, *outlet.Longitude)
+        }
+        serving = append(serving, item)
+    }
+    sortNearbyOutlets(serving)
+    return serving, nil
+}
+
+func (s *OutletService) GetDeliveryAreas(id uint) ([]model.OutletDeliveryArea, error) {
+    areas := []model.OutletDeliveryArea{}
+    if err := database.DbCore.Where("outlet_id = ?", id).Order("id").Find(&areas).Error; err != nil {
+        return nil, err
+    }
+    return areas, nil
+}
+
+func (s *OutletService) CreateDeliveryArea(id uint, req model.DeliveryAreaRequest, actorID string) (*model.OutletDeliveryArea, error) {
+    var outlet model.Outlet
+    if err := database.DbCore.First(&outlet, id).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, errors.New("outlet not found")
+        }
+        return nil, err
+    }
+
+    area := model.OutletDeliveryArea{
+        OutletID: id,
+        Name:     req.Name,
+        Polygon:  req.Polygon,
+        Active:   req.Active == nil || *req.Active,
+    }
+    err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Create(&area).Error; err != nil {
+            return err
+        }
+        return RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(id), model.AuditActionUpdate, actorID,
+            nil, auditDeliveryArea(area))
+    })
+    if err != nil {
+        return nil, err
+    }
+    return &area, nil
+}
+
+func (s *OutletService) DeleteDeliveryArea(id uint, areaID uint, actorID string) error {
+    var area model.OutletDeliveryArea
+    if err := database.DbCore.Where("outlet_id = ?", id).First(&area, areaID).Error; err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return errors.New("delivery area not found")
+        }
+        return err
+    }
+
+    return database.DbCore.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Delete(&area).Error; err != nil {
+            return err
+        }
+        return RecordAudit(tx, model.AuditEntityOutlet, outletEntityID(id), model.AuditActionUpdate, actorID,
+            auditDeliveryArea(area), nil)
+    })
+}
+
+func (s *OutletService) GetOutletHistory(id uint) ([]model.AuditLog, error) {
+    return NewAuditService().GetHistory(model.AuditEntityOutlet, outletEntityID(id))
+}
+
+func outletEntityID(id uint) string {
+    return strconv.FormatUint(uint64(id), 10)
+}
+
+// geocodeOutlet fills the coordinates from the address. A failing geocoder is
+// not fatal, the outlet is simply saved without a location.
+func (s *OutletService) geocodeOutlet(outlet *model.Outlet) {
+    if s.geocoder == nil {
+        return
+    }
+    lat, lng, err := s.geocoder.Geocode(outlet.Address)
+    if err != nil {
+        middleware.LogError(err, "Failed to geocode outlet address")
+        return
+    }
+    outlet.Latitude, outlet.Longitude = &lat, &lng
+}
+
+func sortNearbyOutlets(outlets []model.NearbyOutlet) {
+    sort.SliceStable(outlets, func(i, j int) bool {
+        if outlets[i].DistanceKm != outlets[j].DistanceKm {
+            return outlets[i].DistanceKm < outlets[j].DistanceKm
+        }
+        return outlets[i].ID < outlets[j].ID
+    })
+}
+
+func auditDeliveryArea(area model.OutletDeliveryArea) map[string]interface{} {
+    return map[string]interface{}{
+        "deliveryAreas." + strconv.FormatUint(uint64(area.ID), 10): map[string]interface{}{
+            "name":    area.Name,
+            "polygon": area.Polygon,
+            "active":  area.Active,
+        },
+    }
+}
+
+// OutletInScope reports whether the outlet exists and is visible in the scope.
+func (s *OutletService) OutletInScope(id uint, scope model.OutletScope) (bool, error) {
+    if !scope.Restricted {
+        return true, nil
+    }
+    var count int64
+    err := scopeOutlets(database.DbCore.Model(&model.Outlet{}), scope).Where("id = ?", id).Count(&count).Error
+    return count > 0, err
+}
+
+func scopeOutlets(db *gorm.DB, scope model.OutletScope) *gorm.DB {
+    if !scope.Restricted {
+        return db
+    }
+    if len(scope.RegionIDs) == 0 {
+        return db.Where("1 = 0")
+    }
+    return db.Where("outlets.region_id IN ?", scope.RegionIDs)
+}
+
+func outletTimeZone(timeZone string) string {
+    if timeZone == "" {
+        return model.DefaultOutletTimeZone
+    }
+    return timeZone
+}
+
+// preloadOutletSchedule loads weekly hours and the special days that can still
+// affect whether the outlet is open at any of the given instants. Pass every
+// instant the outlets are evaluated at, e.g. both openAt and now for isOpenNow.
+func preloadOutletSchedule(db *gorm.DB, instants ...time.Time) *gorm.DB {
+    earliest := time.Now()
+    for _, instant := range instants {
+        if instant.Before(earliest) {
+            earliest = instant
+        }
+    }
+    since := earliest.AddDate(0, 0, -2).Format("2006-01-02")
+    return db.
+        Preload("Hours", func(tx *gorm.DB) *gorm.DB {
+            return tx.Order("weekday, open")
+        }).
+        Preload("SpecialDays", func(tx *gorm.DB) *gorm.DB {
+            return tx.Where("date >= ?", since).Order("date")
+        })
+}
+
+func replaceOutletHours(tx *gorm.DB, outletID uint, hours []model.OutletHours) error {
+    if err := tx.Where("outlet_id = ?", outletID).Delete(&model.OutletHours{}).Error; err != nil {
+        return err
+    }
+    if len(hours) == 0 {
+        return nil
+    }
+    for i := range hours {
+        hours[i].ID = 0
+        hours[i].OutletID = outletID
+    }
+    return tx.Create(&hours).Error
+}
+
+func filterOpenOutlets(outlets []model.Outlet, at time.Time) []model.Outlet {
+    open := []model.Outlet{}
+    for _, outlet := range outlets {
+        if outlet.IsOpenAt(at) {
+            open = append(open, outlet)
+        }
+    }
+    return open
+}
+
+func paginateOutlets(outlets []model.Outlet, page, pageSize int) []model.Outlet {
+    start := (page - 1) * pageSize
+    if start >= len(outlets) {
+        return []model.Outlet{}
+    }
+    end := start + pageSize
+    if end > len(outlets) {
+        end = len(outlets)
+    }
+    return outlets[start:end]
+}
+
+// auditSpecialDay keys the override by its date so the outlet history shows
+// e.g. "specialDays.2025-12-25" as the changed field.
+func auditSpecialDay(day model.OutletSpecialDay) map[string]interface{} {
+    return map[string]interface{}{
+        "specialDays." + day.Date: map[string]interface{}{
+            "closed":      day.Closed,
+            "description": day.Description,
+            "intervals":   day.Intervals,
+        },
+    }
 }
 
-func (s *OutletService) GetOutletStats() (map[string]interface{}, error) {
-    var totalOutlets int64
-    var activeOutlets int64
-    var inactiveOutlets int64
+var ErrInvalidStatsRange = errors.New("from must not be after to")
 
-    
-    if err := database.DbCore.Model(&model.Outlet{}).Count(&totalOutlets).Error; err != nil {
+// outletStatsRow is one group of the single aggregate query behind the stats.
+type outletStatsRow struct {
+    RegionID *uint
+    Manager  string
+    Status   string
+    Month    string
+    Total    int64
+}
+
+// GetOutletStats aggregates the outlets created in the requested range. All
+// breakdowns (status, region, manager, month) come from one grouped query.
+func (s *OutletService) GetOutletStats(params model.OutletStatsQuery, scope model.OutletScope) (*model.OutletStats, error) {
+    outlets, err := statsOutlets(params, scope)
+    if err != nil {
         return nil, err
     }
 
-    
-    if err := database.DbCore.Model(&model.Outlet{}).Where("status = ?", "active").Count(&activeOutlets).Error; err != nil {
+    var rows []outletStatsRow
+    err = outlets().
+        Select("region_id, manager, status, DATE_FORMAT(outlets.created_at, '%Y-%m') AS month, COUNT(*) AS total").
+        Group("region_id, manager, status, month").
+        Scan(&rows).Error
+    if err != nil {
         return nil, err
     }
 
-    
-    if err := database.DbCore.Model(&model.Outlet{}).Where("status = ?", "inactive").Count(&inactiveOutlets).Error; err != nil {
+    stats := &model.OutletStats{
+        From:     params.From,
+        To:       params.To,
+        ByStatus: map[string]int64{},
+    }
+    managers := map[string]*model.ManagerStats{}
+    months := map[string]int64{}
+    for _, row := range rows {
+        stats.Total += row.Total
+        stats.ByStatus[row.Status] += row.Total
+
+        manager, ok := managers[row.Manager]
+        if !ok {
+            manager = &model.ManagerStats{Manager: row.Manager}
+            managers[row.Manager] = manager
+        }
+        manager.Total += row.Total
+        if row.Status == "active" {
+            manager.Active += row.Total
+        } else if row.Status == "inactive" {
+            manager.Inactive += row.Total
+        }
+
+        months[row.Month] += row.Total
+    }
+    stats.Active = stats.ByStatus["active"]
+    stats.Inactive = stats.ByStatus["inactive"]
+
+    stats.ByManager = []model.ManagerStats{}
+    for _, manager := range managers {
+        stats.ByManager = append(stats.ByManager, *manager)
+    }
+    sort.Slice(stats.ByManager, func(i, j int) bool {
+        if stats.ByManager[i].Total != stats.ByManager[j].Total {
+            return stats.ByManager[i].Total > stats.ByManager[j].Total
+        }
+        return stats.ByManager[i].Manager < stats.ByManager[j].Manager
+    })
+
+    stats.CreatedPerMonth = []model.MonthlyCount{}
+    for month, count := range months {
+        stats.CreatedPerMonth = append(stats.CreatedPerMonth, model.MonthlyCount{Month: month, Count: count})
+    }
+    sort.Slice(stats.CreatedPerMonth, func(i, j int) bool {
+        return stats.CreatedPerMonth[i].Month < stats.CreatedPerMonth[j].Month
+    })
+
+    if stats.ByRegion, err = regionRollup(rows, scope); err != nil {
+        return nil, err
+    }
+    if stats.Incomplete, err = incompleteOutlets(outlets()); err != nil {
         return nil, err
     }
 
-    stats := map[string]interface{}{
-        "total":    totalOutlets,
-        "active":   activeOutlets,
-        "inactive": inactiveOutlets,
+    stats.StaffPerOutlet = []model.OutletStaffCount{}
+    err = outlets().
+        Select("outlets.id AS outlet_id, outlets.name, COUNT(users.id) AS staff").
+        Joins("LEFT JOIN users ON users.outlet_id = outlets.id AND users.deleted_at IS NULL").
+        Group("outlets.id, outlets.name").
+        Order("staff DESC, outlets.name").
+        Scan(&stats.StaffPerOutlet).Error
+    if err != nil {
+        return nil, err
     }
 
     return stats, nil
+}
+
+// statsOutlets returns a builder for the scoped outlets created inside the range;
+// every call starts a fresh query.
+func statsOutlets(params model.OutletStatsQuery, scope model.OutletScope) (func() *gorm.DB, error) {
+    var from, to *time.Time
+    if params.From != "" {
+        t, err := time.ParseInLocation("2006-01-02", params.From, time.Local)
+        if err != nil {
+            return nil, err
+        }
+        from = &t
+    }
+    if params.To != "" {
+        t, err := time.ParseInLocation("2006-01-02", params.To, time.Local)
+        if err != nil {
+            return nil, err
+        }
+        // Tanggal akhir inklusif, jadi batasnya awal hari berikutnya
+        t = t.AddDate(0, 0, 1)
+        to = &t
+    }
+    if from != nil && to != nil && !from.Before(*to) {
+        return nil, ErrInvalidStatsRange
+    }
+
+    return func() *gorm.DB {
+        query := scopeOutlets(database.DbCore.Model(&model.Outlet{}), scope)
+        if from != nil {
+            query = query.Where("outlets.created_at >= ?", *from)
+        }
+        if to != nil {
+            query = query.Where("outlets.created_at < ?", *to)
+        }
+        return query
+    }, nil
+}
+
+// incompleteOutlets lists outlets without a code, region, coordinates or weekly schedule.
+func incompleteOutlets(query *gorm.DB) ([]model.IncompleteOutlet, error) {
+    var outlets []model.Outlet
+    err := query.
+        Select("id, code, name, region_id, latitude, longitude").
+        Where("code IS NULL OR region_id IS NULL OR latitude IS NULL OR longitude IS NULL OR NOT EXISTS (SELECT 1 FROM outlet_hours WHERE outlet_hours.outlet_id = outlets.id)").
+        Preload("Hours").
+        Order("name").
+        Find(&outlets).Error
+    if err != nil {
+        return nil, err
+    }
+
+    result := []model.IncompleteOutlet{}
+    for _, outlet := range outlets {
+        missing := []string{}
+        if outlet.Code == nil {
+            missing = append(missing, "code")
+        }
+        if outlet.RegionID == nil {
+            missing = append(missing, "regionId")
+        }
+        if outlet.Latitude == nil || outlet.Longitude == nil {
+            missing = append(missing, "coordinates")
+        }
+        if len(outlet.Hours) == 0 {
+            missing = append(missing, "hours")
+        }
+        result = append(result, model.IncompleteOutlet{ID: outlet.ID, Name: outlet.Name, Missing: missing})
+    }
+    return result, nil
+}
+
+// regionRollup counts outlets per region, every region including the outlets
+// of its sub-regions.
+func regionRollup(rows []outletStatsRow, scope model.OutletScope) ([]model.RegionStats, error) {
+    var regions []model.Region
+    regionQuery := database.DbCore.Order("brand_id, name")
+    if scope.Restricted {
+        regionQuery = regionQuery.Where("id IN ?", append(scope.RegionIDs, 0))
+    }
+    if err := regionQuery.Find(&regions).Error; err != nil {
+        return nil, err
+    }
+
+    parents := map[uint]*uint{}
+    stats := map[uint]*model.RegionStats{}
+    for _, region := range regions {
+        parents[region.ID] = region.ParentID
+        stats[region.ID] = &model.RegionStats{RegionID: region.ID, ParentID: region.ParentID, BrandID: region.BrandID, Name: region.Name}
+    }
+
+    for _, row := range rows {
+        if row.RegionID == nil {
+            continue
+        }
+        // Naik terus ke parent supaya angka region induk ikut terhitung
+        for id := row.RegionID; id != nil; id = parents[*id] {
+            item, ok := stats[*id]
+            if !ok {
+                break
+            }
+            item.Total += row.Total
+            if row.Status == "active" {
+                item.Active += row.Total
+            } else if row.Status == "inactive" {
+                item.Inactive += row.Total
+            }
+        }
+    }
+
+    result := []model.RegionStats{}
+    for _, region := range regions {
+        result = append(result, *stats[region.ID])
+    }
+    return result, nil
 }
\ No newline at end of file
@@ -0,0 +1,605 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var (
+	ErrPayrollRunNotFound  = errors.New("payroll run not found")
+	ErrPayrollRunExists    = errors.New("payroll for this period already covers these employees")
+	ErrPayrollRunLocked    = errors.New("payroll run is locked")
+	ErrPayrollRunNotLocked = errors.New("payroll run must be locked first")
+	ErrPayslipNotFound     = errors.New("payslip not found")
+)
+
+const (
+	payslipTemplatePath    = "./web/html/payslip.html"
+	payrollEmailTemplate   = "./web/html/email_template.html"
+	defaultPayrollPageSize = 20
+
+	// Upah lembur per jam = 1/173 gaji bulanan (Kepmenakertrans 102/2004); jam
+	// pertama dibayar 1,5x dan jam berikutnya 2x
+	overtimeHoursDivisor = 173
+)
+
+var PayrollSheetName = "Payroll"
+
+var payrollExcelHeaders = []thirdparty.Header{
+	{Text: "Employee Code", Width: 15},
+	{Text: "Name", Width: 30},
+	{Text: "Outlet ID", Width: 10},
+	{Text: "Position", Width: 20},
+	{Text: "Base Salary", Width: 15},
+	{Text: "Allowances", Width: 15},
+	{Text: "Overtime Hours", Width: 15},
+	{Text: "Overtime Pay", Width: 15},
+	{Text: "Gross Pay", Width: 15},
+	{Text: "Deductions", Width: 15},
+	{Text: "Net Pay", Width: 15},
+	{Text: "Bank", Width: 15},
+	{Text: "Account Number", Width: 20},
+}
+
+type PayrollService struct{}
+
+func NewPayrollService() *PayrollService {
+	return &PayrollService{}
+}
+
+func (s *PayrollService) GetRuns(params model.PayrollRunListQuery) (*model.PayrollRunListResult, error) {
+	query := database.DbCore.Model(&model.PayrollRun{})
+	if params.Year != 0 {
+		query = query.Where("period LIKE ?", fmt.Sprintf("%04d-%%", params.Year))
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPayrollPageSize
+	}
+
+	runs := []model.PayrollRun{}
+	err := query.Order("period DESC").Order("outlet_id ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PayrollRunListResult{
+		Runs: runs,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *PayrollService) GetRun(id uint) (*model.PayrollRun, error) {
+	return findPayrollRun(database.DbCore.Preload("Payslips", func(db *gorm.DB) *gorm.DB {
+		return db.Order("employee_name ASC")
+	}), id)
+}
+
+func (s *PayrollService) GetPayslip(runID, payslipID uint) (*model.Payslip, error) {
+	var payslip model.Payslip
+	err := database.DbCore.Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("run_id = ?", runID).
+		First(&payslip, payslipID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPayslipNotFound
+		}
+		return nil, err
+	}
+	return &payslip, nil
+}
+
+// CreateRun opens a draft payroll for the month and calculates it. One period can
+// have either a single run for all outlets or one run per outlet.
+func (s *PayrollService) CreateRun(req model.PayrollRunRequest, actorID string) (*model.PayrollRun, error) {
+	start, err := time.ParseInLocation("2006-01", req.Period, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	var run model.PayrollRun
+	err = database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if req.OutletID != 0 {
+			if err := checkOutletInScope(tx, req.OutletID, model.OutletScope{}); err != nil {
+				return err
+			}
+		}
+		query := tx.Model(&model.PayrollRun{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("period = ?", req.Period)
+		if req.OutletID != 0 {
+			query = query.Where("outlet_id IN ?", []uint{0, req.OutletID})
+		}
+		var existing int64
+		if err := query.Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return ErrPayrollRunExists
+		}
+
+		run = model.PayrollRun{
+			Period:      req.Period,
+			OutletID:    req.OutletID,
+			PeriodStart: start,
+			PeriodEnd:   start.AddDate(0, 1, -1),
+			Status:      model.PayrollDraft,
+			Note:        req.Note,
+			CreatedBy:   actorID,
+		}
+		if err := tx.Create(&run).Error; err != nil {
+			return err
+		}
+		return calculatePayroll(tx, &run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetRun(run.ID)
+}
+
+// RecalculateRun rebuilds the payslips of a draft run from the current employee
+// data and overtime.
+func (s *PayrollService) RecalculateRun(id uint) (*model.PayrollRun, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		run, err := lockDraftPayrollRun(tx, id)
+		if err != nil {
+			return err
+		}
+		return calculatePayroll(tx, run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetRun(id)
+}
+
+// LockRun finalises the run: its payslips no longer change and the overtime it
+// paid is marked so it cannot be edited or paid again. The run is recalculated
+// first, so overtime added after the last calculation is paid and not only marked.
+func (s *PayrollService) LockRun(id uint, actorID string) (*model.PayrollRun, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		run, err := lockDraftPayrollRun(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := calculatePayroll(tx, run); err != nil {
+			return err
+		}
+		var employeeIDs []uint
+		if err := tx.Model(&model.Payslip{}).Where("run_id = ?", run.ID).Pluck("employee_id", &employeeIDs).Error; err != nil {
+			return err
+		}
+		if len(employeeIDs) > 0 {
+			err := payrollOvertimeQuery(tx, run, employeeIDs).
+				Update("payroll_run_id", run.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(run).Updates(map[string]interface{}{
+			"status":    model.PayrollLocked,
+			"locked_by": actorID,
+			"locked_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetRun(id)
+}
+
+// DeleteRun removes a draft run and its payslips.
+func (s *PayrollService) DeleteRun(id uint) error {
+	return database.DbCore.Transaction(func(tx *gorm.DB) error {
+		run, err := lockDraftPayrollRun(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := deletePayslips(tx, run.ID); err != nil {
+			return err
+		}
+		return tx.Delete(run).Error
+	})
+}
+
+// PayslipPDF renders one payslip in memory for download.
+func (s *PayrollService) PayslipPDF(runID, payslipID uint) ([]byte, *model.Payslip, error) {
+	run, err := findPayrollRun(database.DbCore, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+	payslip, err := s.GetPayslip(runID, payslipID)
+	if err != nil {
+		return nil, nil, err
+	}
+	pdf, err := renderPayslipPDF(run, payslip)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pdf, payslip, nil
+}
+
+// SendPayslips emails every employee of a locked run their payslip PDF. Payslips
+// that were already sent are skipped unless resend is set; failed emails are
+// logged by the mail handler and can be retried by calling this again.
+func (s *PayrollService) SendPayslips(id uint, resend bool) (*model.PayslipEmailResult, error) {
+	run, err := findPayrollRun(database.DbCore, id)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != model.PayrollLocked {
+		return nil, ErrPayrollRunNotLocked
+	}
+	var payslips []model.Payslip
+	err = database.DbCore.Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("run_id = ?", run.ID).Order("employee_name ASC").
+		Find(&payslips).Error
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.ReadFile(payrollEmailTemplate)
+	if err != nil {
+		middleware.LogError(err, "Failed open HTML")
+		return nil, err
+	}
+
+	result := model.PayslipEmailResult{}
+	for i := range payslips {
+		payslip := &payslips[i]
+		if payslip.EmployeeEmail == "" || (payslip.EmailedAt != nil && !resend) {
+			result.Skipped++
+			continue
+		}
+		pdf, err := renderPayslipPDF(run, payslip)
+		if err != nil {
+			middleware.LogError(err, "Failed to render payslip "+payslip.EmployeeCode)
+			result.Failed++
+			continue
+		}
+		if !sendPayslipEmail(string(f), run, payslip, pdf) {
+			result.Failed++
+			continue
+		}
+		if err := database.DbCore.Model(payslip).Update("emailed_at", time.Now()).Error; err != nil {
+			return nil, err
+		}
+		result.Sent++
+	}
+	return &result, nil
+}
+
+// ExportRun writes the payroll summary of the run to an xlsx file for the
+// accountant and returns its path. The caller removes the file after sending it.
+func (s *PayrollService) ExportRun(id uint) (string, *model.PayrollRun, error) {
+	run, err := s.GetRun(id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rows := []map[string]interface{}{}
+	var overtimeMinutes int
+	var base, allowances, overtime int64
+	for _, payslip := range run.Payslips {
+		rows = append(rows, map[string]interface{}{
+			"Employee Code":  payslip.EmployeeCode,
+			"Name":           payslip.EmployeeName,
+			"Outlet ID":      payslip.OutletID,
+			"Position":       payslip.Position,
+			"Base Salary":    payslip.BaseSalary,
+			"Allowances":     payslip.Allowances,
+			"Overtime Hours": overtimeHours(payslip.OvertimeMinutes),
+			"Overtime Pay":   payslip.OvertimePay,
+			"Gross Pay":      payslip.GrossPay,
+			"Deductions":     payslip.Deductions,
+			"Net Pay":        payslip.NetPay,
+			"Bank":           payslip.BankName,
+			"Account Number": payslip.BankAccount,
+		})
+		overtimeMinutes += payslip.OvertimeMinutes
+		base += payslip.BaseSalary
+		allowances += payslip.Allowances
+		overtime += payslip.OvertimePay
+	}
+	rows = append(rows, map[string]interface{}{
+		"Employee Code":  "TOTAL",
+		"Base Salary":    base,
+		"Allowances":     allowances,
+		"Overtime Hours": overtimeHours(overtimeMinutes),
+		"Overtime Pay":   overtime,
+		"Gross Pay":      run.TotalGross,
+		"Deductions":     run.TotalDeductions,
+		"Net Pay":        run.TotalNet,
+	})
+
+	file, err := os.CreateTemp("", "payroll-*.xlsx")
+	if err != nil {
+		return "", nil, err
+	}
+	file.Close()
+
+	if !thirdparty.GenerateExcelFile(payrollExcelHeaders, rows, PayrollSheetName, file.Name()) {
+		os.Remove(file.Name())
+		return "", nil, errors.New("failed to generate payroll excel file")
+	}
+	return file.Name(), run, nil
+}
+
+// calculatePayroll replaces the run's payslips with one per employee who worked
+// part of the period, including those who left during it: base salary prorated
+// by the days employed, fixed components and the overtime of the period that no
+// locked run has paid yet. Inactive employees without an end date are skipped.
+func calculatePayroll(tx *gorm.DB, run *model.PayrollRun) error {
+	if err := deletePayslips(tx, run.ID); err != nil {
+		return err
+	}
+
+	query := tx.Preload("Components", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("join_date <= ?", run.PeriodEnd.Format("2006-01-02")).
+		Where("end_date IS NULL OR end_date >= ?", run.PeriodStart.Format("2006-01-02")).
+		Where("active = ? OR end_date IS NOT NULL", true)
+	if run.OutletID != 0 {
+		query = query.Where("outlet_id = ?", run.OutletID)
+	}
+	var employees []model.Employee
+	if err := query.Order("name ASC").Find(&employees).Error; err != nil {
+		return err
+	}
+
+	run.EmployeeCount, run.TotalGross, run.TotalDeductions, run.TotalNet = 0, 0, 0, 0
+	for _, employee := range employees {
+		var overtime []model.EmployeeOvertime
+		if err := payrollOvertimeQuery(tx, run, []uint{employee.ID}).Order("date ASC").Find(&overtime).Error; err != nil {
+			return err
+		}
+		payslip := buildPayslip(run, &employee, overtime)
+		if err := tx.Create(&payslip).Error; err != nil {
+			return err
+		}
+		run.EmployeeCount++
+		run.TotalGross += payslip.GrossPay
+		run.TotalDeductions += payslip.Deductions
+		run.TotalNet += payslip.NetPay
+	}
+
+	run.CalculatedAt = time.Now()
+	return tx.Model(run).Updates(map[string]interface{}{
+		"employee_count":   run.EmployeeCount,
+		"total_gross":      run.TotalGross,
+		"total_deductions": run.TotalDeductions,
+		"total_net":        run.TotalNet,
+		"calculated_at":    run.CalculatedAt,
+	}).Error
+}
+
+func buildPayslip(run *model.PayrollRun, employee *model.Employee, overtime []model.EmployeeOvertime) model.Payslip {
+	baseSalary, baseName := employee.BaseSalary, "Gaji pokok"
+	if worked, total := employedDays(run, employee); worked < total {
+		baseSalary = roundRupiah(float64(employee.BaseSalary) * float64(worked) / float64(total))
+		baseName = "Gaji pokok (" + strconv.Itoa(worked) + "/" + strconv.Itoa(total) + " hari)"
+	}
+	payslip := model.Payslip{
+		RunID:         run.ID,
+		EmployeeID:    employee.ID,
+		EmployeeCode:  employee.Code,
+		EmployeeName:  employee.Name,
+		EmployeeEmail: employee.Email,
+		OutletID:      employee.OutletID,
+		Position:      employee.Position,
+		BankName:      employee.BankName,
+		BankAccount:   employee.BankAccount,
+		BaseSalary:    baseSalary,
+		Lines: []model.PayslipLine{{
+			Type:   model.PayslipBase,
+			Name:   baseName,
+			Amount: baseSalary,
+		}},
+	}
+	for _, component := range employee.Components {
+		payslip.Lines = append(payslip.Lines, model.PayslipLine{
+			Type:   component.Type,
+			Name:   component.Name,
+			Amount: component.Amount,
+		})
+		if component.Type == model.SalaryDeduction {
+			payslip.Deductions += component.Amount
+		} else {
+			payslip.Allowances += component.Amount
+		}
+	}
+
+	for _, entry := range overtime {
+		payslip.OvertimeMinutes += entry.Minutes
+		payslip.OvertimePay += overtimePay(employee, entry.Minutes)
+	}
+	if payslip.OvertimeMinutes > 0 {
+		payslip.Lines = append(payslip.Lines, model.PayslipLine{
+			Type:   model.PayslipOvertime,
+			Name:   "Lembur " + strconv.FormatFloat(overtimeHours(payslip.OvertimeMinutes), 'f', -1, 64) + " jam",
+			Amount: payslip.OvertimePay,
+		})
+	}
+
+	payslip.GrossPay = payslip.BaseSalary + payslip.Allowances + payslip.OvertimePay
+	payslip.NetPay = payslip.GrossPay - payslip.Deductions
+	return payslip
+}
+
+// employedDays counts the calendar days of the run's period between the
+// employee's join date and end date, and the days in the whole period.
+func employedDays(run *model.PayrollRun, employee *model.Employee) (worked, total int) {
+	from, to := run.PeriodStart, run.PeriodEnd
+	if calendarDay(employee.JoinDate) > calendarDay(from) {
+		from = employee.JoinDate
+	}
+	if employee.EndDate != nil && calendarDay(*employee.EndDate) < calendarDay(to) {
+		to = *employee.EndDate
+	}
+	total = calendarDay(run.PeriodEnd) - calendarDay(run.PeriodStart) + 1
+	worked = calendarDay(to) - calendarDay(from) + 1
+	if worked < 0 {
+		worked = 0
+	}
+	return worked, total
+}
+
+// calendarDay numbers a date by days since the epoch, ignoring its time and
+// zone so DST and DATE columns read back in UTC compare correctly.
+func calendarDay(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// overtimePay pays one day of overtime: the first hour at 1.5x and the rest at 2x
+// the hourly rate.
+func overtimePay(employee *model.Employee, minutes int) int64 {
+	hourly := float64(employee.OvertimeRate)
+	if hourly == 0 {
+		hourly = float64(employee.BaseSalary) / overtimeHoursDivisor
+	}
+	hours := float64(minutes) / 60
+	first := math.Min(hours, 1)
+	return roundRupiah(hourly * (1.5*first + 2*(hours-first)))
+}
+
+func overtimeHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
+
+// payrollOvertimeQuery selects the overtime of the employees in the run's period
+// that is unpaid or paid by this run.
+func payrollOvertimeQuery(tx *gorm.DB, run *model.PayrollRun, employeeIDs []uint) *gorm.DB {
+	return tx.Model(&model.EmployeeOvertime{}).
+		Where("employee_id IN ? AND date >= ? AND date <= ?", employeeIDs,
+			run.PeriodStart.Format("2006-01-02"), run.PeriodEnd.Format("2006-01-02")).
+		Where("payroll_run_id IS NULL OR payroll_run_id = ?", run.ID)
+}
+
+// openPayrollDate returns date when no locked run covers the outlet's employees
+// in that month, otherwise the first day of the next month that is still open.
+// The runs are read FOR SHARE so a run cannot be locked until the caller commits.
+func openPayrollDate(tx *gorm.DB, outletID uint, date time.Time) (time.Time, error) {
+	for {
+		var locked int64
+		err := tx.Model(&model.PayrollRun{}).Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("period = ? AND outlet_id IN ? AND status = ?", date.Format("2006-01"), []uint{0, outletID}, model.PayrollLocked).
+			Count(&locked).Error
+		if err != nil {
+			return time.Time{}, err
+		}
+		if locked == 0 {
+			return date, nil
+		}
+		date = time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, time.Local)
+	}
+}
+
+func deletePayslips(tx *gorm.DB, runID uint) error {
+	payslips := tx.Model(&model.Payslip{}).Select("id").Where("run_id = ?", runID)
+	if err := tx.Where("payslip_id IN (?)", payslips).Delete(&model.PayslipLine{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("run_id = ?", runID).Delete(&model.Payslip{}).Error
+}
+
+func findPayrollRun(db *gorm.DB, id uint) (*model.PayrollRun, error) {
+	var run model.PayrollRun
+	if err := db.First(&run, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPayrollRunNotFound
+		}
+		return nil, err
+	}
+	return &run, nil
+}
+
+func lockDraftPayrollRun(tx *gorm.DB, id uint) (*model.PayrollRun, error) {
+	run, err := findPayrollRun(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != model.PayrollDraft {
+		return nil, ErrPayrollRunLocked
+	}
+	return run, nil
+}
+
+func renderPayslipPDF(run *model.PayrollRun, payslip *model.Payslip) ([]byte, error) {
+	document := model.PayslipDocument{
+		CompanyName:    config.INVOICE_COMPANY_NAME,
+		CompanyAddress: config.INVOICE_COMPANY_ADDRESS,
+		Period:         run.PeriodStart.Format("January 2006"),
+		PeriodStart:    run.PeriodStart,
+		PeriodEnd:      run.PeriodEnd,
+		Payslip:        *payslip,
+	}
+	var outlet model.Outlet
+	if err := database.DbCore.Select("id", "name").First(&outlet, payslip.OutletID).Error; err == nil {
+		document.OutletName = outlet.Name
+	}
+
+	tmpl, err := template.New("payslip.html").Funcs(template.FuncMap{
+		"rupiah": formatRupiah,
+		"hours":  overtimeHours,
+	}).ParseFiles(payslipTemplatePath)
+	if err != nil {
+		return nil, err
+	}
+	html := new(bytes.Buffer)
+	if err := tmpl.Execute(html, document); err != nil {
+		return nil, err
+	}
+	return thirdparty.GeneratePdfBytes(html.String())
+}
+
+func sendPayslipEmail(templateString string, run *model.PayrollRun, payslip *model.Payslip, pdf []byte) bool {
+	period := run.PeriodStart.Format("01/2006")
+	templateString = strings.Replace(templateString, "{{nama}}", html.EscapeString(payslip.EmployeeName), 1)
+	templateString = strings.Replace(templateString, "{{Opening_text}}", "Terlampir slip gaji Anda untuk periode "+period+".", 1)
+	templateString = strings.Replace(templateString, "{{keterangan}}", "Gaji bersih yang dibayarkan: "+formatRupiah(payslip.NetPay)+". Hubungi bagian HR jika ada pertanyaan.", 1)
+	templateString = strings.Replace(templateString, "{{Year}}", strconv.Itoa(time.Now().Year()), 1)
+	templateString = strings.Replace(templateString, "{{Link}}", config.PUBLIC_BASE_URL, 1)
+	templateString = strings.Replace(templateString, "{{Nama Sistem}}", config.INVOICE_COMPANY_NAME, 1)
+
+	return thirdparty.SendEmailWithAttachments(templateString, "Slip gaji periode "+period,
+		[]thirdparty.RecipientStruct{{Name: payslip.EmployeeName, Email: payslip.EmployeeEmail}},
+		[]thirdparty.AttachmentStruct{{
+			Filename: fmt.Sprintf("slip-gaji-%s-%s.pdf", payslip.EmployeeCode, run.Period),
+			Data:     pdf,
+		}},
+	)
+}
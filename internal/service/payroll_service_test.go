@@ -0,0 +1,42 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"BackendFramework/internal/model"
+)
+
+func TestBuildPayslipProration(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2026, 6, day, 0, 0, 0, 0, time.Local) }
+	end := func(day int) *time.Time { d := date(day); return &d }
+	run := &model.PayrollRun{ID: 1, PeriodStart: date(1), PeriodEnd: date(30)}
+
+	tests := []struct {
+		name     string
+		joinDate time.Time
+		endDate  *time.Time
+		wantBase int64
+		wantName string
+	}{
+		{name: "employed the whole period", joinDate: date(1).AddDate(-1, 0, 0), wantBase: 6000000, wantName: "Gaji pokok"},
+		{name: "joined on the last day", joinDate: date(30), wantBase: 200000, wantName: "Gaji pokok (1/30 hari)"},
+		{name: "joined mid period", joinDate: date(16), wantBase: 3000000, wantName: "Gaji pokok (15/30 hari)"},
+		{name: "left mid period", joinDate: date(1).AddDate(-1, 0, 0), endDate: end(10), wantBase: 2000000, wantName: "Gaji pokok (10/30 hari)"},
+		{name: "joined and left within the period", joinDate: date(5), endDate: end(14), wantBase: 2000000, wantName: "Gaji pokok (10/30 hari)"},
+		{name: "end date after the period", joinDate: date(1), endDate: end(45), wantBase: 6000000, wantName: "Gaji pokok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employee := &model.Employee{BaseSalary: 6000000, JoinDate: tt.joinDate, EndDate: tt.endDate}
+			payslip := buildPayslip(run, employee, nil)
+			if payslip.BaseSalary != tt.wantBase || payslip.GrossPay != tt.wantBase {
+				t.Errorf("base, gross = %d, %d, want %d", payslip.BaseSalary, payslip.GrossPay, tt.wantBase)
+			}
+			if payslip.Lines[0].Name != tt.wantName || payslip.Lines[0].Amount != tt.wantBase {
+				t.Errorf("base line = %q %d, want %q %d", payslip.Lines[0].Name, payslip.Lines[0].Amount, tt.wantName, tt.wantBase)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"gorm.io/gorm"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+type PrivacyService struct{}
+
+func NewPrivacyService() *PrivacyService {
+	return &PrivacyService{}
+}
+
+// ExportUserData collects everything we store about a user: the MySQL record,
+// session documents (without the token values), activity logs and uploaded files.
+func (s *PrivacyService) ExportUserData(username string) (*model.UserDataExport, error) {
+	var user model.User
+	if err := database.DbCore.Unscoped().Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	sessions, err := findUserDocuments(ctx, "access_tokens", username,
+		options.Find().SetProjection(bson.M{"access_token": 0, "refresh_token": 0}))
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := findUserDocuments(ctx, "user_activity", username,
+		options.Find().SetSort(bson.M{"timestamp": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []model.UserFile
+	if err := database.DbCore.Where("user_id = ?", username).Find(&files).Error; err != nil {
+		return nil, err
+	}
+
+	return &model.UserDataExport{
+		GeneratedAt: time.Now(),
+		User:        user,
+		Sessions:    sessions,
+		Activities:  activities,
+		Files:       files,
+	}, nil
+}
+
+// BuildExportArchive packs an export into a ZIP with one JSON file per source
+// and the original uploaded files under files/.
+func (s *PrivacyService) BuildExportArchive(export *model.UserDataExport) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+
+	parts := []struct {
+		name    string
+		content interface{}
+	}{
+		{"user.json", export.User},
+		{"sessions.json", export.Sessions},
+		{"activities.json", export.Activities},
+		{"files.json", export.Files},
+	}
+	for _, part := range parts {
+		if err := writeZipJSON(zw, part.name, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, file := range export.Files {
+		data, err := thirdparty.DownloadFileBucket(file.ObjectKey)
+		if err != nil {
+			// File yang sudah hilang dari bucket tidak membatalkan seluruh ekspor
+			middleware.LogError(err, "Failed to download user file for export")
+			continue
+		}
+		w, err := zw.Create(fmt.Sprintf("files/%d_%s", file.ID, path.Base(file.FileName)))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EraseUserData anonymizes a user's personal data. The users row is kept (with
+// its created_at) so registration analytics stay intact, activity logs keep their
+// endpoint/method/timestamp under a pseudonym, sessions and files are removed.
+// Audit entries keep which fields changed but lose the values.
+func (s *PrivacyService) EraseUserData(username string, actorID string) (*model.UserErasureResult, error) {
+	var user model.User
+	if err := database.DbCore.Unscoped().Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	pseudonym := fmt.Sprintf("erased-%d", user.ID)
+	result := &model.UserErasureResult{UserID: user.ID, Pseudonym: pseudonym}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Mongo dan bucket dibersihkan dulu; username di MySQL baru diganti terakhir
+	// supaya proses bisa diulang kalau ada langkah yang gagal di tengah jalan.
+	deleted, err := database.DbAuth.Collection("access_tokens").DeleteMany(ctx, bson.M{"user_id": username})
+	if err != nil {
+		middleware.LogError(err, "Failed to delete user sessions")
+		return nil, err
+	}
+	result.SessionsDeleted = deleted.DeletedCount
+
+	scrubbed, err := database.DbAuth.Collection("user_activity").UpdateMany(ctx,
+		bson.M{"user_id": username},
+		bson.M{
+			"$set":   bson.M{"user_id": pseudonym},
+			"$unset": bson.M{"ip_address": "", "user_agent": "", "query_params": "", "request_body": ""},
+		},
+	)
+	if err != nil {
+		middleware.LogError(err, "Failed to scrub user activity")
+		return nil, err
+	}
+	result.ActivitiesScrubbed = scrubbed.ModifiedCount
+
+	var files []model.UserFile
+	if err := database.DbCore.Where("user_id = ?", username).Find(&files).Error; err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if err := thirdparty.DeleteFileBucket(file.ObjectKey); err != nil {
+			middleware.LogError(err, "Failed to delete user file")
+			return nil, err
+		}
+		if err := database.DbCore.Delete(&file).Error; err != nil {
+			return nil, err
+		}
+		result.FilesDeleted++
+	}
+
+	err = database.DbCore.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&user).Updates(map[string]interface{}{
+			"username":             pseudonym,
+			"first_name":           "Erased",
+			"last_name":            "User",
+			"email":                pseudonym + "@erased.invalid",
+			"phone":                "",
+			"address":              "",
+			"password":             "",
+			"is_aktif":             "erased",
+			"subscribe_newsletter": false,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := redactUserAuditLogs(tx, userEntityID(user.ID)); err != nil {
+			return err
+		}
+		if err := tx.Model(&model.AuditLog{}).Where("actor_id = ?", username).Update("actor_id", pseudonym).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&model.AuditLog{
+			EntityType: model.AuditEntityUser,
+			EntityID:   userEntityID(user.ID),
+			Action:     model.AuditActionErase,
+			ActorID:    actorID,
+			Changes:    []model.AuditChange{},
+		}).Error
+	})
+	if err != nil {
+		middleware.LogError(err, "Failed to anonymize user record")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func redactUserAuditLogs(tx *gorm.DB, entityID string) error {
+	var logs []model.AuditLog
+	if err := tx.Where("entity_type = ? AND entity_id = ?", model.AuditEntityUser, entityID).Find(&logs).Error; err != nil {
+		return err
+	}
+	for _, log := range logs {
+		for i := range log.Changes {
+			log.Changes[i].Before = nil
+			log.Changes[i].After = nil
+		}
+		if err := tx.Model(&log).Select("changes").Updates(&log).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findUserDocuments(ctx context.Context, collection, username string, opts ...options.Lister[options.FindOptions]) ([]map[string]interface{}, error) {
+	// Dokumen bertingkat (query_params, request_body) didecode sebagai map supaya JSON-nya rapi
+	collOpts := options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true})
+	cursor, err := database.DbAuth.Collection(collection, collOpts).Find(ctx, bson.M{"user_id": username}, opts...)
+	if err != nil {
+		middleware.LogError(err, "Failed to query "+collection)
+		return nil, err
+	}
+
+	documents := []map[string]interface{}{}
+	if err := cursor.All(ctx, &documents); err != nil {
+		middleware.LogError(err, "Failed to decode "+collection)
+		return nil, err
+	}
+	return documents, nil
+}
+
+func writeZipJSON(zw *zip.Writer, name string, content interface{}) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
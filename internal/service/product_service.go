@@ -0,0 +1,571 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrProductNotFound          = errors.New("product not found")
+	ErrProductCategoryNotFound  = errors.New("product category not found")
+	ErrProductCategoryNotEmpty  = errors.New("product category still has products")
+	ErrProductVariantNotFound   = errors.New("product variant not found")
+	ErrProductModifierNotFound  = errors.New("product modifier not found")
+	ErrDuplicateProductCode     = errors.New("sku or barcode is already used")
+	ErrProductOutletNotFound    = errors.New("outlet not found")
+	ErrProductOutletPriceAbsent = errors.New("outlet price not found")
+)
+
+const defaultProductPageSize = 20
+
+type ProductService struct{}
+
+func NewProductService() *ProductService {
+	return &ProductService{}
+}
+
+// ---------------- CATEGORY ----------------
+
+func (s *ProductService) GetCategories() ([]model.ProductCategory, error) {
+	categories := []model.ProductCategory{}
+	if err := database.DbCore.Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (s *ProductService) CreateCategory(req model.ProductCategoryRequest) (*model.ProductCategory, error) {
+	category := model.ProductCategory{Name: req.Name, Description: req.Description}
+	if err := database.DbCore.Create(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (s *ProductService) UpdateCategory(id uint, req model.ProductCategoryRequest) (*model.ProductCategory, error) {
+	var category model.ProductCategory
+	if err := database.DbCore.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductCategoryNotFound
+		}
+		return nil, err
+	}
+
+	category.Name = req.Name
+	category.Description = req.Description
+	if err := database.DbCore.Save(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (s *ProductService) DeleteCategory(id uint) error {
+	var category model.ProductCategory
+	if err := database.DbCore.First(&category, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProductCategoryNotFound
+		}
+		return err
+	}
+
+	var products int64
+	if err := database.DbCore.Model(&model.Product{}).Where("category_id = ?", id).Count(&products).Error; err != nil {
+		return err
+	}
+	if products > 0 {
+		return ErrProductCategoryNotEmpty
+	}
+	return database.DbCore.Delete(&category).Error
+}
+
+// ---------------- PRODUCT ----------------
+
+func (s *ProductService) GetProducts(params model.ProductListQuery) (*model.ProductListResult, error) {
+	query := database.DbCore.Model(&model.Product{})
+	if params.Search != "" {
+		term := "%" + strings.ToLower(params.Search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(sku) LIKE ? OR barcode = ?", term, term, params.Search)
+	}
+	if params.CategoryID != 0 {
+		query = query.Where("category_id = ?", params.CategoryID)
+	}
+	if params.Active != nil {
+		query = query.Where("active = ?", *params.Active)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultProductPageSize
+	}
+
+	products := []model.Product{}
+	err := preloadProduct(query).
+		Order("name ASC").Order("id ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ProductListResult{
+		Products: products,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *ProductService) GetProductByID(id uint) (*model.Product, error) {
+	var product model.Product
+	if err := preloadProduct(database.DbCore).Preload("OutletPrices").First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProductByCode looks a scanned code up as SKU or barcode of a product or one
+// of its variants. The variant is nil when the code belongs to the product itself.
+func (s *ProductService) GetProductByCode(code string) (*model.Product, *model.ProductVariant, error) {
+	var product model.Product
+	err := preloadProduct(database.DbCore).Where("sku = ? OR barcode = ?", code, code).First(&product).Error
+	if err == nil {
+		return &product, nil, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	var variant model.ProductVariant
+	if err := database.DbCore.Where("sku = ? OR barcode = ?", code, code).First(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrProductNotFound
+		}
+		return nil, nil, err
+	}
+	found, err := s.GetProductByID(variant.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return found, &variant, nil
+}
+
+func (s *ProductService) CreateProduct(req model.ProductRequest) (*model.Product, error) {
+	product := model.Product{Active: true}
+	applyProductRequest(&product, req)
+
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := checkProductCategory(tx, req.CategoryID); err != nil {
+			return err
+		}
+		if err := checkProductCodes(tx, 0, req); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Create(&product).Error; err != nil {
+			return err
+		}
+		if err := syncProductVariants(tx, product.ID, req.Variants); err != nil {
+			return err
+		}
+		return syncProductModifiers(tx, product.ID, req.Modifiers)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProductByID(product.ID)
+}
+
+// UpdateProduct replaces a product. Variants and modifiers are matched by ID,
+// the ones missing from the request are soft deleted.
+func (s *ProductService) UpdateProduct(id uint, req model.ProductRequest) (*model.Product, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var product model.Product
+		if err := tx.First(&product, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if err := checkProductCategory(tx, req.CategoryID); err != nil {
+			return err
+		}
+		if err := checkProductCodes(tx, id, req); err != nil {
+			return err
+		}
+
+		applyProductRequest(&product, req)
+		if err := tx.Omit(clause.Associations).Save(&product).Error; err != nil {
+			return err
+		}
+		if err := syncProductVariants(tx, product.ID, req.Variants); err != nil {
+			return err
+		}
+		return syncProductModifiers(tx, product.ID, req.Modifiers)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProductByID(id)
+}
+
+// DeleteProduct soft deletes a product with its variants and modifiers; its
+// outlet price overrides are removed.
+func (s *ProductService) DeleteProduct(id uint) error {
+	return database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var product model.Product
+		if err := tx.First(&product, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+
+		if err := tx.Where("product_id = ?", id).Delete(&model.ProductVariant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&model.ProductModifier{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&model.ProductOutletPrice{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&product).Error
+	})
+}
+
+// ---------------- OUTLET PRICE ----------------
+
+// SetOutletPrice creates or replaces the price of a product (or one variant) at an outlet.
+func (s *ProductService) SetOutletPrice(productID, outletID uint, req model.ProductOutletPriceRequest, scope model.OutletScope) (*model.ProductOutletPrice, error) {
+	if _, err := findProductVariant(database.DbCore, productID, req.VariantID); err != nil {
+		return nil, err
+	}
+	if err := checkPriceOutletInScope(outletID, scope); err != nil {
+		return nil, err
+	}
+
+	price := model.ProductOutletPrice{
+		ProductID: productID,
+		OutletID:  outletID,
+		VariantID: req.VariantID,
+		Price:     req.Price,
+	}
+	err := database.DbCore.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"price", "updated_at"}),
+	}).Create(&price).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// ID dari upsert MySQL tidak selalu terisi, ambil ulang barisnya
+	if err := database.DbCore.
+		Where("product_id = ? AND outlet_id = ? AND variant_id = ?", productID, outletID, req.VariantID).
+		First(&price).Error; err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+func (s *ProductService) DeleteOutletPrice(productID, outletID, variantID uint, scope model.OutletScope) error {
+	if err := checkPriceOutletInScope(outletID, scope); err != nil {
+		return err
+	}
+	result := database.DbCore.
+		Where("product_id = ? AND outlet_id = ? AND variant_id = ?", productID, outletID, variantID).
+		Delete(&model.ProductOutletPrice{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrProductOutletPriceAbsent
+	}
+	return nil
+}
+
+// ResolvePrice returns the effective unit price of a product or variant at an outlet.
+func (s *ProductService) ResolvePrice(productID, variantID, outletID uint) (*model.ResolvedPrice, error) {
+	return ResolvePrice(database.DbCore, productID, variantID, outletID)
+}
+
+// ResolvePrice picks, in order: the outlet override of the variant, the outlet
+// override of the product when the variant has no own price, the variant price,
+// and finally the product base price. It takes a db so it can run inside a
+// caller's transaction.
+func ResolvePrice(db *gorm.DB, productID, variantID, outletID uint) (*model.ResolvedPrice, error) {
+	var product model.Product
+	if err := db.First(&product, productID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	variant, err := findProductVariant(db, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := &model.ResolvedPrice{
+		ProductID: productID,
+		VariantID: variantID,
+		OutletID:  outletID,
+		Price:     product.BasePrice,
+		TaxRate:   product.TaxRate,
+		Source:    model.PriceSourceBase,
+	}
+	if variant != nil && variant.Price != nil {
+		resolved.Price = *variant.Price
+		resolved.Source = model.PriceSourceVariant
+	}
+	if outletID == 0 {
+		return resolved, nil
+	}
+
+	candidates := []uint{variantID}
+	if variant == nil || variant.Price == nil {
+		candidates = append(candidates, 0)
+	}
+	var overrides []model.ProductOutletPrice
+	err = db.Where("product_id = ? AND outlet_id = ? AND variant_id IN ?", productID, outletID, candidates).
+		Find(&overrides).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		for _, override := range overrides {
+			if override.VariantID == candidate {
+				resolved.Price = override.Price
+				resolved.Source = model.PriceSourceOutlet
+				return resolved, nil
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// ---------------- HELPERS ----------------
+
+func preloadProduct(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Category").
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Modifiers", func(db *gorm.DB) *gorm.DB { return db.Order("id") })
+}
+
+func applyProductRequest(product *model.Product, req model.ProductRequest) {
+	product.CategoryID = req.CategoryID
+	product.Name = req.Name
+	product.Description = req.Description
+	product.SKU = req.SKU
+	product.Barcode = req.Barcode
+	product.BasePrice = req.BasePrice
+	product.TaxRate = req.TaxRate
+	product.Unit = req.Unit
+	if product.Unit == "" {
+		product.Unit = "pcs"
+	}
+	if req.Active != nil {
+		product.Active = *req.Active
+	}
+}
+
+func checkProductCategory(tx *gorm.DB, categoryID *uint) error {
+	if categoryID == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&model.ProductCategory{}).Where("id = ?", *categoryID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrProductCategoryNotFound
+	}
+	return nil
+}
+
+// checkProductCodes keeps SKUs and barcodes unique across products and variants,
+// so a scanned code always resolves to one item. Soft deleted rows still count
+// because they keep their unique index entry.
+func checkProductCodes(tx *gorm.DB, productID uint, req model.ProductRequest) error {
+	codes := []string{req.SKU}
+	if req.Barcode != nil {
+		codes = append(codes, *req.Barcode)
+	}
+	for _, variant := range req.Variants {
+		codes = append(codes, variant.SKU)
+		if variant.Barcode != nil {
+			codes = append(codes, *variant.Barcode)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if seen[code] {
+			return ErrDuplicateProductCode
+		}
+		seen[code] = true
+	}
+
+	var products int64
+	err := tx.Unscoped().Model(&model.Product{}).
+		Where("(sku IN ? OR barcode IN ?) AND id <> ?", codes, codes, productID).
+		Count(&products).Error
+	if err != nil {
+		return err
+	}
+	var variants int64
+	err = tx.Unscoped().Model(&model.ProductVariant{}).
+		Where("(sku IN ? OR barcode IN ?) AND product_id <> ?", codes, codes, productID).
+		Count(&variants).Error
+	if err != nil {
+		return err
+	}
+	if products > 0 || variants > 0 {
+		return ErrDuplicateProductCode
+	}
+	return nil
+}
+
+func syncProductVariants(tx *gorm.DB, productID uint, inputs []model.ProductVariantInput) error {
+	var existing []model.ProductVariant
+	if err := tx.Where("product_id = ?", productID).Find(&existing).Error; err != nil {
+		return err
+	}
+	byID := map[uint]model.ProductVariant{}
+	for _, variant := range existing {
+		byID[variant.ID] = variant
+	}
+
+	kept := map[uint]bool{}
+	for _, input := range inputs {
+		var variant model.ProductVariant
+		if input.ID != 0 {
+			found, ok := byID[input.ID]
+			if !ok {
+				return ErrProductVariantNotFound
+			}
+			variant = found
+		} else {
+			// Varian yang pernah dihapus dengan SKU sama dipulihkan, supaya ID-nya
+			// (yang dipakai histori stok dan penjualan) tetap sama
+			err := tx.Unscoped().Where("product_id = ? AND sku = ? AND deleted_at IS NOT NULL", productID, input.SKU).
+				Limit(1).Find(&variant).Error
+			if err != nil {
+				return err
+			}
+			variant.DeletedAt = gorm.DeletedAt{}
+		}
+
+		variant.ProductID = productID
+		variant.Name = input.Name
+		variant.SKU = input.SKU
+		variant.Barcode = input.Barcode
+		variant.Price = input.Price
+		variant.Active = input.Active == nil || *input.Active
+		if err := tx.Unscoped().Save(&variant).Error; err != nil {
+			return err
+		}
+		kept[variant.ID] = true
+	}
+
+	for _, variant := range existing {
+		if !kept[variant.ID] {
+			if err := tx.Delete(&variant).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func syncProductModifiers(tx *gorm.DB, productID uint, inputs []model.ProductModifierInput) error {
+	var existing []model.ProductModifier
+	if err := tx.Where("product_id = ?", productID).Find(&existing).Error; err != nil {
+		return err
+	}
+	byID := map[uint]model.ProductModifier{}
+	for _, modifier := range existing {
+		byID[modifier.ID] = modifier
+	}
+
+	kept := map[uint]bool{}
+	for _, input := range inputs {
+		modifier := model.ProductModifier{ProductID: productID}
+		if input.ID != 0 {
+			found, ok := byID[input.ID]
+			if !ok {
+				return ErrProductModifierNotFound
+			}
+			modifier = found
+		}
+
+		modifier.Name = input.Name
+		modifier.Price = input.Price
+		modifier.Active = input.Active == nil || *input.Active
+		if err := tx.Save(&modifier).Error; err != nil {
+			return err
+		}
+		kept[modifier.ID] = true
+	}
+
+	for _, modifier := range existing {
+		if !kept[modifier.ID] {
+			if err := tx.Delete(&modifier).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findProductVariant checks that variantID belongs to the product; 0 means the
+// product itself and returns a nil variant.
+func findProductVariant(db *gorm.DB, productID, variantID uint) (*model.ProductVariant, error) {
+	if variantID == 0 {
+		var count int64
+		if err := db.Model(&model.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrProductNotFound
+		}
+		return nil, nil
+	}
+
+	var variant model.ProductVariant
+	if err := db.Where("product_id = ?", productID).First(&variant, variantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// checkPriceOutletInScope only lets region managers price the outlets of their
+// own region; an outlet outside the scope is reported as not found.
+func checkPriceOutletInScope(outletID uint, scope model.OutletScope) error {
+	err := checkOutletInScope(database.DbCore, outletID, scope)
+	if errors.Is(err, ErrStockOutletScope) {
+		return ErrProductOutletNotFound
+	}
+	return err
+}
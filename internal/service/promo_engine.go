@@ -0,0 +1,335 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"BackendFramework/internal/model"
+)
+
+// EvaluatePromotions applies the promotions to the cart and explains why the others
+// did not apply. It only reads its arguments, so the same cart and promotions always
+// give the same result: promotions are tried by priority (highest first, then lowest
+// ID), and a discount is spread over the items by largest remainder, ties going to
+// the earlier item. Items used by a buy-X-get-Y or bundle promo are not reused by
+// another one; percentage and fixed promos work on what is left of each item.
+func EvaluatePromotions(cart model.PromoCart, promotions []model.Promotion) model.PromoEvaluation {
+	sorted := append([]model.Promotion{}, promotions...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	codes := map[string]bool{}
+	for _, code := range cart.Codes {
+		codes[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+
+	evaluation := model.PromoEvaluation{
+		Applied:      []model.AppliedPromo{},
+		Skipped:      []model.SkippedPromo{},
+		UnknownCodes: []string{},
+	}
+	remaining := make([]int64, len(cart.Items))
+	usedUnits := make([]float64, len(cart.Items))
+	for i, item := range cart.Items {
+		remaining[i] = item.Amount
+		evaluation.Subtotal += item.Amount
+	}
+
+	known := map[string]bool{}
+	exclusive := false
+	for i := range sorted {
+		promo := &sorted[i]
+		code := ""
+		if promo.Code != nil {
+			code = strings.ToUpper(*promo.Code)
+			known[code] = true
+		}
+		skip := func(reason string) {
+			evaluation.Skipped = append(evaluation.Skipped, model.SkippedPromo{
+				PromotionID: promo.ID,
+				Name:        promo.Name,
+				Code:        code,
+				Reason:      reason,
+			})
+		}
+
+		if reason := promoIneligibility(promo, cart, codes, evaluation.Subtotal); reason != "" {
+			skip(reason)
+			continue
+		}
+		if exclusive || (!promo.Stackable && len(evaluation.Applied) > 0) {
+			skip(model.PromoSkipNotStackable)
+			continue
+		}
+
+		var lines []int64
+		var units []float64
+		switch promo.Type {
+		case model.PromoPercentage, model.PromoFixed:
+			lines = promoAmountDiscount(promo, cart.Items, remaining)
+		case model.PromoBuyXGetY:
+			lines, units = promoBuyXGetYDiscount(promo, cart.Items, remaining, usedUnits)
+		case model.PromoBundle:
+			lines, units = promoBundleDiscount(promo, cart.Items, remaining, usedUnits)
+		}
+
+		var discount int64
+		for _, amount := range lines {
+			discount += amount
+		}
+		if promo.MaxDiscount > 0 && discount > promo.MaxDiscount {
+			lines = allocateDiscount(promo.MaxDiscount, lines)
+			discount = promo.MaxDiscount
+		}
+		if discount <= 0 {
+			skip(model.PromoSkipNoItems)
+			continue
+		}
+
+		for j := range lines {
+			remaining[j] -= lines[j]
+			if units != nil {
+				usedUnits[j] += units[j]
+			}
+		}
+		evaluation.Applied = append(evaluation.Applied, model.AppliedPromo{
+			PromotionID: promo.ID,
+			Name:        promo.Name,
+			Code:        code,
+			Type:        promo.Type,
+			Discount:    discount,
+			Lines:       lines,
+		})
+		evaluation.TotalDiscount += discount
+		if !promo.Stackable {
+			exclusive = true
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, code := range cart.Codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" && !known[code] && !seen[code] {
+			evaluation.UnknownCodes = append(evaluation.UnknownCodes, code)
+		}
+		seen[code] = true
+	}
+	return evaluation
+}
+
+// promoIneligibility returns why the promotion cannot apply to the cart, or ""
+// when it can.
+func promoIneligibility(promo *model.Promotion, cart model.PromoCart, codes map[string]bool, subtotal int64) string {
+	switch {
+	case promo.Code != nil && !codes[strings.ToUpper(*promo.Code)]:
+		return model.PromoSkipCodeRequired
+	case !promo.Active:
+		return model.PromoSkipInactive
+	case promo.StartsAt != nil && cart.At.Before(*promo.StartsAt):
+		return model.PromoSkipNotStarted
+	case promo.EndsAt != nil && !cart.At.Before(*promo.EndsAt):
+		return model.PromoSkipEnded
+	case len(promo.OutletIDs) > 0 && !containsID(promo.OutletIDs, cart.OutletID):
+		return model.PromoSkipOutlet
+	}
+	if ok, reason := promo.InTimeWindow(cart.At); !ok {
+		return reason
+	}
+	switch {
+	case promo.UsageLimit != nil && promo.UsageCount >= *promo.UsageLimit:
+		return model.PromoSkipUsageLimit
+	case promo.PerCustomerLimit != nil && cart.CustomerID == nil:
+		return model.PromoSkipCustomerRequired
+	case promo.PerCustomerLimit != nil && cart.CustomerUsage[promo.ID] >= *promo.PerCustomerLimit:
+		return model.PromoSkipCustomerLimit
+	case subtotal < promo.MinSpend:
+		return model.PromoSkipMinSpend
+	}
+	return ""
+}
+
+// promoAmountDiscount takes a percentage or a fixed amount off the eligible items.
+func promoAmountDiscount(promo *model.Promotion, items []model.PromoCartItem, remaining []int64) []int64 {
+	weights := make([]int64, len(items))
+	var base int64
+	for i, item := range items {
+		if promoCoversItem(promo, item) {
+			weights[i] = remaining[i]
+			base += remaining[i]
+		}
+	}
+
+	discount := int64(promo.Value)
+	if promo.Type == model.PromoPercentage {
+		discount = roundRupiah(float64(base) * promo.Value / 100)
+	}
+	return allocateDiscount(min(discount, base), weights)
+}
+
+// promoBuyXGetYDiscount lines up the eligible units from the most to the least
+// expensive; in every complete group of Buy+Get units the Get cheapest ones are
+// discounted by Value percent (100 when not set).
+func promoBuyXGetYDiscount(promo *model.Promotion, items []model.PromoCartItem, remaining []int64, usedUnits []float64) ([]int64, []float64) {
+	lines := make([]int64, len(items))
+	units := make([]float64, len(items))
+	group := promo.BuyQuantity + promo.GetQuantity
+	if promo.BuyQuantity < 1 || promo.GetQuantity < 1 {
+		return lines, units
+	}
+
+	type unit struct {
+		item  int
+		price float64
+	}
+	pool := []unit{}
+	for i, item := range items {
+		available := math.Floor(item.Quantity - usedUnits[i])
+		if !promoCoversItem(promo, item) || available < 1 {
+			continue
+		}
+		price := float64(remaining[i]) / (item.Quantity - usedUnits[i])
+		for n := 0; n < int(available); n++ {
+			pool = append(pool, unit{item: i, price: price})
+		}
+	}
+	sort.SliceStable(pool, func(i, j int) bool {
+		if pool[i].price != pool[j].price {
+			return pool[i].price > pool[j].price
+		}
+		return pool[i].item < pool[j].item
+	})
+
+	percent := promo.Value
+	if percent <= 0 || percent > 100 {
+		percent = 100
+	}
+	for g := 0; g+group <= len(pool); g += group {
+		for n := g; n < g+group; n++ {
+			units[pool[n].item]++
+			if n >= g+promo.BuyQuantity {
+				lines[pool[n].item] += roundRupiah(pool[n].price * percent / 100)
+			}
+		}
+	}
+	for i := range lines {
+		lines[i] = min(lines[i], remaining[i])
+	}
+	return lines, units
+}
+
+// promoBundleDiscount sells complete sets of BundleItems at BundlePrice each. The
+// saving is spread over the items in the sets by their value.
+func promoBundleDiscount(promo *model.Promotion, items []model.PromoCartItem, remaining []int64, usedUnits []float64) ([]int64, []float64) {
+	lines := make([]int64, len(items))
+	units := make([]float64, len(items))
+	if len(promo.BundleItems) == 0 {
+		return lines, units
+	}
+
+	available := func(i int) float64 {
+		return math.Floor(items[i].Quantity - usedUnits[i])
+	}
+	sets := math.MaxInt
+	for _, bundleItem := range promo.BundleItems {
+		var count float64
+		for i, item := range items {
+			if item.ProductID == bundleItem.ProductID {
+				count += available(i)
+			}
+		}
+		sets = min(sets, int(count)/bundleItem.Quantity)
+	}
+	if sets == 0 {
+		return lines, units
+	}
+
+	values := make([]int64, len(items))
+	var normal int64
+	for _, bundleItem := range promo.BundleItems {
+		need := float64(sets * bundleItem.Quantity)
+		for i, item := range items {
+			if need == 0 || item.ProductID != bundleItem.ProductID {
+				continue
+			}
+			take := min(need, available(i)-units[i])
+			if take <= 0 {
+				continue
+			}
+			value := roundRupiah(float64(remaining[i]) * take / (item.Quantity - usedUnits[i]))
+			units[i] += take
+			values[i] += value
+			normal += value
+			need -= take
+		}
+	}
+
+	saving := normal - int64(sets)*promo.BundlePrice
+	if saving <= 0 {
+		return lines, make([]float64, len(items))
+	}
+	return allocateDiscount(saving, values), units
+}
+
+func promoCoversItem(promo *model.Promotion, item model.PromoCartItem) bool {
+	if len(promo.ProductIDs) == 0 && len(promo.CategoryIDs) == 0 {
+		return true
+	}
+	if containsID(promo.ProductIDs, item.ProductID) {
+		return true
+	}
+	return item.CategoryID != nil && containsID(promo.CategoryIDs, *item.CategoryID)
+}
+
+// allocateDiscount splits amount over the weights proportionally, never giving a
+// line more than its weight. Leftover rupiah go to the largest remainders.
+func allocateDiscount(amount int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	var total int64
+	for _, weight := range weights {
+		total += weight
+	}
+	if amount <= 0 || total <= 0 {
+		return shares
+	}
+	amount = min(amount, total)
+
+	type remainder struct {
+		index int
+		value float64
+	}
+	remainders := []remainder{}
+	var allocated int64
+	for i, weight := range weights {
+		exact := float64(amount) * float64(weight) / float64(total)
+		shares[i] = int64(math.Floor(exact))
+		allocated += shares[i]
+		remainders = append(remainders, remainder{index: i, value: exact - float64(shares[i])})
+	}
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value > remainders[j].value
+	})
+	for _, r := range remainders {
+		if allocated >= amount {
+			break
+		}
+		if shares[r.index] < weights[r.index] {
+			shares[r.index]++
+			allocated++
+		}
+	}
+	return shares
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"BackendFramework/internal/model"
+)
+
+func TestEvaluatePromotions(t *testing.T) {
+	code := func(s string) *string { return &s }
+	limit := func(n int) *int { return &n }
+	customer := func(id uint) *uint { return &id }
+	// Selasa siang
+	at := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	moment := func(d time.Duration) *time.Time { t := at.Add(d); return &t }
+	// Subtotal 150.000: item A 100.000, item B 2 x 25.000
+	items := []model.PromoCartItem{
+		{ProductID: 1, Quantity: 1, Amount: 100000},
+		{ProductID: 2, Quantity: 2, Amount: 50000},
+	}
+
+	type applied struct {
+		ID       uint
+		Discount int64
+		Lines    []int64
+	}
+	tests := []struct {
+		name        string
+		items       []model.PromoCartItem
+		codes       []string
+		at          time.Time
+		customerID  *uint
+		usage       map[uint]int
+		promotions  []model.Promotion
+		wantApplied []applied
+		wantSkipped map[uint]string
+		wantUnknown []string
+	}{
+		{
+			name: "stackable promos apply on what is left",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoPercentage, Value: 10, Priority: 10, Stackable: true, Active: true},
+				{ID: 2, Type: model.PromoFixed, Value: 6000, Priority: 5, Stackable: true, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 15000, Lines: []int64{10000, 5000}},
+				{ID: 2, Discount: 6000, Lines: []int64{4000, 2000}},
+			},
+			wantSkipped: map[uint]string{},
+		},
+		{
+			name: "exclusive promo blocks the rest",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoPercentage, Value: 20, Priority: 10, Active: true},
+				{ID: 2, Type: model.PromoFixed, Value: 5000, Priority: 5, Stackable: true, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 30000, Lines: []int64{20000, 10000}},
+			},
+			wantSkipped: map[uint]string{2: model.PromoSkipNotStackable},
+		},
+		{
+			name: "exclusive promo is skipped once another applied",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoFixed, Value: 5000, Priority: 10, Stackable: true, Active: true},
+				{ID: 2, Type: model.PromoPercentage, Value: 50, Priority: 5, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 5000, Lines: []int64{3333, 1667}},
+			},
+			wantSkipped: map[uint]string{2: model.PromoSkipNotStackable},
+		},
+		{
+			name: "minimum spend is checked against the subtotal",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoFixed, Value: 10000, MinSpend: 150001, Stackable: true, Active: true},
+				{ID: 2, Type: model.PromoFixed, Value: 10000, MinSpend: 150000, Stackable: true, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 2, Discount: 10000, Lines: []int64{6667, 3333}},
+			},
+			wantSkipped: map[uint]string{1: model.PromoSkipMinSpend},
+		},
+		{
+			name: "equal priority goes to the lowest ID",
+			promotions: []model.Promotion{
+				{ID: 5, Type: model.PromoFixed, Value: 1000, Active: true},
+				{ID: 3, Type: model.PromoFixed, Value: 2000, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 3, Discount: 2000, Lines: []int64{1333, 667}},
+			},
+			wantSkipped: map[uint]string{5: model.PromoSkipNotStackable},
+		},
+		{
+			name: "equal remainders go to the earlier item",
+			items: []model.PromoCartItem{
+				{ProductID: 1, Quantity: 1, Amount: 1000},
+				{ProductID: 2, Quantity: 1, Amount: 1000},
+			},
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoFixed, Value: 1, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 1, Lines: []int64{1, 0}},
+			},
+			wantSkipped: map[uint]string{},
+		},
+		{
+			name: "max discount caps a percentage promo",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoPercentage, Value: 50, MaxDiscount: 10000, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 10000, Lines: []int64{6667, 3333}},
+			},
+			wantSkipped: map[uint]string{},
+		},
+		{
+			name:  "voucher codes are matched case-insensitively",
+			codes: []string{" hemat ", "XYZ"},
+			promotions: []model.Promotion{
+				{ID: 1, Code: code("HEMAT"), Type: model.PromoFixed, Value: 1500, Stackable: true, Active: true},
+				{ID: 2, Code: code("OTHER"), Type: model.PromoFixed, Value: 1500, Stackable: true, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 1500, Lines: []int64{1000, 500}},
+			},
+			wantSkipped: map[uint]string{2: model.PromoSkipCodeRequired},
+			wantUnknown: []string{"XYZ"},
+		},
+		{
+			name: "buy one get one takes the cheapest unit",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoBuyXGetY, BuyQuantity: 1, GetQuantity: 1, Stackable: true, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 25000, Lines: []int64{0, 25000}},
+			},
+			wantSkipped: map[uint]string{},
+		},
+		{
+			name: "bundle sells a complete set at the bundle price",
+			items: []model.PromoCartItem{
+				{ProductID: 1, Quantity: 2, Amount: 40000},
+				{ProductID: 2, Quantity: 1, Amount: 15000},
+			},
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoBundle, BundleItems: []model.PromotionBundleItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}}, BundlePrice: 30000, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 5000, Lines: []int64{2857, 2143}},
+			},
+			wantSkipped: map[uint]string{},
+		},
+		{
+			name: "incomplete bundle gives no discount",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoBundle, BundleItems: []model.PromotionBundleItem{{ProductID: 1, Quantity: 1}, {ProductID: 3, Quantity: 1}}, BundlePrice: 1000, Active: true},
+			},
+			wantApplied: []applied{},
+			wantSkipped: map[uint]string{1: model.PromoSkipNoItems},
+		},
+		{
+			name: "time window past midnight belongs to the day it started",
+			// Selasa 01:00 masih bagian dari malam Senin
+			at: time.Date(2026, 3, 10, 1, 0, 0, 0, time.Local),
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoFixed, Value: 1000, DaysOfWeek: []int{1}, StartTime: "22:00", EndTime: "02:00", Stackable: true, Active: true},
+				{ID: 2, Type: model.PromoFixed, Value: 1000, DaysOfWeek: []int{2}, StartTime: "22:00", EndTime: "02:00", Stackable: true, Active: true},
+				{ID: 3, Type: model.PromoFixed, Value: 1000, StartTime: "22:00", EndTime: "02:00", Stackable: true, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 1, Discount: 1000, Lines: []int64{667, 333}},
+				{ID: 3, Discount: 1000, Lines: []int64{667, 333}},
+			},
+			wantSkipped: map[uint]string{2: model.PromoSkipDay},
+		},
+		{
+			name: "outside the daily hours",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoFixed, Value: 1000, StartTime: "22:00", EndTime: "02:00", Active: true},
+				{ID: 2, Type: model.PromoFixed, Value: 1000, DaysOfWeek: []int{3}, Active: true},
+			},
+			wantApplied: []applied{},
+			wantSkipped: map[uint]string{1: model.PromoSkipTime, 2: model.PromoSkipDay},
+		},
+		{
+			name: "promo period, inactive promos and outlets",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoFixed, Value: 1000, StartsAt: moment(time.Minute), Active: true},
+				{ID: 2, Type: model.PromoFixed, Value: 1000, EndsAt: moment(0), Active: true},
+				{ID: 3, Type: model.PromoFixed, Value: 1000, Active: false},
+				{ID: 4, Type: model.PromoFixed, Value: 1000, OutletIDs: []uint{2}, Active: true},
+				{ID: 5, Type: model.PromoFixed, Value: 1000, StartsAt: moment(0), EndsAt: moment(time.Minute), OutletIDs: []uint{1, 2}, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 5, Discount: 1000, Lines: []int64{667, 333}},
+			},
+			wantSkipped: map[uint]string{
+				1: model.PromoSkipNotStarted,
+				2: model.PromoSkipEnded,
+				3: model.PromoSkipInactive,
+				4: model.PromoSkipOutlet,
+			},
+		},
+		{
+			name: "usage limits without a customer",
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoFixed, Value: 1000, UsageLimit: limit(5), UsageCount: 5, Active: true},
+				{ID: 2, Type: model.PromoFixed, Value: 1000, PerCustomerLimit: limit(1), Active: true},
+				{ID: 3, Type: model.PromoFixed, Value: 1000, UsageLimit: limit(5), UsageCount: 4, Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 3, Discount: 1000, Lines: []int64{667, 333}},
+			},
+			wantSkipped: map[uint]string{1: model.PromoSkipUsageLimit, 2: model.PromoSkipCustomerRequired},
+		},
+		{
+			name:       "per customer limit counts the customer's earlier sales",
+			customerID: customer(7),
+			usage:      map[uint]int{1: 1, 2: 1},
+			promotions: []model.Promotion{
+				{ID: 1, Type: model.PromoFixed, Value: 1000, PerCustomerLimit: limit(1), Active: true},
+				{ID: 2, Type: model.PromoFixed, Value: 1000, PerCustomerLimit: limit(2), Active: true},
+			},
+			wantApplied: []applied{
+				{ID: 2, Discount: 1000, Lines: []int64{667, 333}},
+			},
+			wantSkipped: map[uint]string{1: model.PromoSkipCustomerLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cart := model.PromoCart{OutletID: 1, CustomerID: tt.customerID, Codes: tt.codes, At: tt.at, Items: tt.items, CustomerUsage: tt.usage}
+			if cart.Items == nil {
+				cart.Items = items
+			}
+			if cart.At.IsZero() {
+				cart.At = at
+			}
+			got := EvaluatePromotions(cart, tt.promotions)
+
+			var total int64
+			gotApplied := []applied{}
+			for _, promo := range got.Applied {
+				gotApplied = append(gotApplied, applied{ID: promo.PromotionID, Discount: promo.Discount, Lines: promo.Lines})
+				total += promo.Discount
+			}
+			if !reflect.DeepEqual(gotApplied, tt.wantApplied) {
+				t.Errorf("applied = %+v, want %+v", gotApplied, tt.wantApplied)
+			}
+			if got.TotalDiscount != total {
+				t.Errorf("totalDiscount = %d, want %d", got.TotalDiscount, total)
+			}
+
+			gotSkipped := map[uint]string{}
+			for _, promo := range got.Skipped {
+				gotSkipped[promo.PromotionID] = promo.Reason
+			}
+			if !reflect.DeepEqual(gotSkipped, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", gotSkipped, tt.wantSkipped)
+			}
+
+			wantUnknown := tt.wantUnknown
+			if wantUnknown == nil {
+				wantUnknown = []string{}
+			}
+			if !reflect.DeepEqual(got.UnknownCodes, wantUnknown) {
+				t.Errorf("unknownCodes = %v, want %v", got.UnknownCodes, wantUnknown)
+			}
+
+			// Urutan input promo tidak boleh mengubah hasil
+			reversed := make([]model.Promotion, len(tt.promotions))
+			for i, promo := range tt.promotions {
+				reversed[len(reversed)-1-i] = promo
+			}
+			if again := EvaluatePromotions(cart, reversed); !reflect.DeepEqual(again, got) {
+				t.Errorf("result depends on promotion order: %+v vs %+v", again, got)
+			}
+		})
+	}
+}
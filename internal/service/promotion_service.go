@@ -0,0 +1,367 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrPromoNotFound       = errors.New("promotion not found")
+	ErrPromoCodeExists     = errors.New("voucher code is already used by another promotion")
+	ErrPromoValueInvalid   = errors.New("percentage must be between 0 and 100 and amounts above 0")
+	ErrPromoPeriodInvalid  = errors.New("promotion must end after it starts")
+	ErrPromoUsageExhausted = errors.New("promotion usage limit has been reached")
+)
+
+const defaultPromotionPageSize = 20
+
+type PromotionService struct{}
+
+func NewPromotionService() *PromotionService {
+	return &PromotionService{}
+}
+
+func (s *PromotionService) GetPromotions(params model.PromotionListQuery) (*model.PromotionListResult, error) {
+	query := database.DbCore.Model(&model.Promotion{})
+	if params.Search != "" {
+		search := "%" + params.Search + "%"
+		query = query.Where("name LIKE ? OR code LIKE ?", search, search)
+	}
+	if params.Type != "" {
+		query = query.Where("type = ?", params.Type)
+	}
+	if params.Active != nil {
+		query = query.Where("active = ?", *params.Active)
+	}
+	if params.OutletID != 0 {
+		query = query.Where("outlet_ids IS NULL OR JSON_LENGTH(outlet_ids) = 0 OR JSON_CONTAINS(outlet_ids, CAST(? AS JSON))", params.OutletID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPromotionPageSize
+	}
+
+	promotions := []model.Promotion{}
+	err := query.Order("priority DESC").Order("id ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&promotions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PromotionListResult{
+		Promotions: promotions,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *PromotionService) GetPromotion(id uint) (*model.Promotion, error) {
+	var promotion model.Promotion
+	if err := database.DbCore.First(&promotion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPromoNotFound
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (s *PromotionService) CreatePromotion(req model.PromotionRequest) (*model.Promotion, error) {
+	promotion := model.Promotion{}
+	if err := applyPromotionRequest(&promotion, req); err != nil {
+		return nil, err
+	}
+	if err := checkPromotionCode(database.DbCore, &promotion); err != nil {
+		return nil, err
+	}
+	if err := database.DbCore.Create(&promotion).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// UpdatePromotion replaces the definition; UsageCount is kept.
+func (s *PromotionService) UpdatePromotion(id uint, req model.PromotionRequest) (*model.Promotion, error) {
+	promotion, err := s.GetPromotion(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPromotionRequest(promotion, req); err != nil {
+		return nil, err
+	}
+	if err := checkPromotionCode(database.DbCore, promotion); err != nil {
+		return nil, err
+	}
+	if err := database.DbCore.Save(promotion).Error; err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// DeletePromotion soft deletes the promotion; sales keep their SalePromotion rows.
+func (s *PromotionService) DeletePromotion(id uint) error {
+	result := database.DbCore.Delete(&model.Promotion{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPromoNotFound
+	}
+	return nil
+}
+
+// Preview prices the cart and evaluates the promotions exactly like checkout
+// would, without saving anything, so the cashier sees why a promotion did or
+// did not apply.
+func (s *PromotionService) Preview(req model.PromotionPreviewRequest, scope model.OutletScope) (*model.PromotionPreview, error) {
+	db := database.DbCore
+	if err := checkOutletInScope(db, req.OutletID, scope); err != nil {
+		return nil, err
+	}
+	if req.CustomerID != nil {
+		if _, err := findCustomer(db, *req.CustomerID); err != nil {
+			return nil, err
+		}
+	}
+
+	lines := []model.SaleLine{}
+	for _, input := range req.Lines {
+		line, err := buildSaleLine(db, req.OutletID, input)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+
+	at := time.Now()
+	if req.At != nil {
+		at = *req.At
+	}
+	evaluation, err := evaluateSalePromotions(db, req.OutletID, req.CustomerID, req.PromoCodes, lines, at)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyPromoDiscounts(lines, evaluation); err != nil {
+		return nil, err
+	}
+	return &model.PromotionPreview{Lines: lines, Evaluation: evaluation}, nil
+}
+
+// evaluateSalePromotions builds the promo cart from priced sale lines and runs
+// EvaluatePromotions against the promotions that could apply: active ones without
+// a code plus any promotion whose code was entered.
+func evaluateSalePromotions(db *gorm.DB, outletID uint, customerID *uint, codes []string, lines []model.SaleLine, at time.Time) (model.PromoEvaluation, error) {
+	var outlet model.Outlet
+	if err := db.First(&outlet, outletID).Error; err != nil {
+		return model.PromoEvaluation{}, err
+	}
+
+	cart := model.PromoCart{
+		OutletID:      outletID,
+		CustomerID:    customerID,
+		Codes:         codes,
+		At:            at.In(outlet.Location()),
+		CustomerUsage: map[uint]int{},
+	}
+	productIDs := []uint{}
+	for _, line := range lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	var products []model.Product
+	if err := db.Select("id", "category_id").Where("id IN ?", uniqueIDs(productIDs)).Find(&products).Error; err != nil {
+		return model.PromoEvaluation{}, err
+	}
+	categoryOf := map[uint]*uint{}
+	for _, product := range products {
+		categoryOf[product.ID] = product.CategoryID
+	}
+	for _, line := range lines {
+		cart.Items = append(cart.Items, model.PromoCartItem{
+			ProductID:  line.ProductID,
+			CategoryID: categoryOf[line.ProductID],
+			Quantity:   line.Quantity,
+			Amount:     line.LineTotal - line.TaxAmount,
+		})
+	}
+
+	upperCodes := []string{}
+	for _, code := range codes {
+		upperCodes = append(upperCodes, strings.ToUpper(strings.TrimSpace(code)))
+	}
+	query := db.Where("code IS NULL AND active = ?", true)
+	if len(upperCodes) > 0 {
+		query = db.Where("(code IS NULL AND active = ?) OR code IN ?", true, upperCodes)
+	}
+	var promotions []model.Promotion
+	if err := query.Find(&promotions).Error; err != nil {
+		return model.PromoEvaluation{}, err
+	}
+
+	if customerID != nil {
+		var usage []struct {
+			PromotionID uint
+			Uses        int
+		}
+		err := db.Model(&model.SalePromotion{}).
+			Joins("JOIN sales ON sales.id = sale_promotions.sale_id").
+			Where("sales.customer_id = ? AND sales.status <> ?", *customerID, model.SaleStatusVoided).
+			Select("sale_promotions.promotion_id, COUNT(*) AS uses").
+			Group("sale_promotions.promotion_id").
+			Scan(&usage).Error
+		if err != nil {
+			return model.PromoEvaluation{}, err
+		}
+		for _, row := range usage {
+			cart.CustomerUsage[row.PromotionID] = row.Uses
+		}
+	}
+
+	return EvaluatePromotions(cart, promotions), nil
+}
+
+// applyPromoDiscounts adds the promo discounts to the lines' discount and reprices them.
+func applyPromoDiscounts(lines []model.SaleLine, evaluation model.PromoEvaluation) error {
+	for _, applied := range evaluation.Applied {
+		for i, amount := range applied.Lines {
+			lines[i].Discount += amount
+		}
+	}
+	for i := range lines {
+		if err := priceSaleLine(&lines[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func salePromotions(evaluation model.PromoEvaluation) []model.SalePromotion {
+	promotions := []model.SalePromotion{}
+	for _, applied := range evaluation.Applied {
+		promotions = append(promotions, model.SalePromotion{
+			PromotionID: applied.PromotionID,
+			Name:        applied.Name,
+			Code:        applied.Code,
+			Discount:    applied.Discount,
+		})
+	}
+	return promotions
+}
+
+// claimPromotionUsage counts one use per applied promotion. The limit is checked
+// in the same UPDATE, so two cashiers cannot both take the last use.
+func claimPromotionUsage(tx *gorm.DB, promotions []model.SalePromotion) error {
+	for _, promotion := range promotions {
+		result := tx.Model(&model.Promotion{}).
+			Where("id = ? AND (usage_limit IS NULL OR usage_count < usage_limit)", promotion.PromotionID).
+			UpdateColumn("usage_count", gorm.Expr("usage_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPromoUsageExhausted
+		}
+	}
+	return nil
+}
+
+// releasePromotionUsage gives the uses of a voided sale back to its promotions.
+func releasePromotionUsage(tx *gorm.DB, saleID uint) error {
+	var promotionIDs []uint
+	if err := tx.Model(&model.SalePromotion{}).Where("sale_id = ?", saleID).Pluck("promotion_id", &promotionIDs).Error; err != nil {
+		return err
+	}
+	if len(promotionIDs) == 0 {
+		return nil
+	}
+	return tx.Unscoped().Model(&model.Promotion{}).
+		Where("id IN ? AND usage_count > 0", promotionIDs).
+		UpdateColumn("usage_count", gorm.Expr("usage_count - 1")).Error
+}
+
+func applyPromotionRequest(promotion *model.Promotion, req model.PromotionRequest) error {
+	switch req.Type {
+	case model.PromoPercentage:
+		if req.Value <= 0 || req.Value > 100 {
+			return ErrPromoValueInvalid
+		}
+	case model.PromoFixed:
+		if req.Value <= 0 {
+			return ErrPromoValueInvalid
+		}
+	case model.PromoBuyXGetY:
+		if req.Value < 0 || req.Value > 100 {
+			return ErrPromoValueInvalid
+		}
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return ErrPromoPeriodInvalid
+	}
+
+	promotion.Name = req.Name
+	promotion.Code = nil
+	if req.Code != nil && *req.Code != "" {
+		code := strings.ToUpper(*req.Code)
+		promotion.Code = &code
+	}
+	promotion.Type = req.Type
+	promotion.Value = req.Value
+	promotion.MaxDiscount = req.MaxDiscount
+	promotion.MinSpend = req.MinSpend
+	promotion.BuyQuantity = req.BuyQuantity
+	promotion.GetQuantity = req.GetQuantity
+	promotion.BundleItems = req.BundleItems
+	promotion.BundlePrice = req.BundlePrice
+	promotion.ProductIDs = req.ProductIDs
+	promotion.CategoryIDs = req.CategoryIDs
+	promotion.OutletIDs = req.OutletIDs
+	promotion.StartsAt = req.StartsAt
+	promotion.EndsAt = req.EndsAt
+	promotion.DaysOfWeek = req.DaysOfWeek
+	promotion.StartTime = req.StartTime
+	promotion.EndTime = req.EndTime
+	promotion.UsageLimit = req.UsageLimit
+	promotion.PerCustomerLimit = req.PerCustomerLimit
+	promotion.Priority = req.Priority
+	promotion.Stackable = req.Stackable
+	promotion.Active = req.Active == nil || *req.Active
+	return nil
+}
+
+// checkPromotionCode keeps voucher codes unique, soft deleted promotions
+// included because they keep their unique index entry.
+func checkPromotionCode(db *gorm.DB, promotion *model.Promotion) error {
+	if promotion.Code == nil {
+		return nil
+	}
+	var count int64
+	err := db.Unscoped().Model(&model.Promotion{}).
+		Where("code = ? AND id <> ?", *promotion.Code, promotion.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPromoCodeExists
+	}
+	return nil
+}
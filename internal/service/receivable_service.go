@@ -0,0 +1,638 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var (
+	ErrReceivableNotFound         = errors.New("receivable not found")
+	ErrReceivableNoCustomer       = errors.New("credit sales require a customer")
+	ErrReceivableCreditNotAllowed = errors.New("customer has no credit limit")
+	ErrReceivableCreditLimit      = errors.New("sale exceeds the customer's remaining credit limit")
+	ErrReceivableSettled          = errors.New("receivable is already settled")
+	ErrReceivableOverpaid         = errors.New("payment exceeds the receivable balance")
+	ErrInvalidStatementPeriod     = errors.New("from must not be after to")
+)
+
+const (
+	statementTemplatePath     = "./web/html/customer_statement.html"
+	receivableEmailTemplate   = "./web/html/email_template.html"
+	defaultReceivablePageSize = 20
+)
+
+type ReceivableService struct{}
+
+func NewReceivableService() *ReceivableService {
+	return &ReceivableService{}
+}
+
+func (s *ReceivableService) GetReceivables(params model.ReceivableListQuery, scope model.OutletScope) (*model.ReceivableListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.Receivable{}), "outlet_id", scope)
+	if params.CustomerID != 0 {
+		query = query.Where("customer_id = ?", params.CustomerID)
+	}
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if params.Overdue {
+		query = query.Where("balance > 0 AND due_date < ?", startOfDay(time.Now()))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultReceivablePageSize
+	}
+
+	receivables := []model.Receivable{}
+	err := query.Order("due_date ASC").Order("id ASC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&receivables).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ReceivableListResult{
+		Receivables: receivables,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *ReceivableService) GetReceivable(id uint, scope model.OutletScope) (*model.Receivable, error) {
+	var receivable model.Receivable
+	err := scopeOutletColumn(database.DbCore.Model(&model.Receivable{}), "outlet_id", scope).
+		Preload("Payments", func(db *gorm.DB) *gorm.DB { return db.Order("paid_at ASC").Order("id ASC") }).
+		First(&receivable, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReceivableNotFound
+		}
+		return nil, err
+	}
+	return &receivable, nil
+}
+
+// RecordPayment books a (partial) payment against the receivable. A payment may
+// not exceed the remaining balance; the receivable is paid once it reaches zero.
+func (s *ReceivableService) RecordPayment(id uint, req model.ReceivablePaymentRequest, scope model.OutletScope, actorID string) (*model.Receivable, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var receivable model.Receivable
+		err := scopeOutletColumn(tx.Model(&model.Receivable{}), "outlet_id", scope).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&receivable, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReceivableNotFound
+			}
+			return err
+		}
+		if receivable.Balance <= 0 {
+			return ErrReceivableSettled
+		}
+		if req.Amount > receivable.Balance {
+			return ErrReceivableOverpaid
+		}
+
+		paidAt := time.Now()
+		if req.PaidAt != nil {
+			paidAt = *req.PaidAt
+		}
+		payment := model.ReceivablePayment{
+			ReceivableID: receivable.ID,
+			CustomerID:   receivable.CustomerID,
+			Amount:       req.Amount,
+			Method:       req.Method,
+			Reference:    req.Reference,
+			Note:         req.Note,
+			PaidAt:       paidAt,
+			ReceivedBy:   actorID,
+		}
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+
+		receivable.Paid += req.Amount
+		return saveReceivableBalance(tx, &receivable)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetReceivable(id, scope)
+}
+
+// GetAging splits the outstanding balances per customer by how long they are past
+// due on asOf (default today). Balances are taken as of that date, so payments
+// made afterwards do not change an earlier report.
+func (s *ReceivableService) GetAging(params model.ReceivableAgingQuery, scope model.OutletScope) (*model.ReceivableAgingReport, error) {
+	asOf := startOfDay(time.Now())
+	if params.AsOf != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", params.AsOf, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		asOf = parsed
+	}
+	end := asOf.AddDate(0, 0, 1)
+
+	query := scopeOutletColumn(database.DbCore.Model(&model.Receivable{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	receivables, err := receivablesOutstandingAt(query, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := model.ReceivableAgingReport{
+		AsOf:      asOf.Format("2006-01-02"),
+		Customers: []model.ReceivableAgingCustomer{},
+	}
+	byCustomer := map[uint]*model.ReceivableAgingCustomer{}
+	customerIDs := []uint{}
+	for i := range receivables {
+		balance := receivableBalanceAt(&receivables[i], end)
+		if balance <= 0 {
+			continue
+		}
+		row, ok := byCustomer[receivables[i].CustomerID]
+		if !ok {
+			row = &model.ReceivableAgingCustomer{CustomerID: receivables[i].CustomerID}
+			byCustomer[receivables[i].CustomerID] = row
+			customerIDs = append(customerIDs, receivables[i].CustomerID)
+		}
+		addAging(&row.ReceivableAgingBuckets, receivables[i].DueDate, asOf, balance)
+		addAging(&report.Totals, receivables[i].DueDate, asOf, balance)
+	}
+
+	var customers []model.Customer
+	if len(customerIDs) > 0 {
+		// Unscoped: piutang pelanggan yang sudah dihapus tetap harus tertagih
+		err := database.DbCore.Unscoped().Select("id", "name").Where("id IN ?", customerIDs).Find(&customers).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, customer := range customers {
+		byCustomer[customer.ID].CustomerName = customer.Name
+	}
+	for _, id := range customerIDs {
+		report.Customers = append(report.Customers, *byCustomer[id])
+	}
+	sort.SliceStable(report.Customers, func(i, j int) bool {
+		return report.Customers[i].Total > report.Customers[j].Total
+	})
+	return &report, nil
+}
+
+// SendReminders emails every customer with overdue receivables one list of what
+// is overdue. Customers reminded within RECEIVABLE_REMINDER_INTERVAL_DAYS are
+// skipped; a failed email is not recorded so the next run tries again. Meant to be
+// called daily by the scheduler.
+func (s *ReceivableService) SendReminders(now time.Time) (*model.ReceivableReminderResult, error) {
+	result := model.ReceivableReminderResult{}
+
+	var overdue []model.Receivable
+	err := database.DbCore.Where("balance > 0 AND due_date < ?", startOfDay(now)).
+		Order("customer_id ASC").Order("due_date ASC").
+		Find(&overdue).Error
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := map[uint][]model.Receivable{}
+	customerIDs := []uint{}
+	for _, receivable := range overdue {
+		if _, ok := grouped[receivable.CustomerID]; !ok {
+			customerIDs = append(customerIDs, receivable.CustomerID)
+		}
+		grouped[receivable.CustomerID] = append(grouped[receivable.CustomerID], receivable)
+	}
+	if len(customerIDs) == 0 {
+		return &result, nil
+	}
+
+	var customers []model.Customer
+	if err := database.DbCore.Where("id IN ?", customerIDs).Find(&customers).Error; err != nil {
+		return nil, err
+	}
+	f, err := os.ReadFile(receivableEmailTemplate)
+	if err != nil {
+		middleware.LogError(err, "Failed open HTML")
+		return nil, err
+	}
+
+	cutoff := now.AddDate(0, 0, -config.RECEIVABLE_REMINDER_INTERVAL_DAYS)
+	for _, customer := range customers {
+		receivables := grouped[customer.ID]
+		if customer.Email == nil || remindedSince(receivables, cutoff) {
+			result.Skipped++
+			continue
+		}
+
+		if !sendReceivableReminder(string(f), &customer, receivables, now) {
+			result.Failed++
+			continue
+		}
+		ids := make([]uint, 0, len(receivables))
+		for _, receivable := range receivables {
+			ids = append(ids, receivable.ID)
+		}
+		err := database.DbCore.Model(&model.Receivable{}).Where("id IN ?", ids).Update("last_reminder_at", now).Error
+		if err != nil {
+			return nil, err
+		}
+		result.Customers++
+		result.Receivables += len(receivables)
+	}
+	return &result, nil
+}
+
+// CustomerStatement builds the customer's statement between from and to (inclusive
+// dates, default from the first of this month until today): the balance brought
+// forward, every credit sale, payment and return credit in the period, and what is
+// still open at the end of it.
+func (s *ReceivableService) CustomerStatement(customerID uint, params model.CustomerStatementQuery, scope model.OutletScope) (*model.CustomerStatement, error) {
+	customer, err := findCustomer(database.DbCore, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	to := startOfDay(time.Now())
+	if params.To != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", params.To, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		to = parsed
+	}
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.Local)
+	if params.From != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", params.From, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return nil, ErrInvalidStatementPeriod
+	}
+	end := to.AddDate(0, 0, 1)
+
+	var receivables []model.Receivable
+	err = scopeOutletColumn(database.DbCore.Model(&model.Receivable{}), "outlet_id", scope).
+		Where("customer_id = ? AND issued_at < ?", customerID, end).
+		Preload("Payments").
+		Order("issued_at ASC").Order("id ASC").
+		Find(&receivables).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statement := model.CustomerStatement{
+		CompanyName:    config.INVOICE_COMPANY_NAME,
+		CompanyAddress: config.INVOICE_COMPANY_ADDRESS,
+		Customer:       *customer,
+		From:           from,
+		To:             to,
+		Entries:        []model.CustomerStatementEntry{},
+		Open:           []model.Receivable{},
+		GeneratedAt:    time.Now(),
+	}
+	for _, receivable := range receivables {
+		if receivable.IssuedAt.Before(from) {
+			statement.OpeningBalance += receivable.Amount
+		} else {
+			statement.Entries = append(statement.Entries, model.CustomerStatementEntry{
+				Date:        receivable.IssuedAt,
+				Reference:   receivable.Number,
+				Description: "Penjualan kredit " + receivable.ReceiptNumber,
+				Debit:       receivable.Amount,
+			})
+		}
+		for _, payment := range receivable.Payments {
+			switch {
+			case !payment.PaidAt.Before(end):
+				continue
+			case payment.PaidAt.Before(from):
+				statement.OpeningBalance -= payment.Amount
+				continue
+			}
+			description := "Pembayaran " + receivable.Number + " (" + payment.Method + ")"
+			if payment.Method == model.ReceivablePaymentReturn {
+				description = "Potongan retur " + receivable.Number
+			}
+			statement.Entries = append(statement.Entries, model.CustomerStatementEntry{
+				Date:        payment.PaidAt,
+				Reference:   payment.Reference,
+				Description: description,
+				Credit:      payment.Amount,
+			})
+		}
+
+		if balance := receivableBalanceAt(&receivable, end); balance > 0 {
+			open := receivable
+			open.Balance = balance
+			open.Payments = nil
+			statement.Open = append(statement.Open, open)
+			addAging(&statement.Aging, receivable.DueDate, to, balance)
+		}
+	}
+
+	sort.SliceStable(statement.Entries, func(i, j int) bool {
+		return statement.Entries[i].Date.Before(statement.Entries[j].Date)
+	})
+	balance := statement.OpeningBalance
+	for i := range statement.Entries {
+		entry := &statement.Entries[i]
+		balance += entry.Debit - entry.Credit
+		entry.Balance = balance
+		statement.TotalDebit += entry.Debit
+		statement.TotalCredit += entry.Credit
+	}
+	statement.ClosingBalance = balance
+	return &statement, nil
+}
+
+// CustomerStatementPDF renders the statement in memory for download.
+func (s *ReceivableService) CustomerStatementPDF(customerID uint, params model.CustomerStatementQuery, scope model.OutletScope) ([]byte, *model.CustomerStatement, error) {
+	statement, err := s.CustomerStatement(customerID, params, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tmpl, err := template.New("customer_statement.html").Funcs(template.FuncMap{
+		"rupiah": formatRupiah,
+	}).ParseFiles(statementTemplatePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	html := new(bytes.Buffer)
+	if err := tmpl.Execute(html, statement); err != nil {
+		return nil, nil, err
+	}
+	pdf, err := thirdparty.GeneratePdfBytes(html.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return pdf, statement, nil
+}
+
+// createSaleReceivable turns the credit tenders of a sale into a receivable due
+// after the customer's payment terms. The customer's open balance plus this sale
+// must stay within their credit limit; the customer row stays locked until commit
+// so two sales cannot both use the last of the limit.
+func createSaleReceivable(tx *gorm.DB, sale *model.Sale) error {
+	var amount int64
+	for _, tender := range sale.Tenders {
+		if tender.Method == model.TenderCredit {
+			amount += tender.Amount
+		}
+	}
+	if amount == 0 {
+		return nil
+	}
+	if sale.CustomerID == nil {
+		return ErrReceivableNoCustomer
+	}
+
+	customer, err := lockCustomer(tx, *sale.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer.CreditLimit <= 0 {
+		return ErrReceivableCreditNotAllowed
+	}
+	var outstanding int64
+	err = tx.Model(&model.Receivable{}).
+		Where("customer_id = ? AND balance > 0", customer.ID).
+		Select("COALESCE(SUM(balance), 0)").Scan(&outstanding).Error
+	if err != nil {
+		return err
+	}
+	if outstanding+amount > customer.CreditLimit {
+		return ErrReceivableCreditLimit
+	}
+
+	terms := customer.PaymentTermDays
+	if terms == 0 {
+		terms = config.RECEIVABLE_DEFAULT_TERM_DAYS
+	}
+	seq, err := nextDocumentNumber(tx, fmt.Sprintf("receivable:%d", sale.SoldAt.Year()))
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&model.Receivable{
+		Number:        fmt.Sprintf("AR-%d-%05d", sale.SoldAt.Year(), seq),
+		CustomerID:    customer.ID,
+		SaleID:        sale.ID,
+		OutletID:      sale.OutletID,
+		ReceiptNumber: sale.ReceiptNumber,
+		Amount:        amount,
+		Balance:       amount,
+		Status:        model.ReceivableOpen,
+		IssuedAt:      sale.SoldAt,
+		DueDate:       startOfDay(sale.SoldAt).AddDate(0, 0, terms),
+	}).Error
+}
+
+// reverseSaleReceivable credits the receivable of the sale with the credit part of
+// an approved void/return. The credit never takes the balance below zero: what the
+// customer already paid for returned goods is settled outside the receivable.
+func reverseSaleReceivable(tx *gorm.DB, sale *model.Sale, reversal *model.SaleReversal, actorID string) error {
+	var amount int64
+	for _, refund := range reversal.Refunds {
+		if refund.Method == model.TenderCredit {
+			amount += refund.Amount
+		}
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	var receivable model.Receivable
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("sale_id = ?", sale.ID).
+		First(&receivable).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	amount = min(amount, receivable.Balance)
+	if amount <= 0 {
+		return nil
+	}
+	credit := model.ReceivablePayment{
+		ReceivableID: receivable.ID,
+		CustomerID:   receivable.CustomerID,
+		Amount:       amount,
+		Method:       model.ReceivablePaymentReturn,
+		Reference:    reversal.Number,
+		PaidAt:       time.Now(),
+		ReceivedBy:   actorID,
+	}
+	if err := tx.Create(&credit).Error; err != nil {
+		return err
+	}
+	receivable.Credited += amount
+	return saveReceivableBalance(tx, &receivable)
+}
+
+// saveReceivableBalance recomputes the balance and status from Paid and Credited.
+func saveReceivableBalance(tx *gorm.DB, receivable *model.Receivable) error {
+	receivable.Balance = receivable.Amount - receivable.Paid - receivable.Credited
+	switch {
+	case receivable.Balance > 0 && receivable.Paid+receivable.Credited == 0:
+		receivable.Status = model.ReceivableOpen
+	case receivable.Balance > 0:
+		receivable.Status = model.ReceivablePartiallyPaid
+	case receivable.Paid == 0:
+		receivable.Status = model.ReceivableCancelled
+	default:
+		receivable.Status = model.ReceivablePaid
+	}
+	return tx.Model(&model.Receivable{}).Where("id = ?", receivable.ID).Updates(map[string]interface{}{
+		"paid":     receivable.Paid,
+		"credited": receivable.Credited,
+		"balance":  receivable.Balance,
+		"status":   receivable.Status,
+	}).Error
+}
+
+// receivablesOutstandingAt loads the receivables issued before end that were
+// still open at end: open now, or settled by a payment made at or after end.
+func receivablesOutstandingAt(query *gorm.DB, end time.Time) ([]model.Receivable, error) {
+	settledLater := database.DbCore.Model(&model.ReceivablePayment{}).
+		Select("receivable_id").
+		Where("paid_at >= ?", end)
+
+	var receivables []model.Receivable
+	err := query.Where("issued_at < ?", end).
+		Where("balance > 0 OR id IN (?)", settledLater).
+		Preload("Payments").
+		Order("due_date ASC").Order("id ASC").
+		Find(&receivables).Error
+	return receivables, err
+}
+
+// receivableBalanceAt is the balance of the receivable counting only payments and
+// credits made before end.
+func receivableBalanceAt(receivable *model.Receivable, end time.Time) int64 {
+	balance := receivable.Amount
+	for _, payment := range receivable.Payments {
+		if payment.PaidAt.Before(end) {
+			balance -= payment.Amount
+		}
+	}
+	return balance
+}
+
+// addAging adds the balance to the bucket for the number of days the due date is
+// past asOf. Receivables that are not due yet go to Current.
+func addAging(buckets *model.ReceivableAgingBuckets, dueDate, asOf time.Time, balance int64) {
+	days := daysPastDue(dueDate, asOf)
+	switch {
+	case days < 0:
+		buckets.Current += balance
+	case days <= 30:
+		buckets.Days0To30 += balance
+	case days <= 60:
+		buckets.Days31To60 += balance
+	case days <= 90:
+		buckets.Days61To90 += balance
+	default:
+		buckets.Over90 += balance
+	}
+	buckets.Total += balance
+}
+
+func remindedSince(receivables []model.Receivable, cutoff time.Time) bool {
+	for _, receivable := range receivables {
+		if receivable.LastReminderAt != nil && receivable.LastReminderAt.After(cutoff) {
+			return true
+		}
+	}
+	return false
+}
+
+func sendReceivableReminder(templateString string, customer *model.Customer, receivables []model.Receivable, now time.Time) bool {
+	var total int64
+	var detail strings.Builder
+	detail.WriteString("<table style=\"border-collapse: collapse; font-size: 14px;\">")
+	detail.WriteString("<tr><th align=\"left\">No. Piutang</th><th align=\"left\">Jatuh tempo</th><th align=\"right\">Sisa tagihan</th></tr>")
+	for _, receivable := range receivables {
+		days := daysPastDue(receivable.DueDate, now)
+		detail.WriteString(fmt.Sprintf(
+			"<tr><td style=\"padding: 2px 12px 2px 0;\">%s</td><td style=\"padding: 2px 12px 2px 0;\">%s (%d hari)</td><td align=\"right\">%s</td></tr>",
+			html.EscapeString(receivable.Number), receivable.DueDate.Format("02 Jan 2006"), days, formatRupiah(receivable.Balance),
+		))
+		total += receivable.Balance
+	}
+	detail.WriteString(fmt.Sprintf("<tr><td colspan=\"2\"><strong>Total</strong></td><td align=\"right\"><strong>%s</strong></td></tr>", formatRupiah(total)))
+	detail.WriteString("</table>")
+
+	opening := "Kami ingin mengingatkan bahwa terdapat " + strconv.Itoa(len(receivables)) +
+		" tagihan yang telah melewati jatuh tempo dengan total " + formatRupiah(total) + ". Mohon segera melakukan pembayaran."
+
+	templateString = strings.Replace(templateString, "{{nama}}", html.EscapeString(customer.Name), 1)
+	templateString = strings.Replace(templateString, "{{Opening_text}}", opening, 1)
+	templateString = strings.Replace(templateString, "{{keterangan}}", detail.String(), 1)
+	templateString = strings.Replace(templateString, "{{Year}}", strconv.Itoa(now.Year()), 1)
+	templateString = strings.Replace(templateString, "{{Link}}", config.PUBLIC_BASE_URL, 1)
+	templateString = strings.Replace(templateString, "{{Nama Sistem}}", config.INVOICE_COMPANY_NAME, 1)
+
+	return thirdparty.SendEmail(templateString, "Pengingat tagihan jatuh tempo", []thirdparty.RecipientStruct{{
+		Name:  customer.Name,
+		Email: *customer.Email,
+	}})
+}
+
+// daysPastDue counts whole days from the due date to at. The due date is a DATE
+// column, so its calendar day is used as is, without converting the time zone.
+func daysPastDue(dueDate, at time.Time) int {
+	due := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.Local)
+	// Dibulatkan supaya hari yang lebih pendek/panjang karena DST tidak menggeser hitungan
+	return int(math.Round(startOfDay(at).Sub(due).Hours() / 24))
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"BackendFramework/internal/model"
+)
+
+func TestDaysPastDue(t *testing.T) {
+	// Kolom DATE dibaca sebagai tengah malam UTC
+	due := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	local := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want int
+	}{
+		{name: "day before the due date", at: local(1, 9, 23), want: -1},
+		{name: "morning of the due date", at: local(1, 10, 0), want: 0},
+		{name: "evening of the due date", at: local(1, 10, 23), want: 0},
+		{name: "thirty days later", at: local(2, 9, 8), want: 30},
+		{name: "thirty one days later", at: local(2, 10, 8), want: 31},
+		{name: "across a daylight saving change", at: local(4, 10, 8), want: 90},
+		{name: "across a change of year", at: time.Date(2027, 1, 10, 8, 0, 0, 0, time.Local), want: 365},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daysPastDue(due, tt.at); got != tt.want {
+				t.Errorf("daysPastDue = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddAging(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 12, 0, 0, 0, time.Local)
+	daysAgo := func(days int) time.Time {
+		return time.Date(2026, 6, 30-days, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		days int
+		want model.ReceivableAgingBuckets
+	}{
+		{name: "not due yet", days: -1, want: model.ReceivableAgingBuckets{Current: 100, Total: 100}},
+		{name: "due today", days: 0, want: model.ReceivableAgingBuckets{Days0To30: 100, Total: 100}},
+		{name: "30 days", days: 30, want: model.ReceivableAgingBuckets{Days0To30: 100, Total: 100}},
+		{name: "31 days", days: 31, want: model.ReceivableAgingBuckets{Days31To60: 100, Total: 100}},
+		{name: "60 days", days: 60, want: model.ReceivableAgingBuckets{Days31To60: 100, Total: 100}},
+		{name: "61 days", days: 61, want: model.ReceivableAgingBuckets{Days61To90: 100, Total: 100}},
+		{name: "90 days", days: 90, want: model.ReceivableAgingBuckets{Days61To90: 100, Total: 100}},
+		{name: "91 days", days: 91, want: model.ReceivableAgingBuckets{Over90: 100, Total: 100}},
+	}
+
+	all := model.ReceivableAgingBuckets{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := model.ReceivableAgingBuckets{}
+			addAging(&buckets, daysAgo(tt.days), asOf, 100)
+			if buckets != tt.want {
+				t.Errorf("buckets = %+v, want %+v", buckets, tt.want)
+			}
+		})
+		addAging(&all, daysAgo(tt.days), asOf, 100)
+	}
+
+	want := model.ReceivableAgingBuckets{Current: 100, Days0To30: 200, Days31To60: 200, Days61To90: 200, Over90: 100, Total: 800}
+	if all != want {
+		t.Errorf("accumulated buckets = %+v, want %+v", all, want)
+	}
+}
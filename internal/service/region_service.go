@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrBrandNotFound  = errors.New("brand not found")
+	ErrRegionNotFound = errors.New("region not found")
+	ErrRegionParent   = errors.New("parent region must belong to the same brand and not be inside this region")
+	ErrRegionNotEmpty = errors.New("region still has sub-regions or outlets")
+	ErrBrandNotEmpty  = errors.New("brand still has regions")
+	ErrOutOfScope     = errors.New("outlet is outside of your regions")
+)
+
+type RegionService struct{}
+
+func NewRegionService() *RegionService {
+	return &RegionService{}
+}
+
+func (s *RegionService) GetBrands() ([]model.Brand, error) {
+	brands := []model.Brand{}
+	if err := database.DbCore.Order("name").Find(&brands).Error; err != nil {
+		return nil, err
+	}
+	return brands, nil
+}
+
+func (s *RegionService) CreateBrand(req model.BrandRequest) (*model.Brand, error) {
+	brand := model.Brand{Name: req.Name, Code: req.Code}
+	if err := database.DbCore.Create(&brand).Error; err != nil {
+		return nil, err
+	}
+	return &brand, nil
+}
+
+func (s *RegionService) UpdateBrand(id uint, req model.BrandRequest) (*model.Brand, error) {
+	var brand model.Brand
+	if err := database.DbCore.First(&brand, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBrandNotFound
+		}
+		return nil, err
+	}
+
+	brand.Name = req.Name
+	brand.Code = req.Code
+	if err := database.DbCore.Save(&brand).Error; err != nil {
+		return nil, err
+	}
+	return &brand, nil
+}
+
+func (s *RegionService) DeleteBrand(id uint) error {
+	var brand model.Brand
+	if err := database.DbCore.First(&brand, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBrandNotFound
+		}
+		return err
+	}
+
+	var regions int64
+	if err := database.DbCore.Model(&model.Region{}).Where("brand_id = ?", id).Count(&regions).Error; err != nil {
+		return err
+	}
+	if regions > 0 {
+		return ErrBrandNotEmpty
+	}
+	return database.DbCore.Delete(&brand).Error
+}
+
+func (s *RegionService) GetRegions(brandID uint) ([]model.Region, error) {
+	regions := []model.Region{}
+	query := database.DbCore.Order("brand_id, name")
+	if brandID != 0 {
+		query = query.Where("brand_id = ?", brandID)
+	}
+	if err := query.Find(&regions).Error; err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
+
+// GetRegionTree returns the regions of a brand (or all brands) nested under their parents.
+func (s *RegionService) GetRegionTree(brandID uint) ([]*model.RegionNode, error) {
+	regions, err := s.GetRegions(brandID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[uint]*model.RegionNode{}
+	for _, region := range regions {
+		nodes[region.ID] = &model.RegionNode{Region: region, Children: []*model.RegionNode{}}
+	}
+
+	roots := []*model.RegionNode{}
+	for _, region := range regions {
+		node := nodes[region.ID]
+		if region.ParentID != nil {
+			if parent, ok := nodes[*region.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+func (s *RegionService) CreateRegion(req model.RegionRequest) (*model.Region, error) {
+	if err := s.validateRegion(0, req); err != nil {
+		return nil, err
+	}
+
+	region := model.Region{
+		BrandID:   req.BrandID,
+		ParentID:  req.ParentID,
+		Name:      req.Name,
+		ManagerID: req.ManagerID,
+	}
+	if err := database.DbCore.Create(&region).Error; err != nil {
+		return nil, err
+	}
+	return &region, nil
+}
+
+func (s *RegionService) UpdateRegion(id uint, req model.RegionRequest) (*model.Region, error) {
+	var region model.Region
+	if err := database.DbCore.First(&region, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRegionNotFound
+		}
+		return nil, err
+	}
+	if err := s.validateRegion(id, req); err != nil {
+		return nil, err
+	}
+
+	region.BrandID = req.BrandID
+	region.ParentID = req.ParentID
+	region.Name = req.Name
+	region.ManagerID = req.ManagerID
+	if err := database.DbCore.Save(&region).Error; err != nil {
+		return nil, err
+	}
+	return &region, nil
+}
+
+func (s *RegionService) DeleteRegion(id uint) error {
+	var region model.Region
+	if err := database.DbCore.First(&region, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRegionNotFound
+		}
+		return err
+	}
+
+	var children, outlets int64
+	if err := database.DbCore.Model(&model.Region{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+		return err
+	}
+	if err := database.DbCore.Model(&model.Outlet{}).Where("region_id = ?", id).Count(&outlets).Error; err != nil {
+		return err
+	}
+	if children > 0 || outlets > 0 {
+		return ErrRegionNotEmpty
+	}
+	return database.DbCore.Delete(&region).Error
+}
+
+// SubtreeIDs returns the given regions together with all of their descendants.
+func (s *RegionService) SubtreeIDs(rootIDs []uint) ([]uint, error) {
+	var regions []model.Region
+	if err := database.DbCore.Select("id", "parent_id").Find(&regions).Error; err != nil {
+		return nil, err
+	}
+
+	children := map[uint][]uint{}
+	for _, region := range regions {
+		if region.ParentID != nil {
+			children[*region.ParentID] = append(children[*region.ParentID], region.ID)
+		}
+	}
+
+	seen := map[uint]bool{}
+	ids := []uint{}
+	queue := append([]uint{}, rootIDs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+		queue = append(queue, children[id]...)
+	}
+	return ids, nil
+}
+
+// ScopeForUser limits region managers to the subtree of the regions they manage.
+// Every other group keeps unrestricted access to the outlet routes.
+func (s *RegionService) ScopeForUser(username string) (model.OutletScope, error) {
+	var user model.User
+	if err := database.DbCore.Where("username = ?", username).First(&user).Error; err != nil {
+		return model.OutletScope{}, err
+	}
+	if user.Group != config.GROUP_REGION_MANAGER {
+		return model.OutletScope{}, nil
+	}
+
+	var managed []uint
+	if err := database.DbCore.Model(&model.Region{}).Where("manager_id = ?", username).Pluck("id", &managed).Error; err != nil {
+		return model.OutletScope{}, err
+	}
+	ids, err := s.SubtreeIDs(managed)
+	if err != nil {
+		return model.OutletScope{}, err
+	}
+	return model.OutletScope{Restricted: true, RegionIDs: ids}, nil
+}
+
+func (s *RegionService) validateRegion(id uint, req model.RegionRequest) error {
+	var brand model.Brand
+	if err := database.DbCore.First(&brand, req.BrandID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrBrandNotFound
+		}
+		return err
+	}
+
+	if req.ParentID == nil {
+		return nil
+	}
+
+	var parent model.Region
+	if err := database.DbCore.First(&parent, *req.ParentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRegionParent
+		}
+		return err
+	}
+	if parent.BrandID != req.BrandID {
+		return ErrRegionParent
+	}
+
+	// Parent tidak boleh berada di dalam subtree region ini sendiri (siklus)
+	if id != 0 {
+		subtree, err := s.SubtreeIDs([]uint{id})
+		if err != nil {
+			return err
+		}
+		for _, regionID := range subtree {
+			if regionID == parent.ID {
+				return ErrRegionParent
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,608 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrSaleReversalNotFound   = errors.New("void or return request not found")
+	ErrSaleReversalPending    = errors.New("sale already has a pending void or return request")
+	ErrSaleReversalReviewed   = errors.New("void or return request has already been reviewed")
+	ErrSaleNotVoidable        = errors.New("only completed sales without returns can be voided")
+	ErrSaleNotReturnable      = errors.New("sale can no longer be returned")
+	ErrSaleDayClosed          = errors.New("sale day is already closed; use a return instead")
+	ErrSaleReversalSelfReview = errors.New("a void or return cannot be approved by the user who requested it")
+	ErrSaleLineNotFound       = errors.New("sale line not found")
+	ErrSaleReturnQuantity     = errors.New("return quantity exceeds the quantity left on the sale line")
+	ErrInvalidReversalPeriod  = errors.New("from must not be after to")
+)
+
+const defaultSaleReversalPageSize = 20
+
+// RequestVoid asks for the whole sale to be cancelled. It is only possible while
+// the sale's business day is still open; afterwards goods come back as a return.
+func (s *SaleService) RequestVoid(saleID uint, req model.SaleVoidRequest, scope model.OutletScope, actorID string) (*model.SaleReversal, error) {
+	var reversal model.SaleReversal
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		sale, err := lockSale(tx, saleID, scope)
+		if err != nil {
+			return err
+		}
+		if sale.Status != model.SaleStatusCompleted {
+			return ErrSaleNotVoidable
+		}
+		if err := checkNoPendingReversal(tx, sale.ID); err != nil {
+			return err
+		}
+		if err := checkSaleVoidable(tx, sale); err != nil {
+			return err
+		}
+
+		lines := []model.SaleReversalLine{}
+		for _, line := range sale.Lines {
+			lines = append(lines, model.SaleReversalLine{
+				SaleLineID: line.ID,
+				ProductID:  line.ProductID,
+				VariantID:  line.VariantID,
+				Quantity:   line.Quantity,
+				Amount:     line.LineTotal,
+			})
+		}
+
+		reversal, err = newSaleReversal(tx, sale, model.SaleReversalVoid, req.ReasonCode, req.ReasonNote, lines, actorID)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&reversal).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reversal, nil
+}
+
+// RequestReturn asks for some or all items of a sale to be taken back. The refund
+// of a line is its share of the line total; the last return of a line refunds
+// whatever is left so rounding never leaks.
+func (s *SaleService) RequestReturn(saleID uint, req model.SaleReturnRequest, scope model.OutletScope, actorID string) (*model.SaleReversal, error) {
+	var reversal model.SaleReversal
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		sale, err := lockSale(tx, saleID, scope)
+		if err != nil {
+			return err
+		}
+		if sale.Status != model.SaleStatusCompleted && sale.Status != model.SaleStatusPartiallyReturned {
+			return ErrSaleNotReturnable
+		}
+		if err := checkNoPendingReversal(tx, sale.ID); err != nil {
+			return err
+		}
+		if err := checkBusinessDayOpen(tx, sale.OutletID, time.Now()); err != nil {
+			return err
+		}
+
+		saleLines := map[uint]model.SaleLine{}
+		for _, line := range sale.Lines {
+			saleLines[line.ID] = line
+		}
+		refunded, err := refundedLineAmounts(tx, sale.ID)
+		if err != nil {
+			return err
+		}
+
+		requested := map[uint]float64{}
+		lines := []model.SaleReversalLine{}
+		for _, input := range req.Lines {
+			line, ok := saleLines[input.SaleLineID]
+			if !ok {
+				return ErrSaleLineNotFound
+			}
+			requested[line.ID] += input.Quantity
+			remaining := line.Quantity - line.ReturnedQuantity
+			if requested[line.ID] > remaining {
+				return ErrSaleReturnQuantity
+			}
+
+			amount := roundRupiah(float64(line.LineTotal) * input.Quantity / line.Quantity)
+			if requested[line.ID] == remaining {
+				amount = line.LineTotal - refunded[line.ID]
+			}
+			refunded[line.ID] += amount
+			lines = append(lines, model.SaleReversalLine{
+				SaleLineID: line.ID,
+				ProductID:  line.ProductID,
+				VariantID:  line.VariantID,
+				Quantity:   input.Quantity,
+				Amount:     amount,
+			})
+		}
+
+		reversal, err = newSaleReversal(tx, sale, model.SaleReversalReturn, req.ReasonCode, req.ReasonNote, lines, actorID)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&reversal).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reversal, nil
+}
+
+// ApproveReversal puts the goods back into stock, marks the returned quantities
+// on the sale, moves the sale to voided / (partially_)returned and credits the
+// refunded credit tender back to the customer's receivable. A cash refund is paid
+// from an open cash shift of the outlet. The requester cannot approve their own
+// request, and the closed-day rules are checked again since the day may have
+// been closed after the request was filed.
+func (s *SaleService) ApproveReversal(id uint, req model.SaleReversalReviewRequest, scope model.OutletScope, reviewerID string) (*model.SaleReversal, error) {
+	var change *tierChange
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		reversal, err := lockPendingReversal(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if reversal.RequestedBy == reviewerID {
+			return ErrSaleReversalSelfReview
+		}
+		sale, err := lockSale(tx, reversal.SaleID, scope)
+		if err != nil {
+			return err
+		}
+		if reversal.Type == model.SaleReversalVoid {
+			if err := checkSaleVoidable(tx, sale); err != nil {
+				return err
+			}
+		} else if err := checkBusinessDayOpen(tx, sale.OutletID, time.Now()); err != nil {
+			return err
+		}
+
+		returned := map[uint]float64{}
+		for _, line := range reversal.Lines {
+			returned[line.SaleLineID] += line.Quantity
+		}
+
+		referenceType := model.StockReferenceSaleReturn
+		if reversal.Type == model.SaleReversalVoid {
+			referenceType = model.StockReferenceSaleVoid
+		}
+		now := time.Now()
+		movements := []model.StockMovement{}
+		fullyReturned := true
+		for i := range sale.Lines {
+			line := &sale.Lines[i]
+			if qty := returned[line.ID]; qty > 0 {
+				line.ReturnedQuantity += qty
+				if err := tx.Model(line).Update("returned_quantity", line.ReturnedQuantity).Error; err != nil {
+					return err
+				}
+				movements = append(movements, model.StockMovement{
+					OutletID:      sale.OutletID,
+					ProductID:     line.ProductID,
+					VariantID:     line.VariantID,
+					Type:          model.StockMovementReturn,
+					Quantity:      qty,
+					ReferenceType: referenceType,
+					ReferenceID:   reversal.ID,
+					Note:          reversal.Number,
+					ActorID:       reviewerID,
+					OccurredAt:    now,
+				})
+			}
+			if line.ReturnedQuantity < line.Quantity {
+				fullyReturned = false
+			}
+		}
+		if err := PostMovements(tx, movements); err != nil {
+			return err
+		}
+
+		status := model.SaleStatusPartiallyReturned
+		switch {
+		case reversal.Type == model.SaleReversalVoid:
+			status = model.SaleStatusVoided
+		case fullyReturned:
+			status = model.SaleStatusReturned
+		}
+		if err := tx.Model(sale).Update("status", status).Error; err != nil {
+			return err
+		}
+		change, err = reverseSaleLoyalty(tx, sale, reversal, status != model.SaleStatusPartiallyReturned, reviewerID)
+		if err != nil {
+			return err
+		}
+		if err := reverseSaleReceivable(tx, sale, reversal, reviewerID); err != nil {
+			return err
+		}
+		if err := attachRefundCashShift(tx, reversal, sale); err != nil {
+			return err
+		}
+		if reversal.Type == model.SaleReversalVoid {
+			if err := releasePromotionUsage(tx, sale.ID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(reversal).Updates(map[string]interface{}{
+			"status":      model.SaleReversalApproved,
+			"reviewed_by": reviewerID,
+			"reviewed_at": now,
+			"review_note": req.Note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if change != nil {
+		go sendTierEmails([]*tierChange{change})
+	}
+	return s.GetReversal(id, scope)
+}
+
+func (s *SaleService) RejectReversal(id uint, req model.SaleReversalReviewRequest, scope model.OutletScope, reviewerID string) (*model.SaleReversal, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		reversal, err := lockPendingReversal(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		return tx.Model(reversal).Updates(map[string]interface{}{
+			"status":      model.SaleReversalRejected,
+			"reviewed_by": reviewerID,
+			"reviewed_at": time.Now(),
+			"review_note": req.Note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetReversal(id, scope)
+}
+
+func (s *SaleService) GetReversals(params model.SaleReversalListQuery, scope model.OutletScope) (*model.SaleReversalListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.SaleReversal{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.Type != "" {
+		query = query.Where("type = ?", params.Type)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultSaleReversalPageSize
+	}
+
+	reversals := []model.SaleReversal{}
+	err := query.Preload("Lines").Preload("Refunds").Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&reversals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SaleReversalListResult{
+		Reversals: reversals,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *SaleService) GetReversal(id uint, scope model.OutletScope) (*model.SaleReversal, error) {
+	var reversal model.SaleReversal
+	err := scopeOutletColumn(database.DbCore.Model(&model.SaleReversal{}), "outlet_id", scope).
+		Preload("Lines").Preload("Refunds").
+		First(&reversal, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSaleReversalNotFound
+		}
+		return nil, err
+	}
+	return &reversal, nil
+}
+
+// GetReversalReport returns void and return rates per outlet and per cashier for
+// sales made between from and to (inclusive dates, default the last 30 days).
+func (s *SaleService) GetReversalReport(params model.SaleReversalReportQuery, scope model.OutletScope) (*model.SaleReversalReport, error) {
+	to := time.Now()
+	if params.To != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", params.To, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		to = parsed
+	}
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local)
+	from := to.AddDate(0, 0, -29)
+	if params.From != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", params.From, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return nil, ErrInvalidReversalPeriod
+	}
+	end := to.AddDate(0, 0, 1)
+
+	byOutlet, err := reversalRates("sales.outlet_id", params.OutletID, from, end, scope)
+	if err != nil {
+		return nil, err
+	}
+	byCashier, err := reversalRates("sales.outlet_id, sales.cashier_id", params.OutletID, from, end, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SaleReversalReport{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		ByOutlet:  byOutlet,
+		ByCashier: byCashier,
+	}, nil
+}
+
+// reversalRates counts sales and approved voids/returns grouped by groupBy
+// (outlet, optionally with cashier). A sale returned twice counts once.
+func reversalRates(groupBy string, outletID uint, from, end time.Time, scope model.OutletScope) ([]model.SaleReversalRate, error) {
+	salesQuery := func() *gorm.DB {
+		query := scopeOutletColumn(database.DbCore.Table("sales"), "sales.outlet_id", scope).
+			Where("sales.sold_at >= ? AND sales.sold_at < ?", from, end)
+		if outletID != 0 {
+			query = query.Where("sales.outlet_id = ?", outletID)
+		}
+		return query
+	}
+
+	var rates []model.SaleReversalRate
+	err := salesQuery().
+		Select(groupBy + ", COUNT(*) AS sale_count, COALESCE(SUM(sales.total), 0) AS sale_total").
+		Group(groupBy).Order(groupBy).
+		Scan(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var reversals []struct {
+		OutletID  uint
+		CashierID string
+		Type      string
+		Count     int64
+		Amount    int64
+	}
+	err = salesQuery().
+		Joins("JOIN sale_reversals ON sale_reversals.sale_id = sales.id AND sale_reversals.status = ?", model.SaleReversalApproved).
+		Select(groupBy + ", sale_reversals.type, COUNT(DISTINCT sales.id) AS count, COALESCE(SUM(sale_reversals.refund_total), 0) AS amount").
+		Group(groupBy + ", sale_reversals.type").
+		Scan(&reversals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	index := map[string]*model.SaleReversalRate{}
+	for i := range rates {
+		index[fmt.Sprintf("%d/%s", rates[i].OutletID, rates[i].CashierID)] = &rates[i]
+	}
+	for _, reversal := range reversals {
+		rate, ok := index[fmt.Sprintf("%d/%s", reversal.OutletID, reversal.CashierID)]
+		if !ok {
+			continue
+		}
+		if reversal.Type == model.SaleReversalVoid {
+			rate.VoidCount, rate.VoidAmount = reversal.Count, reversal.Amount
+		} else {
+			rate.ReturnCount, rate.ReturnAmount = reversal.Count, reversal.Amount
+		}
+	}
+	for i := range rates {
+		if rates[i].SaleCount > 0 {
+			rates[i].VoidRate = float64(rates[i].VoidCount) / float64(rates[i].SaleCount)
+			rates[i].ReturnRate = float64(rates[i].ReturnCount) / float64(rates[i].SaleCount)
+		}
+	}
+	if rates == nil {
+		rates = []model.SaleReversalRate{}
+	}
+	return rates, nil
+}
+
+// newSaleReversal numbers the request and splits its refund over the sale's
+// tenders, in the order they were paid and never above what is left of a tender
+// after earlier refunds. Cash counts net of the change handed out.
+func newSaleReversal(tx *gorm.DB, sale *model.Sale, reversalType, reasonCode, reasonNote string, lines []model.SaleReversalLine, actorID string) (model.SaleReversal, error) {
+	var refundTotal int64
+	for _, line := range lines {
+		refundTotal += line.Amount
+	}
+
+	var refunded []struct {
+		Method string
+		Amount int64
+	}
+	err := tx.Model(&model.SaleRefund{}).
+		Joins("JOIN sale_reversals ON sale_reversals.id = sale_refunds.reversal_id").
+		Where("sale_refunds.sale_id = ? AND sale_reversals.status = ?", sale.ID, model.SaleReversalApproved).
+		Select("sale_refunds.method, SUM(sale_refunds.amount) AS amount").
+		Group("sale_refunds.method").
+		Scan(&refunded).Error
+	if err != nil {
+		return model.SaleReversal{}, err
+	}
+	used := map[string]int64{}
+	for _, row := range refunded {
+		used[row.Method] = row.Amount
+	}
+	refunds := splitRefund(sale, refundTotal, used)
+
+	prefix, sequence := "RT", "sale_return"
+	if reversalType == model.SaleReversalVoid {
+		prefix, sequence = "VD", "sale_void"
+	}
+	now := time.Now()
+	seq, err := nextDocumentNumber(tx, fmt.Sprintf("%s:%d", sequence, now.Year()))
+	if err != nil {
+		return model.SaleReversal{}, err
+	}
+
+	return model.SaleReversal{
+		Number:      fmt.Sprintf("%s-%d-%05d", prefix, now.Year(), seq),
+		Type:        reversalType,
+		SaleID:      sale.ID,
+		OutletID:    sale.OutletID,
+		CashierID:   sale.CashierID,
+		Status:      model.SaleReversalPending,
+		ReasonCode:  reasonCode,
+		ReasonNote:  reasonNote,
+		RequestedBy: actorID,
+		RefundTotal: refundTotal,
+		Lines:       lines,
+		Refunds:     refunds,
+	}, nil
+}
+
+// splitRefund spreads refundTotal over the sale's tenders in the order they were
+// paid. used holds the amount per method already refunded by approved reversals.
+func splitRefund(sale *model.Sale, refundTotal int64, used map[string]int64) []model.SaleRefund {
+	change := sale.Change
+	refunds := []model.SaleRefund{}
+	left := refundTotal
+	for _, tender := range sale.Tenders {
+		available := tender.Amount
+		if tender.Method == model.TenderCash && change > 0 {
+			deduct := min(change, available)
+			available -= deduct
+			change -= deduct
+		}
+		// Refund sebelumnya untuk metode yang sama dipotong dari tender pertama metode itu
+		deduct := min(used[tender.Method], available)
+		available -= deduct
+		used[tender.Method] -= deduct
+
+		amount := min(available, left)
+		if amount <= 0 {
+			continue
+		}
+		refunds = append(refunds, model.SaleRefund{SaleID: sale.ID, Method: tender.Method, Amount: amount})
+		left -= amount
+	}
+	return refunds
+}
+
+// checkSaleVoidable refuses a void once the sale's business day is over: either
+// the day has been closed for the outlet, or the calendar day in the outlet's
+// time zone has passed.
+func checkSaleVoidable(tx *gorm.DB, sale *model.Sale) error {
+	soldOn, err := outletBusinessDate(tx, sale.OutletID, sale.SoldAt)
+	if err != nil {
+		return err
+	}
+	today, err := outletBusinessDate(tx, sale.OutletID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !soldOn.Equal(today) {
+		return ErrSaleDayClosed
+	}
+	closed, err := dailyClosingExists(tx, sale.OutletID, soldOn, "SHARE")
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrSaleDayClosed
+	}
+	return nil
+}
+
+// refundedLineAmounts sums the approved refunds per sale line.
+func refundedLineAmounts(tx *gorm.DB, saleID uint) (map[uint]int64, error) {
+	var rows []struct {
+		SaleLineID uint
+		Amount     int64
+	}
+	err := tx.Model(&model.SaleReversalLine{}).
+		Joins("JOIN sale_reversals ON sale_reversals.id = sale_reversal_lines.reversal_id").
+		Where("sale_reversals.sale_id = ? AND sale_reversals.status = ?", saleID, model.SaleReversalApproved).
+		Select("sale_reversal_lines.sale_line_id, SUM(sale_reversal_lines.amount) AS amount").
+		Group("sale_reversal_lines.sale_line_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	amounts := map[uint]int64{}
+	for _, row := range rows {
+		amounts[row.SaleLineID] = row.Amount
+	}
+	return amounts, nil
+}
+
+func checkNoPendingReversal(tx *gorm.DB, saleID uint) error {
+	var pending int64
+	err := tx.Model(&model.SaleReversal{}).
+		Where("sale_id = ? AND status = ?", saleID, model.SaleReversalPending).
+		Count(&pending).Error
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return ErrSaleReversalPending
+	}
+	return nil
+}
+
+// lockSale loads a sale with its lines and tenders and locks it until tx ends.
+func lockSale(tx *gorm.DB, id uint, scope model.OutletScope) (*model.Sale, error) {
+	var sale model.Sale
+	err := scopeOutletColumn(tx.Model(&model.Sale{}), "outlet_id", scope).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").Preload("Tenders").
+		First(&sale, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSaleNotFound
+		}
+		return nil, err
+	}
+	return &sale, nil
+}
+
+func lockPendingReversal(tx *gorm.DB, id uint, scope model.OutletScope) (*model.SaleReversal, error) {
+	var reversal model.SaleReversal
+	err := scopeOutletColumn(tx.Model(&model.SaleReversal{}), "outlet_id", scope).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").Preload("Refunds").
+		First(&reversal, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSaleReversalNotFound
+		}
+		return nil, err
+	}
+	if reversal.Status != model.SaleReversalPending {
+		return nil, ErrSaleReversalReviewed
+	}
+	return &reversal, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"BackendFramework/internal/model"
+)
+
+func TestSplitRefund(t *testing.T) {
+	refund := func(method string, amount int64) model.SaleRefund {
+		return model.SaleRefund{SaleID: 1, Method: method, Amount: amount}
+	}
+	// Total 66.900: kartu 50.000 + tunai 20.000, kembalian 3.100
+	split := model.Sale{ID: 1, Change: 3100, Tenders: []model.SaleTender{
+		{Method: model.TenderCard, Amount: 50000},
+		{Method: model.TenderCash, Amount: 20000},
+	}}
+
+	tests := []struct {
+		name   string
+		sale   model.Sale
+		amount int64
+		used   map[string]int64
+		want   []model.SaleRefund
+	}{
+		{
+			name:   "full refund, cash net of change",
+			sale:   split,
+			amount: 66900,
+			want:   []model.SaleRefund{refund(model.TenderCard, 50000), refund(model.TenderCash, 16900)},
+		},
+		{
+			name:   "partial refund goes to the first tender",
+			sale:   split,
+			amount: 30000,
+			want:   []model.SaleRefund{refund(model.TenderCard, 30000)},
+		},
+		{
+			name:   "card already refunded in full",
+			sale:   split,
+			amount: 10000,
+			used:   map[string]int64{model.TenderCard: 50000},
+			want:   []model.SaleRefund{refund(model.TenderCash, 10000)},
+		},
+		{
+			name:   "card partly refunded before",
+			sale:   split,
+			amount: 40000,
+			used:   map[string]int64{model.TenderCard: 20000},
+			want:   []model.SaleRefund{refund(model.TenderCard, 30000), refund(model.TenderCash, 10000)},
+		},
+		{
+			name: "earlier refund uses up the first tender of the method",
+			sale: model.Sale{ID: 1, Tenders: []model.SaleTender{
+				{Method: model.TenderCard, Amount: 10000},
+				{Method: model.TenderCard, Amount: 20000},
+			}},
+			amount: 15000,
+			used:   map[string]int64{model.TenderCard: 15000},
+			want:   []model.SaleRefund{refund(model.TenderCard, 15000)},
+		},
+		{
+			name:   "never more than was kept",
+			sale:   model.Sale{ID: 1, Change: 30000, Tenders: []model.SaleTender{{Method: model.TenderCash, Amount: 100000}}},
+			amount: 80000,
+			want:   []model.SaleRefund{refund(model.TenderCash, 70000)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := tt.used
+			if used == nil {
+				used = map[string]int64{}
+			}
+			if got := splitRefund(&tt.sale, tt.amount, used); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitRefund = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,430 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrSaleNotFound         = errors.New("sale not found")
+	ErrSaleClientIDConflict = errors.New("clientTransactionId is already used by another outlet")
+	ErrSaleProductInactive  = errors.New("product is not active")
+	ErrSaleModifierInvalid  = errors.New("modifier does not belong to the product")
+	ErrSaleDiscountTooLarge = errors.New("discount exceeds the line amount")
+	ErrSaleUnderpaid        = errors.New("tenders do not cover the sale total")
+	ErrSaleNonCashChange    = errors.New("change can only be given from cash tenders")
+	ErrSaleSoldAtInvalid    = errors.New("soldAt is in the future or older than the offline sale limit")
+
+	// errSaleReplay membatalkan transaksi DB ketika ClientTransactionID ternyata
+	// sudah diposting request lain yang berjalan bersamaan
+	errSaleReplay = errors.New("sale already posted")
+)
+
+const defaultSalePageSize = 20
+
+type SaleService struct{}
+
+func NewSaleService() *SaleService {
+	return &SaleService{}
+}
+
+// CreateSale posts a POS transaction: it prices the cart from the catalog, applies
+// the promotions, checks the tenders, assigns the next receipt number of the outlet,
+// takes the items out of stock, books credit tenders as a receivable and links the
+// sale to the cashier's open cash shift, all in one transaction. Posting the same
+// clientTransactionId again returns the original sale with created = false.
+func (s *SaleService) CreateSale(req model.SaleRequest, scope model.OutletScope, cashierID string) (*model.Sale, bool, error) {
+	existing, err := findSaleByClientID(database.DbCore, req.ClientTransactionID)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		if existing.OutletID != req.OutletID {
+			return nil, false, ErrSaleClientIDConflict
+		}
+		sale, err := s.GetSale(existing.ID, scope)
+		return sale, false, err
+	}
+
+	var sale model.Sale
+	var change *tierChange
+	err = database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := checkOutletInScope(tx, req.OutletID, scope); err != nil {
+			return err
+		}
+		if req.CustomerID != nil {
+			if _, err := findCustomer(tx, *req.CustomerID); err != nil {
+				return err
+			}
+		}
+
+		lines := []model.SaleLine{}
+		for _, input := range req.Lines {
+			line, err := buildSaleLine(tx, req.OutletID, input)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, line)
+		}
+
+		// Transaksi offline membawa waktu aslinya, tapi tetap tidak boleh jatuh
+		// di hari bisnis yang sudah ditutup
+		soldAt, err := saleTime(req.SoldAt, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := checkBusinessDayOpen(tx, req.OutletID, soldAt); err != nil {
+			return err
+		}
+		evaluation, err := evaluateSalePromotions(tx, req.OutletID, req.CustomerID, req.PromoCodes, lines, soldAt)
+		if err != nil {
+			return err
+		}
+		if err := applyPromoDiscounts(lines, evaluation); err != nil {
+			return err
+		}
+		sale = model.Sale{
+			ClientTransactionID: req.ClientTransactionID,
+			OutletID:            req.OutletID,
+			Status:              model.SaleStatusCompleted,
+			CashierID:           cashierID,
+			CustomerID:          req.CustomerID,
+			Note:                req.Note,
+			SoldAt:              soldAt,
+			Lines:               lines,
+			Promotions:          salePromotions(evaluation),
+		}
+		for _, tender := range req.Tenders {
+			sale.Tenders = append(sale.Tenders, model.SaleTender{
+				Method:    tender.Method,
+				Amount:    tender.Amount,
+				Reference: tender.Reference,
+			})
+		}
+		if err := computeSaleTotals(&sale); err != nil {
+			return err
+		}
+		if err := attachSaleCashShift(tx, &sale); err != nil {
+			return err
+		}
+
+		// Counter struk per outlet dikunci sampai commit, sehingga sekaligus
+		// menyerialkan pengecekan ClientTransactionID di bawah ini
+		seq, err := nextDocumentNumber(tx, fmt.Sprintf("receipt:%d", req.OutletID))
+		if err != nil {
+			return err
+		}
+		replay, err := findSaleByClientID(tx.Clauses(clause.Locking{Strength: "SHARE"}), req.ClientTransactionID)
+		if err != nil {
+			return err
+		}
+		if replay != nil {
+			existing = replay
+			return errSaleReplay
+		}
+		sale.ReceiptNumber = fmt.Sprintf("R%d-%06d", req.OutletID, seq)
+
+		if err := tx.Create(&sale).Error; err != nil {
+			return err
+		}
+		if err := claimPromotionUsage(tx, sale.Promotions); err != nil {
+			return err
+		}
+		if err := PostMovements(tx, saleMovements(&sale, model.StockMovementSale, -1, cashierID, sale.SoldAt)); err != nil {
+			return err
+		}
+		if err := createSaleReceivable(tx, &sale); err != nil {
+			return err
+		}
+		change, err = applySaleLoyalty(tx, &sale, cashierID)
+		return err
+	})
+	if errors.Is(err, errSaleReplay) {
+		if existing.OutletID != req.OutletID {
+			return nil, false, ErrSaleClientIDConflict
+		}
+		replayed, err := s.GetSale(existing.ID, scope)
+		return replayed, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if change != nil {
+		// Email dikirim di background supaya kasir tidak menunggu SMTP
+		go sendTierEmails([]*tierChange{change})
+	}
+	return &sale, true, nil
+}
+
+func (s *SaleService) GetSales(params model.SaleListQuery, scope model.OutletScope) (*model.SaleListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.Sale{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+	if params.CashierID != "" {
+		query = query.Where("cashier_id = ?", params.CashierID)
+	}
+	if params.CustomerID != 0 {
+		query = query.Where("customer_id = ?", params.CustomerID)
+	}
+	if params.From != "" {
+		from, err := time.Parse(time.RFC3339, params.From)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("sold_at >= ?", from)
+	}
+	if params.To != "" {
+		to, err := time.Parse(time.RFC3339, params.To)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("sold_at <= ?", to)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultSalePageSize
+	}
+
+	sales := []model.Sale{}
+	err := query.Preload("Lines").Preload("Tenders").Preload("Promotions").
+		Order("sold_at DESC").Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.SaleListResult{
+		Sales: sales,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *SaleService) GetSale(id uint, scope model.OutletScope) (*model.Sale, error) {
+	var sale model.Sale
+	err := scopeOutletColumn(database.DbCore.Model(&model.Sale{}), "outlet_id", scope).
+		Preload("Lines").Preload("Tenders").Preload("Promotions").
+		First(&sale, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSaleNotFound
+		}
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// GetSaleByClientID lets a POS check whether a transaction it lost the response
+// for was posted.
+func (s *SaleService) GetSaleByClientID(clientID string, scope model.OutletScope) (*model.Sale, error) {
+	sale, err := findSaleByClientID(database.DbCore, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if sale == nil {
+		return nil, ErrSaleNotFound
+	}
+	return s.GetSale(sale.ID, scope)
+}
+
+// buildSaleLine prices one cart line at the outlet price and snapshots the
+// catalog data onto it. Tax is computed on the amount after discount.
+func buildSaleLine(tx *gorm.DB, outletID uint, input model.SaleLineInput) (model.SaleLine, error) {
+	var product model.Product
+	if err := tx.First(&product, input.ProductID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.SaleLine{}, ErrProductNotFound
+		}
+		return model.SaleLine{}, err
+	}
+	variant, err := findProductVariant(tx, input.ProductID, input.VariantID)
+	if err != nil {
+		return model.SaleLine{}, err
+	}
+	if !product.Active || (variant != nil && !variant.Active) {
+		return model.SaleLine{}, ErrSaleProductInactive
+	}
+
+	price, err := ResolvePrice(tx, input.ProductID, input.VariantID, outletID)
+	if err != nil {
+		return model.SaleLine{}, err
+	}
+
+	line := model.SaleLine{
+		ProductID: product.ID,
+		VariantID: input.VariantID,
+		Name:      product.Name,
+		SKU:       product.SKU,
+		Quantity:  input.Quantity,
+		UnitPrice: price.Price,
+		Modifiers: []model.SaleLineModifier{},
+		Discount:  input.Discount,
+		TaxRate:   price.TaxRate,
+	}
+	if variant != nil {
+		line.Name = product.Name + " - " + variant.Name
+		line.SKU = variant.SKU
+	}
+
+	if len(input.ModifierIDs) > 0 {
+		var modifiers []model.ProductModifier
+		err := tx.Where("id IN ? AND product_id = ? AND active = ?", input.ModifierIDs, product.ID, true).
+			Find(&modifiers).Error
+		if err != nil {
+			return model.SaleLine{}, err
+		}
+		if len(modifiers) != len(uniqueIDs(input.ModifierIDs)) {
+			return model.SaleLine{}, ErrSaleModifierInvalid
+		}
+		for _, modifier := range modifiers {
+			line.Modifiers = append(line.Modifiers, model.SaleLineModifier{
+				ModifierID: modifier.ID,
+				Name:       modifier.Name,
+				Price:      modifier.Price,
+			})
+			line.UnitPrice += modifier.Price
+		}
+	}
+
+	if err := priceSaleLine(&line); err != nil {
+		return model.SaleLine{}, err
+	}
+	return line, nil
+}
+
+// saleTime returns when the sale happened: the POS time of an offline sale, or
+// now. The POS clock may run SALE_CLOCK_SKEW_MINUTES ahead, and an offline sale
+// may be at most SALE_OFFLINE_MAX_HOURS old.
+func saleTime(soldAt *time.Time, now time.Time) (time.Time, error) {
+	if soldAt == nil {
+		return now, nil
+	}
+	if soldAt.After(now.Add(time.Duration(config.SALE_CLOCK_SKEW_MINUTES) * time.Minute)) {
+		return time.Time{}, ErrSaleSoldAtInvalid
+	}
+	if soldAt.Before(now.Add(-time.Duration(config.SALE_OFFLINE_MAX_HOURS) * time.Hour)) {
+		return time.Time{}, ErrSaleSoldAtInvalid
+	}
+	// Waktu perangkat yang sedikit maju tidak boleh mendahului jam server
+	if soldAt.After(now) {
+		return now, nil
+	}
+	return *soldAt, nil
+}
+
+// priceSaleLine computes tax and total from the unit price, quantity and discount.
+// Tax is charged on the discounted amount.
+func priceSaleLine(line *model.SaleLine) error {
+	gross := roundRupiah(float64(line.UnitPrice) * line.Quantity)
+	if line.Discount > gross {
+		return ErrSaleDiscountTooLarge
+	}
+	line.TaxAmount = roundRupiah(float64(gross-line.Discount) * line.TaxRate / 100)
+	line.LineTotal = gross - line.Discount + line.TaxAmount
+	return nil
+}
+
+// computeSaleTotals sums the lines and checks the tenders: they must cover the
+// total, and only the cash part can be handed back as change.
+func computeSaleTotals(sale *model.Sale) error {
+	sale.Subtotal, sale.DiscountTotal, sale.TaxTotal, sale.Total = 0, 0, 0, 0
+	for _, line := range sale.Lines {
+		sale.Subtotal += line.LineTotal + line.Discount - line.TaxAmount
+		sale.DiscountTotal += line.Discount
+		sale.TaxTotal += line.TaxAmount
+		sale.Total += line.LineTotal
+	}
+
+	var cash int64
+	sale.Paid = 0
+	for _, tender := range sale.Tenders {
+		sale.Paid += tender.Amount
+		if tender.Method == model.TenderCash {
+			cash += tender.Amount
+		}
+	}
+	if sale.Paid < sale.Total {
+		return ErrSaleUnderpaid
+	}
+	sale.Change = sale.Paid - sale.Total
+	if sale.Change > cash {
+		return ErrSaleNonCashChange
+	}
+	return nil
+}
+
+// saleMovements builds one stock movement per line; sign is -1 for items leaving
+// the outlet and +1 for items coming back.
+func saleMovements(sale *model.Sale, movementType string, sign float64, actorID string, at time.Time) []model.StockMovement {
+	movements := []model.StockMovement{}
+	for _, line := range sale.Lines {
+		movements = append(movements, model.StockMovement{
+			OutletID:      sale.OutletID,
+			ProductID:     line.ProductID,
+			VariantID:     line.VariantID,
+			Type:          movementType,
+			Quantity:      sign * line.Quantity,
+			ReferenceType: model.StockReferenceSale,
+			ReferenceID:   sale.ID,
+			Note:          sale.ReceiptNumber,
+			ActorID:       actorID,
+			OccurredAt:    at,
+		})
+	}
+	return movements
+}
+
+func findSaleByClientID(db *gorm.DB, clientID string) (*model.Sale, error) {
+	var sale model.Sale
+	err := db.Select("id", "outlet_id").Where("client_transaction_id = ?", clientID).First(&sale).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &sale, nil
+}
+
+func roundRupiah(amount float64) int64 {
+	return int64(math.Round(amount))
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := map[uint]bool{}
+	unique := []uint{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
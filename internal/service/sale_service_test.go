@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"BackendFramework/internal/config"
+	"BackendFramework/internal/model"
+)
+
+func TestPriceSaleLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      model.SaleLine
+		wantTax   int64
+		wantTotal int64
+		wantErr   error
+	}{
+		{name: "tax on the full amount", line: model.SaleLine{UnitPrice: 10000, Quantity: 3, TaxRate: 11}, wantTax: 3300, wantTotal: 33300},
+		{name: "tax on the discounted amount", line: model.SaleLine{UnitPrice: 12345, Quantity: 1.5, Discount: 518, TaxRate: 10}, wantTax: 1800, wantTotal: 19800},
+		{name: "tax is rounded to the rupiah", line: model.SaleLine{UnitPrice: 999, Quantity: 1, TaxRate: 11}, wantTax: 110, wantTotal: 1109},
+		{name: "discount equal to the amount", line: model.SaleLine{UnitPrice: 5000, Quantity: 2, Discount: 10000, TaxRate: 11}, wantTax: 0, wantTotal: 0},
+		{name: "discount larger than the amount", line: model.SaleLine{UnitPrice: 5000, Quantity: 2, Discount: 10001}, wantErr: ErrSaleDiscountTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := tt.line
+			err := priceSaleLine(&line)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if line.TaxAmount != tt.wantTax || line.LineTotal != tt.wantTotal {
+				t.Errorf("tax, total = %d, %d, want %d, %d", line.TaxAmount, line.LineTotal, tt.wantTax, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestComputeSaleTotals(t *testing.T) {
+	// Subtotal 48.518, diskon 518, pajak 5.100, total 53.100
+	lines := []model.SaleLine{
+		{LineTotal: 33300, TaxAmount: 3300},
+		{LineTotal: 19800, Discount: 518, TaxAmount: 1800},
+	}
+
+	tests := []struct {
+		name       string
+		tenders    []model.SaleTender
+		wantPaid   int64
+		wantChange int64
+		wantErr    error
+	}{
+		{name: "cash with change", tenders: []model.SaleTender{{Method: model.TenderCash, Amount: 60000}}, wantPaid: 60000, wantChange: 6900},
+		{name: "exact card payment", tenders: []model.SaleTender{{Method: model.TenderCard, Amount: 53100}}, wantPaid: 53100},
+		{name: "split payment, change from the cash part", tenders: []model.SaleTender{{Method: model.TenderCard, Amount: 50000}, {Method: model.TenderCash, Amount: 10000}}, wantPaid: 60000, wantChange: 6900},
+		{name: "underpaid", tenders: []model.SaleTender{{Method: model.TenderCard, Amount: 50000}, {Method: model.TenderCash, Amount: 3000}}, wantErr: ErrSaleUnderpaid},
+		{name: "change larger than the cash part", tenders: []model.SaleTender{{Method: model.TenderCard, Amount: 58000}, {Method: model.TenderCash, Amount: 2000}}, wantErr: ErrSaleNonCashChange},
+		{name: "no tenders", wantErr: ErrSaleUnderpaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sale := model.Sale{Lines: lines, Tenders: tt.tenders}
+			err := computeSaleTotals(&sale)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if sale.Subtotal != 48518 || sale.DiscountTotal != 518 || sale.TaxTotal != 5100 || sale.Total != 53100 {
+				t.Errorf("totals = %d/%d/%d/%d, want 48518/518/5100/53100", sale.Subtotal, sale.DiscountTotal, sale.TaxTotal, sale.Total)
+			}
+			if tt.wantErr == nil && (sale.Paid != tt.wantPaid || sale.Change != tt.wantChange) {
+				t.Errorf("paid, change = %d, %d, want %d, %d", sale.Paid, sale.Change, tt.wantPaid, tt.wantChange)
+			}
+		})
+	}
+}
+
+func TestSaleTime(t *testing.T) {
+	config.SALE_CLOCK_SKEW_MINUTES = 5
+	config.SALE_OFFLINE_MAX_HOURS = 72
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+
+	tests := []struct {
+		name    string
+		soldAt  *time.Time
+		want    time.Time
+		wantErr error
+	}{
+		{name: "online sale uses the server time", want: now},
+		{name: "offline sale keeps its own time", soldAt: at(-26 * time.Hour), want: now.Add(-26 * time.Hour)},
+		{name: "device clock slightly ahead is clamped to now", soldAt: at(3 * time.Minute), want: now},
+		{name: "future time beyond the skew", soldAt: at(6 * time.Minute), wantErr: ErrSaleSoldAtInvalid},
+		{name: "exactly the offline limit", soldAt: at(-72 * time.Hour), want: now.Add(-72 * time.Hour)},
+		{name: "older than the offline limit", soldAt: at(-73 * time.Hour), wantErr: ErrSaleSoldAtInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := saleTime(tt.soldAt, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !got.Equal(tt.want) {
+				t.Errorf("saleTime = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
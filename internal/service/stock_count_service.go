@@ -0,0 +1,539 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/middleware"
+	"BackendFramework/internal/model"
+	"BackendFramework/internal/thirdparty"
+)
+
+var (
+	ErrStockCountNotFound       = errors.New("stock count not found")
+	ErrStockCountStatus         = errors.New("stock count is no longer open")
+	ErrStockCountOpen           = errors.New("outlet already has an open stock count")
+	ErrStockCountNothingCounted = errors.New("stock count has no counted items")
+)
+
+const (
+	StockCountSheetName       = "Stock Count"
+	stockCountVarianceTplPath = "./web/html/stock_count_variance.html"
+	defaultStockCountPageSize = 20
+)
+
+type StockCountService struct{}
+
+func NewStockCountService() *StockCountService {
+	return &StockCountService{}
+}
+
+func (s *StockCountService) GetCounts(params model.StockCountListQuery, scope model.OutletScope) (*model.StockCountListResult, error) {
+	query := scopeOutletColumn(database.DbCore.Model(&model.StockCount{}), "outlet_id", scope)
+	if params.OutletID != 0 {
+		query = query.Where("outlet_id = ?", params.OutletID)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultStockCountPageSize
+	}
+
+	counts := []model.StockCount{}
+	err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StockCountListResult{
+		Counts: counts,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *StockCountService) GetCount(id uint, scope model.OutletScope) (*model.StockCount, error) {
+	var count model.StockCount
+	err := scopeOutletColumn(database.DbCore.Model(&model.StockCount{}), "outlet_id", scope).
+		Preload("Lines").
+		First(&count, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStockCountNotFound
+		}
+		return nil, err
+	}
+	return &count, nil
+}
+
+// CreateCount opens a session and freezes the current stock of the outlet as the
+// expected quantities. Only one session per outlet can be open at a time.
+func (s *StockCountService) CreateCount(req model.StockCountRequest, scope model.OutletScope, actorID string) (*model.StockCount, error) {
+	var count model.StockCount
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := checkOutletInScope(tx, req.OutletID, scope); err != nil {
+			return err
+		}
+
+		// Baris outlet dikunci supaya dua permintaan tidak sama-sama membuka sesi
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&model.Outlet{}, req.OutletID).Error; err != nil {
+			return err
+		}
+
+		var open int64
+		err := tx.Model(&model.StockCount{}).
+			Where("outlet_id = ? AND status = ?", req.OutletID, model.StockCountCounting).
+			Count(&open).Error
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return ErrStockCountOpen
+		}
+
+		// Kunci saldo outlet supaya snapshot konsisten dengan waktu SnapshotAt
+		var levels []model.StockLevel
+		err = tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("outlet_id = ?", req.OutletID).
+			Order("product_id, variant_id").
+			Find(&levels).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		seq, err := nextDocumentNumber(tx, fmt.Sprintf("%s:%d", model.StockReferenceCount, now.Year()))
+		if err != nil {
+			return err
+		}
+
+		lines := []model.StockCountLine{}
+		for _, level := range levels {
+			lines = append(lines, model.StockCountLine{
+				ProductID:        level.ProductID,
+				VariantID:        level.VariantID,
+				ExpectedQuantity: level.Quantity,
+			})
+		}
+
+		count = model.StockCount{
+			Number:     fmt.Sprintf("SO-%d-%05d", now.Year(), seq),
+			OutletID:   req.OutletID,
+			Status:     model.StockCountCounting,
+			Note:       req.Note,
+			SnapshotAt: now,
+			CreatedBy:  actorID,
+			Lines:      lines,
+		}
+		return tx.Create(&count).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// EnterCounts records counted quantities. Counting an item again overwrites the
+// previous figure; items missing from the snapshot are added with expected 0.
+func (s *StockCountService) EnterCounts(id uint, req model.StockCountEntryRequest, scope model.OutletScope, actorID string) (*model.StockCount, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		count, err := lockStockCount(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		return applyCountEntries(tx, count, req.Lines, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCount(id, scope)
+}
+
+// ImportCounts reads counted quantities from the "SKU" and "Counted Quantity"
+// columns of an uploaded sheet. Nothing is saved when any row is invalid.
+func (s *StockCountService) ImportCounts(id uint, rows []map[string]interface{}, scope model.OutletScope, actorID string) (*model.StockCount, []model.StockCountUploadError, error) {
+	entries := []model.StockCountEntry{}
+	rowErrors := []model.StockCountUploadError{}
+	for i, row := range rows {
+		sku := excelCell(row, "SKU")
+		quantity := excelCell(row, "Counted Quantity")
+		// Baris kosong dilewati, begitu juga item yang tidak diisi jumlahnya
+		if sku == "" || quantity == "" {
+			continue
+		}
+
+		// Baris 1 adalah header
+		rowNumber := i + 2
+		qty, err := strconv.ParseFloat(quantity, 64)
+		if err != nil || qty < 0 {
+			rowErrors = append(rowErrors, model.StockCountUploadError{Row: rowNumber, SKU: sku, Message: "Counted Quantity must be a number of at least 0"})
+			continue
+		}
+		productID, variantID, err := findProductIDsByCode(database.DbCore, sku)
+		if err != nil {
+			if !errors.Is(err, ErrProductNotFound) {
+				return nil, nil, err
+			}
+			rowErrors = append(rowErrors, model.StockCountUploadError{Row: rowNumber, SKU: sku, Message: "unknown SKU or barcode"})
+			continue
+		}
+		entries = append(entries, model.StockCountEntry{ProductID: productID, VariantID: variantID, Quantity: qty})
+	}
+	if len(rowErrors) > 0 {
+		return nil, rowErrors, nil
+	}
+	if len(entries) == 0 {
+		return nil, nil, ErrStockCountNothingCounted
+	}
+
+	count, err := s.EnterCounts(id, model.StockCountEntryRequest{Lines: entries}, scope, actorID)
+	return count, nil, err
+}
+
+// ApproveCount posts an adjustment for every counted line with a variance and
+// closes the session. Stock that moved between the snapshot and the moment an
+// item was counted is part of the expected quantity, and the adjustment is dated
+// at that moment so stock as of any earlier date is unaffected. The variance
+// report is archived to the bucket afterwards; a failed upload does not undo
+// the approval.
+func (s *StockCountService) ApproveCount(id uint, scope model.OutletScope, actorID string) (*model.StockCount, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		count, err := lockStockCount(tx, id, scope)
+		if err != nil {
+			return err
+		}
+
+		moved, err := stockMovedSinceSnapshot(tx, count)
+		if err != nil {
+			return err
+		}
+		settleCountLines(count, moved)
+
+		movements := stockCountAdjustments(count, actorID)
+		counted := 0
+		for i := range count.Lines {
+			line := &count.Lines[i]
+			if line.CountedQuantity == nil {
+				continue
+			}
+			counted++
+			err := tx.Model(line).Updates(map[string]interface{}{
+				"moved_quantity": line.MovedQuantity,
+				"variance":       line.Variance,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if counted == 0 {
+			return ErrStockCountNothingCounted
+		}
+		if err := PostMovements(tx, movements); err != nil {
+			return err
+		}
+
+		return tx.Model(count).Updates(map[string]interface{}{
+			"status":      model.StockCountApproved,
+			"approved_by": actorID,
+			"approved_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.GetCount(id, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.VarianceReport(id, scope)
+	if err == nil {
+		key := "stock-counts/" + count.Number + ".pdf"
+		err = thirdparty.UploadBytesBucket(report, key, "application/pdf")
+		if err == nil {
+			count.ReportKey = key
+			err = database.DbCore.Model(count).Update("report_key", key).Error
+		}
+	}
+	if err != nil {
+		middleware.LogError(err, "Failed to archive stock count variance report")
+	}
+	return count, nil
+}
+
+func (s *StockCountService) CancelCount(id uint, scope model.OutletScope) (*model.StockCount, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		count, err := lockStockCount(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		return tx.Model(count).Update("status", model.StockCountCancelled).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCount(id, scope)
+}
+
+type stockCountReportRow struct {
+	SKU           string
+	Name          string
+	Expected      string
+	Moved         string
+	Counted       string
+	Variance      string
+	VarianceValue int64
+}
+
+// VarianceReport renders the variance of every line as a PDF, valued at the
+// outlet's current selling price.
+func (s *StockCountService) VarianceReport(id uint, scope model.OutletScope) ([]byte, error) {
+	count, err := s.GetCount(id, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	var outlet model.Outlet
+	if err := database.DbCore.Select("id", "name").First(&outlet, count.OutletID).Error; err != nil {
+		return nil, err
+	}
+
+	productIDs := []uint{}
+	for _, line := range count.Lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	var products []model.Product
+	err = database.DbCore.Unscoped().Preload("Variants", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("id IN ?", productIDs).Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	productByID := map[uint]model.Product{}
+	for _, product := range products {
+		productByID[product.ID] = product
+	}
+
+	rows := []stockCountReportRow{}
+	var totalValue int64
+	uncounted := 0
+	for _, line := range count.Lines {
+		product := productByID[line.ProductID]
+		row := stockCountReportRow{
+			SKU:      product.SKU,
+			Name:     product.Name,
+			Expected: formatQuantity(line.ExpectedQuantity),
+			Moved:    formatQuantity(line.MovedQuantity),
+			Counted:  "-",
+			Variance: "-",
+		}
+		for _, variant := range product.Variants {
+			if variant.ID == line.VariantID {
+				row.SKU = variant.SKU
+				row.Name = product.Name + " - " + variant.Name
+			}
+		}
+
+		if line.CountedQuantity == nil {
+			uncounted++
+		} else {
+			row.Counted = formatQuantity(*line.CountedQuantity)
+			row.Variance = formatQuantity(*line.Variance)
+			price, err := ResolvePrice(database.DbCore.Unscoped(), line.ProductID, line.VariantID, count.OutletID)
+			if err == nil {
+				row.VarianceValue = int64(*line.Variance * float64(price.Price))
+				totalValue += row.VarianceValue
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	tmpl, err := template.ParseFiles(stockCountVarianceTplPath)
+	if err != nil {
+		return nil, err
+	}
+	html := new(bytes.Buffer)
+	err = tmpl.Execute(html, map[string]interface{}{
+		"Count":      count,
+		"OutletName": outlet.Name,
+		"Rows":       rows,
+		"TotalValue": totalValue,
+		"Uncounted":  uncounted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return thirdparty.GeneratePdfBytes(html.String())
+}
+
+// applyCountEntries writes counted quantities onto the lines of an open session.
+func applyCountEntries(tx *gorm.DB, count *model.StockCount, entries []model.StockCountEntry, actorID string) error {
+	lines := map[string]*model.StockCountLine{}
+	for i := range count.Lines {
+		line := &count.Lines[i]
+		lines[stockKey(count.OutletID, line.ProductID, line.VariantID)] = line
+	}
+
+	moved, err := stockMovedSinceSnapshot(tx, count)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		line, ok := lines[stockKey(count.OutletID, entry.ProductID, entry.VariantID)]
+		if !ok {
+			if _, err := findProductVariant(tx, entry.ProductID, entry.VariantID); err != nil {
+				return err
+			}
+			line = &model.StockCountLine{
+				CountID:   count.ID,
+				ProductID: entry.ProductID,
+				VariantID: entry.VariantID,
+			}
+			lines[stockKey(count.OutletID, entry.ProductID, entry.VariantID)] = line
+		}
+
+		counted := entry.Quantity
+		line.CountedQuantity = &counted
+		line.CountedBy = actorID
+		line.CountedAt = &now
+		settleCountLine(count.OutletID, line, moved)
+		if err := tx.Save(line).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stockMovedSinceSnapshot returns the movements posted at the outlet after the
+// session froze its expected quantities. Posting time is used rather than
+// OccurredAt because the snapshot was taken from the cached levels.
+func stockMovedSinceSnapshot(tx *gorm.DB, count *model.StockCount) ([]model.StockMovement, error) {
+	var movements []model.StockMovement
+	err := tx.Select("outlet_id", "product_id", "variant_id", "quantity", "created_at").
+		Where("outlet_id = ? AND created_at > ?", count.OutletID, count.SnapshotAt).
+		Where("NOT (reference_type = ? AND reference_id = ?)", model.StockReferenceCount, count.ID).
+		Find(&movements).Error
+	return movements, err
+}
+
+// settleCountLines recomputes MovedQuantity and Variance of every counted line.
+func settleCountLines(count *model.StockCount, moved []model.StockMovement) {
+	for i := range count.Lines {
+		settleCountLine(count.OutletID, &count.Lines[i], moved)
+	}
+}
+
+// settleCountLine adds up the movements of the line's item posted up to the
+// moment it was counted and derives the variance from them.
+func settleCountLine(outletID uint, line *model.StockCountLine, moved []model.StockMovement) {
+	if line.CountedQuantity == nil || line.CountedAt == nil {
+		return
+	}
+	line.MovedQuantity = 0
+	for _, movement := range moved {
+		if movement.OutletID != outletID || movement.ProductID != line.ProductID || movement.VariantID != line.VariantID {
+			continue
+		}
+		if movement.CreatedAt.After(*line.CountedAt) {
+			continue
+		}
+		line.MovedQuantity += movement.Quantity
+	}
+	variance := *line.CountedQuantity - line.ExpectedQuantity - line.MovedQuantity
+	line.Variance = &variance
+}
+
+// stockCountAdjustments builds the ledger adjustments of the settled counted lines.
+func stockCountAdjustments(count *model.StockCount, actorID string) []model.StockMovement {
+	movements := []model.StockMovement{}
+	for _, line := range count.Lines {
+		if line.CountedQuantity == nil || line.Variance == nil || *line.Variance == 0 {
+			continue
+		}
+		movements = append(movements, model.StockMovement{
+			OutletID:      count.OutletID,
+			ProductID:     line.ProductID,
+			VariantID:     line.VariantID,
+			Type:          model.StockMovementAdjustment,
+			Quantity:      *line.Variance,
+			ReferenceType: model.StockReferenceCount,
+			ReferenceID:   count.ID,
+			Note:          count.Number,
+			ActorID:       actorID,
+			OccurredAt:    *line.CountedAt,
+		})
+	}
+	return movements
+}
+
+// lockStockCount loads an open session with its lines and locks it until tx ends.
+func lockStockCount(tx *gorm.DB, id uint, scope model.OutletScope) (*model.StockCount, error) {
+	var count model.StockCount
+	err := scopeOutletColumn(tx.Model(&model.StockCount{}), "outlet_id", scope).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").
+		First(&count, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStockCountNotFound
+		}
+		return nil, err
+	}
+	if count.Status != model.StockCountCounting {
+		return nil, ErrStockCountStatus
+	}
+	return &count, nil
+}
+
+// findProductIDsByCode resolves a SKU or barcode to a product and, when the code
+// belongs to a variant, its variant ID.
+func findProductIDsByCode(db *gorm.DB, code string) (uint, uint, error) {
+	var product model.Product
+	err := db.Select("id").Where("sku = ? OR barcode = ?", code, code).First(&product).Error
+	if err == nil {
+		return product.ID, 0, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, 0, err
+	}
+
+	var variant model.ProductVariant
+	if err := db.Select("id", "product_id").Where("sku = ? OR barcode = ?", code, code).First(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, 0, ErrProductNotFound
+		}
+		return 0, 0, err
+	}
+	return variant.ProductID, variant.ID, nil
+}
+
+func formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', -1, 64)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"BackendFramework/internal/model"
+)
+
+func TestStockCountAdjustments(t *testing.T) {
+	snapshot := time.Date(2026, 5, 4, 8, 0, 0, 0, time.Local)
+	countedAt := snapshot.Add(2 * time.Hour)
+	qty := func(q float64) *float64 { return &q }
+	movement := func(productID uint, quantity float64, postedAt time.Time) model.StockMovement {
+		return model.StockMovement{OutletID: 1, ProductID: productID, Quantity: quantity, CreatedAt: postedAt}
+	}
+
+	tests := []struct {
+		name         string
+		expected     float64
+		counted      *float64
+		moved        []model.StockMovement
+		wantMoved    float64
+		wantVariance *float64
+	}{
+		{
+			name:         "sales while counting are not a variance",
+			expected:     10,
+			counted:      qty(7),
+			moved:        []model.StockMovement{movement(1, -3, snapshot.Add(time.Hour))},
+			wantMoved:    -3,
+			wantVariance: qty(0),
+		},
+		{
+			name:     "shrinkage on top of sales while counting",
+			expected: 10,
+			counted:  qty(5),
+			moved: []model.StockMovement{
+				movement(1, -3, snapshot.Add(time.Hour)),
+				movement(1, 4, snapshot.Add(90*time.Minute)),
+			},
+			wantMoved:    1,
+			wantVariance: qty(-6),
+		},
+		{
+			name:     "movements after the item was counted are left out",
+			expected: 10,
+			counted:  qty(10),
+			moved: []model.StockMovement{
+				movement(1, -2, countedAt.Add(time.Minute)),
+				movement(2, -5, snapshot.Add(time.Hour)),
+			},
+			wantMoved:    0,
+			wantVariance: qty(0),
+		},
+		{
+			name:         "uncounted line is left alone",
+			expected:     10,
+			moved:        []model.StockMovement{movement(1, -3, snapshot.Add(time.Hour))},
+			wantMoved:    0,
+			wantVariance: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := model.StockCountLine{ProductID: 1, ExpectedQuantity: tt.expected, CountedQuantity: tt.counted}
+			if tt.counted != nil {
+				line.CountedAt = &countedAt
+			}
+			count := &model.StockCount{ID: 9, Number: "SO-2026-00009", OutletID: 1, SnapshotAt: snapshot, Lines: []model.StockCountLine{line}}
+
+			settleCountLines(count, tt.moved)
+			got := count.Lines[0]
+			if got.MovedQuantity != tt.wantMoved {
+				t.Errorf("movedQuantity = %v, want %v", got.MovedQuantity, tt.wantMoved)
+			}
+			switch {
+			case tt.wantVariance == nil && got.Variance != nil:
+				t.Errorf("variance = %v, want nil", *got.Variance)
+			case tt.wantVariance != nil && (got.Variance == nil || *got.Variance != *tt.wantVariance):
+				t.Errorf("variance = %v, want %v", got.Variance, *tt.wantVariance)
+			}
+
+			// Stok akhir = stok cache saat approve + adjustment = jumlah yang dihitung
+			level := tt.expected
+			for _, movement := range tt.moved {
+				if movement.ProductID == 1 {
+					level += movement.Quantity
+				}
+			}
+			adjustments := stockCountAdjustments(count, "approver")
+			for _, adjustment := range adjustments {
+				if adjustment.Type != model.StockMovementAdjustment || !adjustment.OccurredAt.Equal(countedAt) {
+					t.Errorf("unexpected adjustment %+v", adjustment)
+				}
+				level += adjustment.Quantity
+			}
+			if tt.counted != nil {
+				var movedAfter float64
+				for _, movement := range tt.moved {
+					if movement.ProductID == 1 && movement.CreatedAt.After(countedAt) {
+						movedAfter += movement.Quantity
+					}
+				}
+				if want := *tt.counted + movedAfter; level != want {
+					t.Errorf("stock level after approval = %v, want %v", level, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"BackendFramework/internal/database"
+	"BackendFramework/internal/model"
+)
+
+var (
+	ErrStockTransferNotFound     = errors.New("stock transfer not found")
+	ErrStockTransferStatus       = errors.New("stock transfer status does not allow this action")
+	ErrStockTransferLineNotFound = errors.New("stock transfer line not found")
+	ErrStockTransferEmptyReceipt = errors.New("receipt has no quantities; send lines or close the transfer")
+)
+
+const defaultStockTransferPageSize = 20
+
+type StockTransferService struct{}
+
+func NewStockTransferService() *StockTransferService {
+	return &StockTransferService{}
+}
+
+// GetTransfers lists transfers where either side is visible to the caller.
+func (s *StockTransferService) GetTransfers(params model.StockTransferListQuery, scope model.OutletScope) (*model.StockTransferListResult, error) {
+	query := scopeTransfers(database.DbCore.Model(&model.StockTransfer{}), scope)
+	if params.OutletID != 0 {
+		query = query.Where("from_outlet_id = ? OR to_outlet_id = ?", params.OutletID, params.OutletID)
+	}
+	if params.Status != "" {
+		query = query.Where("status = ?", params.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultStockTransferPageSize
+	}
+
+	transfers := []model.StockTransfer{}
+	err := query.Preload("Lines").Order("id DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&transfers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StockTransferListResult{
+		Transfers: transfers,
+		Pagination: model.Pagination{
+			Page:       page,
+			PageSize:   pageSize,
+			Total:      total,
+			TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+		},
+	}, nil
+}
+
+func (s *StockTransferService) GetTransfer(id uint, scope model.OutletScope) (*model.StockTransfer, error) {
+	var transfer model.StockTransfer
+	err := scopeTransfers(database.DbCore.Model(&model.StockTransfer{}), scope).
+		Preload("Lines").
+		First(&transfer, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStockTransferNotFound
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// CreateTransfer saves a draft. Only the sending outlet has to be in the caller's
+// scope; stock may be lent to any outlet.
+func (s *StockTransferService) CreateTransfer(req model.StockTransferRequest, scope model.OutletScope, actorID string) (*model.StockTransfer, error) {
+	var transfer model.StockTransfer
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := checkTransferOutlets(tx, req, scope); err != nil {
+			return err
+		}
+		lines, err := transferLines(tx, req.Lines)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		seq, err := nextDocumentNumber(tx, fmt.Sprintf("%s:%d", model.StockReferenceTransfer, now.Year()))
+		if err != nil {
+			return err
+		}
+
+		transfer = model.StockTransfer{
+			Number:       fmt.Sprintf("TRF-%d-%05d", now.Year(), seq),
+			FromOutletID: req.FromOutletID,
+			ToOutletID:   req.ToOutletID,
+			Status:       model.StockTransferDraft,
+			Note:         req.Note,
+			CreatedBy:    actorID,
+			Lines:        lines,
+		}
+		return tx.Create(&transfer).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// UpdateTransfer replaces outlets, note and lines of a draft.
+func (s *StockTransferService) UpdateTransfer(id uint, req model.StockTransferRequest, scope model.OutletScope) (*model.StockTransfer, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransfer(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != model.StockTransferDraft {
+			return ErrStockTransferStatus
+		}
+		if err := checkTransferOutlets(tx, req, scope); err != nil {
+			return err
+		}
+		lines, err := transferLines(tx, req.Lines)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("transfer_id = ?", transfer.ID).Delete(&model.StockTransferLine{}).Error; err != nil {
+			return err
+		}
+		for i := range lines {
+			lines[i].TransferID = transfer.ID
+		}
+		if err := tx.Create(&lines).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(transfer).Updates(map[string]interface{}{
+			"from_outlet_id": req.FromOutletID,
+			"to_outlet_id":   req.ToOutletID,
+			"note":           req.Note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(id, scope)
+}
+
+// SendTransfer marks a draft as dispatched. Stock stays on the sender's books
+// until the receiving outlet confirms it.
+func (s *StockTransferService) SendTransfer(id uint, scope model.OutletScope, actorID string) (*model.StockTransfer, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransfer(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != model.StockTransferDraft {
+			return ErrStockTransferStatus
+		}
+		if err := checkOutletInScope(tx, transfer.FromOutletID, scope); err != nil {
+			return err
+		}
+
+		return tx.Model(transfer).Updates(map[string]interface{}{
+			"status":  model.StockTransferSent,
+			"sent_by": actorID,
+			"sent_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(id, scope)
+}
+
+// ReceiveTransfer books a (partial) receipt. Each received quantity leaves the
+// sender and enters the receiver in the same transaction. Once everything has
+// arrived, or the receiver closes the transfer, the per-line discrepancy is
+// recorded and any shortfall is written off as waste at the sending outlet.
+func (s *StockTransferService) ReceiveTransfer(id uint, req model.StockTransferReceiptRequest, scope model.OutletScope, actorID string) (*model.StockTransfer, error) {
+	if len(req.Lines) == 0 && !req.Close {
+		return nil, ErrStockTransferEmptyReceipt
+	}
+
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransfer(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != model.StockTransferSent && transfer.Status != model.StockTransferPartiallyReceived {
+			return ErrStockTransferStatus
+		}
+		if err := checkOutletInScope(tx, transfer.ToOutletID, scope); err != nil {
+			return err
+		}
+
+		lines := map[uint]*model.StockTransferLine{}
+		for i := range transfer.Lines {
+			lines[transfer.Lines[i].ID] = &transfer.Lines[i]
+		}
+
+		now := time.Now()
+		movements := []model.StockMovement{}
+		for _, received := range req.Lines {
+			line, ok := lines[received.LineID]
+			if !ok {
+				return ErrStockTransferLineNotFound
+			}
+			if received.Quantity == 0 {
+				continue
+			}
+			line.QuantityReceived += received.Quantity
+			movements = append(movements,
+				transferMovement(transfer, line, transfer.FromOutletID, model.StockMovementTransfer, -received.Quantity, actorID, now),
+				transferMovement(transfer, line, transfer.ToOutletID, model.StockMovementTransfer, received.Quantity, actorID, now),
+			)
+		}
+
+		complete := req.Close
+		if !complete {
+			complete = true
+			for _, line := range transfer.Lines {
+				if line.QuantityReceived < line.QuantitySent {
+					complete = false
+					break
+				}
+			}
+		}
+
+		for i := range transfer.Lines {
+			line := &transfer.Lines[i]
+			updates := map[string]interface{}{"quantity_received": line.QuantityReceived}
+			if complete {
+				discrepancy := line.QuantitySent - line.QuantityReceived
+				line.Discrepancy = &discrepancy
+				updates["discrepancy"] = discrepancy
+				if discrepancy > 0 {
+					movements = append(movements, transferMovement(transfer, line, transfer.FromOutletID, model.StockMovementWaste, -discrepancy, actorID, now))
+				}
+			}
+			if err := tx.Model(line).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := PostMovements(tx, movements); err != nil {
+			return err
+		}
+
+		status := map[string]interface{}{"status": model.StockTransferPartiallyReceived}
+		if complete {
+			status = map[string]interface{}{
+				"status":      model.StockTransferReceived,
+				"received_by": actorID,
+				"received_at": now,
+			}
+		}
+		return tx.Model(transfer).Updates(status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(id, scope)
+}
+
+// CancelTransfer cancels a draft. The document is kept so its number is not reused.
+func (s *StockTransferService) CancelTransfer(id uint, scope model.OutletScope) (*model.StockTransfer, error) {
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		transfer, err := lockTransfer(tx, id, scope)
+		if err != nil {
+			return err
+		}
+		if transfer.Status != model.StockTransferDraft {
+			return ErrStockTransferStatus
+		}
+		return tx.Model(transfer).Update("status", model.StockTransferCancelled).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(id, scope)
+}
+
+// lockTransfer loads a transfer with its lines and holds a row lock on it until tx ends.
+func lockTransfer(tx *gorm.DB, id uint, scope model.OutletScope) (*model.StockTransfer, error) {
+	var transfer model.StockTransfer
+	err := scopeTransfers(tx.Model(&model.StockTransfer{}), scope).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").
+		First(&transfer, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStockTransferNotFound
+		}
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func checkTransferOutlets(tx *gorm.DB, req model.StockTransferRequest, scope model.OutletScope) error {
+	if err := checkOutletInScope(tx, req.FromOutletID, scope); err != nil {
+		return err
+	}
+	return checkOutletInScope(tx, req.ToOutletID, model.OutletScope{})
+}
+
+func transferLines(tx *gorm.DB, inputs []model.StockTransferLineInput) ([]model.StockTransferLine, error) {
+	lines := []model.StockTransferLine{}
+	for _, input := range inputs {
+		if _, err := findProductVariant(tx, input.ProductID, input.VariantID); err != nil {
+			return nil, err
+		}
+		lines = append(lines, model.StockTransferLine{
+			ProductID:    input.ProductID,
+			VariantID:    input.VariantID,
+			QuantitySent: input.Quantity,
+		})
+	}
+	return lines, nil
+}
+
+func transferMovement(transfer *model.StockTransfer, line *model.StockTransferLine, outletID uint, movementType string, quantity float64, actorID string, at time.Time) model.StockMovement {
+	return model.StockMovement{
+		OutletID:      outletID,
+		ProductID:     line.ProductID,
+		VariantID:     line.VariantID,
+		Type:          movementType,
+		Quantity:      quantity,
+		ReferenceType: model.StockReferenceTransfer,
+		ReferenceID:   transfer.ID,
+		Note:          transfer.Number,
+		ActorID:       actorID,
+		OccurredAt:    at,
+	}
+}
+
+// scopeTransfers keeps transfers where the sending or receiving outlet is in scope.
+func scopeTransfers(db *gorm.DB, scope model.OutletScope) *gorm.DB {
+	if !scope.Restricted {
+		return db
+	}
+	outlets := scopeOutlets(database.DbCore.Model(&model.Outlet{}).Select("id"), scope)
+	return db.Where("from_outlet_id IN (?) OR to_outlet_id IN (?)", outlets, outlets)
+}
@@ -1,13 +1,22 @@
 package service
 
 import (
+	"BackendFramework/internal/config"
 	"BackendFramework/internal/database"
 	"BackendFramework/internal/middleware"
 	"BackendFramework/internal/model"
+	"errors"
 	"fmt"
 	"strconv"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+// ErrUserChangeForbidden: user biasa hanya boleh mengubah profilnya sendiri dan
+// tidak boleh mengubah grup atau status aktif
+var ErrUserChangeForbidden = errors.New("only admins can change other users, their group or active status")
+
 func GetAllUsers() []model.UserList {
 	var users []model.User
 	var userList []model.UserList
@@ -44,6 +53,7 @@ func GetOneUser(userId string) *model.UserList {
 		Email:    user.Email,
 		Group:    fmt.Sprintf("%d", user.Group),
 		IsAktif:  user.IsAktif,
+		Version:  user.Version,
 	}
 }
 
@@ -95,7 +105,7 @@ func GetUsersByGroup(group string) []model.UserList {
 }
 
 // Untuk UserInput
-func InsertUser(userData *model.UserInput) (bool, error) {
+func InsertUser(userData *model.UserInput, actorID string) (bool, error) {
 	// Set default jika kosong
 	if userData.IsAktif == "" {
 		userData.IsAktif = "active"
@@ -112,12 +122,19 @@ func InsertUser(userData *model.UserInput) (bool, error) {
 		Password:  userData.Password,
 		Group:     userData.Group,
 		IsAktif:   userData.IsAktif,
+		OutletID:  userData.OutletID,
+		Version:   1,
 	}
 	
-	result := database.DbCore.Create(&user)
-	if result.Error != nil {
-		middleware.LogError(result.Error, "Insert Data Failed")
-		return false, result.Error
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return RecordAudit(tx, model.AuditEntityUser, userEntityID(user.ID), model.AuditActionCreate, actorID, nil, user)
+	})
+	if err != nil {
+		middleware.LogError(err, "Insert Data Failed")
+		return false, err
 	}
 	
 	fmt.Printf("User %s created successfully with ID: %d\n", user.Username, user.ID)
@@ -140,17 +157,23 @@ func InsertUserFromRegister(registerData *model.RegisterInput) (bool, error) {
 		Address:             registerData.Address,
 		Phone:               registerData.Phone,
 		Password:            registerData.Password,
-		Group:               registerData.Group,
+		Group:               config.GROUP_USER, // grup hanya bisa diubah admin
 		IsAktif:             "active", 
 		AgreeTerms:          registerData.AgreeTerms,
 		SubscribeNewsletter: registerData.SubscribeNewsletter,
+		Version:             1,
 	}
 	
-	// Create user dengan GORM
-	result := database.DbCore.Create(&user)
-	if result.Error != nil {
-		middleware.LogError(result.Error, "Insert Registration Failed")
-		return false, result.Error
+	// Create user dengan GORM, user yang mendaftar sendiri dicatat sebagai pelakunya
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return RecordAudit(tx, model.AuditEntityUser, userEntityID(user.ID), model.AuditActionCreate, user.Username, nil, user)
+	})
+	if err != nil {
+		middleware.LogError(err, "Insert Registration Failed")
+		return false, err
 	}
 	
 	fmt.Printf("User %s registered successfully with ID: %d\n", user.Username, user.ID)
@@ -158,7 +181,12 @@ func InsertUserFromRegister(registerData *model.RegisterInput) (bool, error) {
 }
 
 // UPDATED: UpdateUser now accepts usrId parameter
-func UpdateUser(usrId string, userData *model.UserInput) (bool, error) {
+func UpdateUser(usrId string, userData *model.UserInput, expectedVersion uint, actorID string) (bool, error) {
+	password, err := hashUserPassword(userData.Password)
+	if err != nil {
+		return false, err
+	}
+
 	updates := model.User{
 		FirstName: userData.FirstName,
 		LastName:  userData.LastName,
@@ -167,24 +195,81 @@ func UpdateUser(usrId string, userData *model.UserInput) (bool, error) {
 		Phone:     userData.Phone,
 		Group:     userData.Group,
 		IsAktif:   userData.IsAktif,
-		Password:  userData.Password,
+		OutletID:  userData.OutletID,
+		Password:  password,
 	}
 	
-	result := database.DbCore.Model(&model.User{}).Where("username = ?", usrId).Updates(updates)
-	if result.Error != nil {
-		middleware.LogError(result.Error, "Update Data Failed")
-		return false, result.Error
+	found, err := updateUserWithAudit(usrId, updates, expectedVersion, actorID)
+	if err != nil {
+		middleware.LogError(err, "Update Data Failed")
+		return false, err
 	}
 	
-	if result.RowsAffected == 0 {
+	if !found {
 		return false, fmt.Errorf("user with ID %s not found", usrId)
 	}
 	
 	return true, nil
 }
 
+// PatchUser hanya mengubah field yang dikirim (pointer non-nil)
+func PatchUser(usrId string, userData *model.UserPatchInput, expectedVersion uint, actorID string) (bool, error) {
+	var actor model.User
+	if err := database.DbCore.Select("username", "group").Where("username = ?", actorID).First(&actor).Error; err != nil {
+		return false, ErrUserChangeForbidden
+	}
+	if actor.Group != config.GROUP_ADMIN && (usrId != actor.Username || userData.Group != nil || userData.IsAktif != nil) {
+		return false, ErrUserChangeForbidden
+	}
+
+	updates := model.User{}
+	if userData.FirstName != nil {
+		updates.FirstName = *userData.FirstName
+	}
+	if userData.LastName != nil {
+		updates.LastName = *userData.LastName
+	}
+	if userData.Email != nil {
+		updates.Email = *userData.Email
+	}
+	if userData.Address != nil {
+		updates.Address = *userData.Address
+	}
+	if userData.Phone != nil {
+		updates.Phone = *userData.Phone
+	}
+	if userData.Group != nil {
+		updates.Group = *userData.Group
+	}
+	if userData.IsAktif != nil {
+		updates.IsAktif = *userData.IsAktif
+	}
+	if userData.OutletID != nil {
+		updates.OutletID = userData.OutletID
+	}
+	if userData.Password != nil {
+		password, err := hashUserPassword(*userData.Password)
+		if err != nil {
+			return false, err
+		}
+		updates.Password = password
+	}
+
+	found, err := updateUserWithAudit(usrId, updates, expectedVersion, actorID)
+	if err != nil {
+		middleware.LogError(err, "Patch Data Failed")
+		return false, err
+	}
+
+	if !found {
+		return false, fmt.Errorf("user with ID %s not found", usrId)
+	}
+
+	return true, nil
+}
+
 // NEW: Update user in group
-func UpdateUserInGroup(usrId string, userData *model.UserInput) (bool, error) {
+func UpdateUserInGroup(usrId string, userData *model.UserInput, expectedVersion uint, actorID string) (bool, error) {
 	// Check if user exists
 	var existingUser model.User
 	result := database.DbCore.Where("username = ?", usrId).First(&existingUser)
@@ -193,6 +278,11 @@ func UpdateUserInGroup(usrId string, userData *model.UserInput) (bool, error) {
 		return false, fmt.Errorf("user with ID %s not found", usrId)
 	}
 	
+	password, err := hashUserPassword(userData.Password)
+	if err != nil {
+		return false, err
+	}
+
 	updates := model.User{
 		FirstName: userData.FirstName,
 		LastName:  userData.LastName,
@@ -201,27 +291,29 @@ func UpdateUserInGroup(usrId string, userData *model.UserInput) (bool, error) {
 		Phone:     userData.Phone,
 		Group:     userData.Group,
 		IsAktif:   userData.IsAktif,
-		Password:  userData.Password,
+		OutletID:  userData.OutletID,
+		Password:  password,
 	}
 	
-	result = database.DbCore.Model(&model.User{}).Where("username = ?", usrId).Updates(updates)
-	if result.Error != nil {
-		middleware.LogError(result.Error, "Update User in Group Failed")
-		return false, result.Error
+	// Versi dari If-Match klien, bukan versi yang baru dibaca, supaya
+	// perubahan orang lain di antaranya tidak tertimpa
+	if _, err := updateUserWithAudit(usrId, updates, expectedVersion, actorID); err != nil {
+		middleware.LogError(err, "Update User in Group Failed")
+		return false, err
 	}
 	
 	fmt.Printf("User %s updated successfully in group management\n", usrId)
 	return true, nil
 }
 
-func DeleteUser(userId string) (bool, error) {
-	result := database.DbCore.Where("username = ?", userId).Delete(&model.User{})
-	if result.Error != nil {
-		middleware.LogError(result.Error, "Delete Data Failed")
-		return false, result.Error
+func DeleteUser(userId string, actorID string) (bool, error) {
+	found, err := deleteUserWithAudit(userId, actorID)
+	if err != nil {
+		middleware.LogError(err, "Delete Data Failed")
+		return false, err
 	}
 	
-	if result.RowsAffected == 0 {
+	if !found {
 		return false, fmt.Errorf("user with ID %s not found", userId)
 	}
 	
@@ -229,7 +321,7 @@ func DeleteUser(userId string) (bool, error) {
 }
 
 // NEW: Delete user from group
-func DeleteUserFromGroup(usrId, group string) (bool, error) {
+func DeleteUserFromGroup(usrId, group string, actorID string) (bool, error) {
 	// Check if user exists
 	var existingUser model.User
 	result := database.DbCore.Where("username = ?", usrId).First(&existingUser)
@@ -251,12 +343,97 @@ func DeleteUserFromGroup(usrId, group string) (bool, error) {
 	}
 	
 	// Delete user
-	result = database.DbCore.Where("username = ?", usrId).Delete(&model.User{})
-	if result.Error != nil {
-		middleware.LogError(result.Error, "Delete User from Group Failed")
-		return false, result.Error
+	if _, err := deleteUserWithAudit(usrId, actorID); err != nil {
+		middleware.LogError(err, "Delete User from Group Failed")
+		return false, err
 	}
 	
 	fmt.Printf("User %s deleted successfully from group %s\n", usrId, group)
 	return true, nil
-}
\ No newline at end of file
+}
+
+// Riwayat perubahan user, dicari lewat username tapi disimpan per ID
+// supaya tetap utuh walaupun user sudah dihapus
+func GetUserHistory(usrId string) ([]model.AuditLog, error) {
+	var user model.User
+	result := database.DbCore.Unscoped().Where("username = ?", usrId).First(&user)
+	if result.Error != nil {
+		middleware.LogError(result.Error, "Data Not Found")
+		return nil, fmt.Errorf("user with ID %s not found", usrId)
+	}
+
+	return NewAuditService().GetHistory(model.AuditEntityUser, userEntityID(user.ID))
+}
+
+// updateUserWithAudit hanya menulis kalau versi di database masih expectedVersion
+func updateUserWithAudit(usrId string, updates model.User, expectedVersion uint, actorID string) (bool, error) {
+	found := false
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var before model.User
+		result := tx.Where("username = ?", usrId).Limit(1).Find(&before)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		found = true
+		if before.Version != expectedVersion {
+			return ErrVersionConflict
+		}
+
+		updates.Version = expectedVersion + 1
+		result = tx.Model(&model.User{}).Where("id = ? AND version = ?", before.ID, expectedVersion).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		var after model.User
+		if err := tx.First(&after, before.ID).Error; err != nil {
+			return err
+		}
+
+		// Password tidak pernah ikut di JSON, cukup dicatat bahwa password diganti
+		var extra []model.AuditChange
+		if updates.Password != "" && updates.Password != before.Password {
+			extra = append(extra, model.AuditChange{Field: "password", Before: "[redacted]", After: "[redacted]"})
+		}
+		return RecordAudit(tx, model.AuditEntityUser, userEntityID(before.ID), model.AuditActionUpdate, actorID, before, after, extra...)
+	})
+	return found, err
+}
+
+func deleteUserWithAudit(usrId string, actorID string) (bool, error) {
+	found := false
+	err := database.DbCore.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		result := tx.Where("username = ?", usrId).Limit(1).Find(&user)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		found = true
+
+		if err := tx.Delete(&user).Error; err != nil {
+			return err
+		}
+		return RecordAudit(tx, model.AuditEntityUser, userEntityID(user.ID), model.AuditActionDelete, actorID, user, nil)
+	})
+	return found, err
+}
+
+// hashUserPassword meng-hash password baru dengan bcrypt seperti saat register.
+// Password kosong berarti tidak diganti.
+func hashUserPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func userEntityID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,7 @@
+package service
+
+import "errors"
+
+// ErrVersionConflict means the row changed since the client read it; the
+// client has to fetch the current version and reapply its edit.
+var ErrVersionConflict = errors.New("resource was modified by another request")
@@ -1,7 +1,9 @@
 package thirdparty
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"time"
 	"os"
 
@@ -90,4 +92,54 @@ func UploadFileBucket(localFilePath, filename string) (string, error) {
 		return "", fmt.Errorf("failed to get file from s3 : %v", err)
 	}
 	return s3Url, nil
-}
\ No newline at end of file
+}
+// UploadBytesBucket stores an in-memory file (e.g. a generated PDF) under key.
+func UploadBytesBucket(data []byte, key, contentType string) error {
+	sess := newSession()
+	s3Client := s3.New(sess)
+
+	_, err := s3Client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(config.AWS_BUCKET_NAME),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload file to S3: %v", err)
+	}
+	return nil
+}
+
+func DownloadFileBucket(fileLoc string) ([]byte, error) {
+	sess := newSession()
+	s3Client := s3.New(sess)
+
+	result, err := s3Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(config.AWS_BUCKET_NAME),
+		Key:    aws.String(fileLoc),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file from S3: %v", err)
+	}
+	defer result.Body.Close()
+
+	data, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from S3: %v", err)
+	}
+	return data, nil
+}
+
+func DeleteFileBucket(fileLoc string) error {
+	sess := newSession()
+	s3Client := s3.New(sess)
+
+	_, err := s3Client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(config.AWS_BUCKET_NAME),
+		Key:    aws.String(fileLoc),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete file from S3: %v", err)
+	}
+	return nil
+}
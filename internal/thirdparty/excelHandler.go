@@ -105,6 +105,10 @@ func ReadExcelFile(sheetName,filePath string) ([]Header, []map[string]interface{
         return nil, nil, false
     }
 
+    if len(rows) == 0 {
+        return []Header{}, []map[string]interface{}{}, true
+    }
+
     // Extract headers and their widths.
     var headers []Header
     for colIndex, headerText := range rows[0] {
@@ -121,7 +125,12 @@ func ReadExcelFile(sheetName,filePath string) ([]Header, []map[string]interface{
     for rowIndex := 1; rowIndex < len(rows); rowIndex++ {
         rowData := make(map[string]interface{})
         for colIndex, header := range headers {
-            rowData[header.Text] = rows[rowIndex][colIndex]
+            // GetRows memotong sel kosong di ujung baris
+            value := ""
+            if colIndex < len(rows[rowIndex]) {
+                value = rows[rowIndex][colIndex]
+            }
+            rowData[header.Text] = value
         }
         data = append(data, rowData)
     }
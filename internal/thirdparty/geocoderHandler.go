@@ -0,0 +1,108 @@
+package thirdparty
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"BackendFramework/internal/config"
+)
+
+var ErrAddressNotFound = errors.New("address not found")
+
+// Geocoder converts a free-text address into coordinates.
+type Geocoder interface {
+	Geocode(address string) (lat float64, lng float64, err error)
+}
+
+// NewGeocoder returns the geocoder selected by GEOCODER_PROVIDER, or nil when
+// geocoding is switched off.
+func NewGeocoder() Geocoder {
+	switch config.GEOCODER_PROVIDER {
+	case "nominatim":
+		return &NominatimGeocoder{
+			BaseURL:   config.GEOCODER_URL,
+			UserAgent: config.GEOCODER_USER_AGENT,
+			Client:    &http.Client{Timeout: 10 * time.Second},
+		}
+	case "stub":
+		return &StubGeocoder{}
+	default:
+		return nil
+	}
+}
+
+// NominatimGeocoder talks to an OpenStreetMap Nominatim compatible /search endpoint.
+type NominatimGeocoder struct {
+	BaseURL   string
+	UserAgent string
+	Client    *http.Client
+}
+
+func (g *NominatimGeocoder) Geocode(address string) (float64, float64, error) {
+	baseURL := g.BaseURL
+	if baseURL == "" {
+		baseURL = "https://nominatim.openstreetmap.org"
+	}
+	query := url.Values{}
+	query.Set("q", address)
+	query.Set("format", "json")
+	query.Set("limit", "1")
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(baseURL, "/")+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	if g.UserAgent != "" {
+		req.Header.Set("User-Agent", g.UserAgent)
+	}
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to call geocoder: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, fmt.Errorf("geocoder returned status %d", resp.StatusCode)
+	}
+
+	var results []struct {
+		Lat string `json:"lat"`
+		Lon string `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode geocoder response: %v", err)
+	}
+	if len(results) == 0 {
+		return 0, 0, ErrAddressNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return lat, lng, nil
+}
+
+// StubGeocoder answers from a fixed address table, for local development and tests.
+type StubGeocoder struct {
+	Results map[string][2]float64
+}
+
+func (g *StubGeocoder) Geocode(address string) (float64, float64, error) {
+	coordinates, ok := g.Results[strings.ToLower(strings.TrimSpace(address))]
+	if !ok {
+		return 0, 0, ErrAddressNotFound
+	}
+	return coordinates[0], coordinates[1], nil
+}
@@ -0,0 +1,37 @@
+package thirdparty
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStubGeocoder(t *testing.T) {
+	geocoder := &StubGeocoder{Results: map[string][2]float64{
+		"jl. sudirman no. 1, jakarta": {-6.2088, 106.8229},
+	}}
+
+	tests := []struct {
+		name    string
+		address string
+		wantLat float64
+		wantLng float64
+		wantErr error
+	}{
+		{name: "exact match", address: "jl. sudirman no. 1, jakarta", wantLat: -6.2088, wantLng: 106.8229},
+		{name: "case and surrounding spaces are ignored", address: "  Jl. Sudirman No. 1, Jakarta ", wantLat: -6.2088, wantLng: 106.8229},
+		{name: "unknown address", address: "Jl. Thamrin No. 2, Jakarta", wantErr: ErrAddressNotFound},
+		{name: "empty address", address: "", wantErr: ErrAddressNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat, lng, err := geocoder.Geocode(tt.address)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if lat != tt.wantLat || lng != tt.wantLng {
+				t.Errorf("coordinates = (%v, %v), want (%v, %v)", lat, lng, tt.wantLat, tt.wantLng)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package thirdparty
 
 import(
+	"io"
+
 	"gopkg.in/gomail.v2"
 
 	"BackendFramework/internal/config"
@@ -12,7 +14,18 @@ type RecipientStruct struct {
     Email 	string   `json:"Email"`
 }
 
+// AttachmentStruct adalah file di memori (mis. PDF hasil render) yang dilampirkan ke email
+type AttachmentStruct struct {
+	Filename string
+	Data     []byte
+}
+
 func SendEmail(mailBody,mailSubject string, recipientData []RecipientStruct) bool {
+	return SendEmailWithAttachments(mailBody, mailSubject, recipientData, nil)
+}
+
+// SendEmailWithAttachments works like SendEmail and attaches the in-memory files.
+func SendEmailWithAttachments(mailBody, mailSubject string, recipientData []RecipientStruct, attachments []AttachmentStruct) bool {
 	mailer := gomail.NewMessage()
 	mailer.SetHeader("From", config.CONFIG_SENDER_NAME)
 	addresses := make([]string, len(recipientData))
@@ -23,6 +36,13 @@ func SendEmail(mailBody,mailSubject string, recipientData []RecipientStruct) boo
     mailer.SetHeader("Subject", mailSubject)
     mailer.Embed("./web/assets/uib_logo_putih2.png")
 	mailer.SetBody("text/html", mailBody)
+	for _, attachment := range attachments {
+		data := attachment.Data
+		mailer.Attach(attachment.Filename, gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		}))
+	}
 
 	dialer := gomail.NewDialer(
         config.CONFIG_SMTP_HOST,
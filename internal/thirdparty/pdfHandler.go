@@ -34,4 +34,24 @@ func GeneratePdf(pdfBody, outputPath string) bool {
 		return false
 	}
 	return true
-}
\ No newline at end of file
+}
+
+// GeneratePdfBytes renders HTML to PDF in memory instead of writing a file.
+func GeneratePdfBytes(pdfBody string) ([]byte, error) {
+	pdfg, err := wkhtmltopdf.NewPDFGenerator()
+	if err != nil {
+		middleware.LogError(err, "Failed To Create Pdf Generator")
+		return nil, err
+	}
+
+	pdfg.AddPage(wkhtmltopdf.NewPageReader(strings.NewReader(pdfBody)))
+	pdfg.Orientation.Set(wkhtmltopdf.OrientationPortrait)
+	pdfg.PageSize.Set(wkhtmltopdf.PageSizeA4)
+	pdfg.Dpi.Set(300)
+
+	if err := pdfg.Create(); err != nil {
+		middleware.LogError(err, "Pdf Creation Falied")
+		return nil, err
+	}
+	return pdfg.Bytes(), nil
+}
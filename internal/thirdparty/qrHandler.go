@@ -1,6 +1,9 @@
 package thirdparty
 
 import(
+	"bytes"
+	"fmt"
+
 	"github.com/skip2/go-qrcode"
 	
 	"BackendFramework/internal/middleware"
@@ -22,4 +25,39 @@ func GenerateQrFile(qrContent, outputPath string) bool {
 	}
 
 	return true
-}
\ No newline at end of file
+}
+
+// GenerateQrPng renders a QR code as a size x size PNG in memory.
+func GenerateQrPng(qrContent string, size int) ([]byte, error) {
+	qr, err := qrcode.New(qrContent, qrcode.Medium)
+	if err != nil {
+		middleware.LogError(err, "Failed To Create Qr Generator")
+		return nil, err
+	}
+	return qr.PNG(size)
+}
+
+// GenerateQrSvg renders a QR code as an SVG of size x size pixels, one rect per dark module.
+func GenerateQrSvg(qrContent string, size int) ([]byte, error) {
+	qr, err := qrcode.New(qrContent, qrcode.Medium)
+	if err != nil {
+		middleware.LogError(err, "Failed To Create Qr Generator")
+		return nil, err
+	}
+
+	bitmap := qr.Bitmap()
+	modules := len(bitmap)
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(buf, `<rect width="%d" height="%d" fill="#ffffff"/>`, modules, modules)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(buf, `<rect x="%d" y="%d" width="1" height="1" fill="#000000"/>`, x, y)
+			}
+		}
+	}
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}